@@ -24,13 +24,15 @@ type PushActorImpl struct {
 	appBitsRepo application_bits.ApplicationBitsRepository
 	appfiles    app_files.AppFiles
 	zipper      app_files.Zipper
+	gitExporter app_files.GitExporter
 }
 
-func NewPushActor(appBitsRepo application_bits.ApplicationBitsRepository, zipper app_files.Zipper, appfiles app_files.AppFiles) PushActor {
+func NewPushActor(appBitsRepo application_bits.ApplicationBitsRepository, zipper app_files.Zipper, appfiles app_files.AppFiles, gitExporter app_files.GitExporter) PushActor {
 	return PushActorImpl{
 		appBitsRepo: appBitsRepo,
 		appfiles:    appfiles,
 		zipper:      zipper,
+		gitExporter: gitExporter,
 	}
 }
 
@@ -67,25 +69,36 @@ func (actor PushActorImpl) GatherFiles(appDir string, uploadDir string) ([]resou
 		hasFileToUpload  bool
 	)
 
-	if actor.zipper.IsZipFile(appDir) {
-		var handleZipErr error
+	var extract func(tmpDir string) error
+	if app_files.IsGitSource(appDir) {
+		extract = func(tmpDir string) error {
+			return actor.gitExporter.Export(appDir, tmpDir)
+		}
+	} else if actor.zipper.IsZipFile(appDir) {
+		extract = func(tmpDir string) error {
+			return actor.zipper.Unzip(appDir, tmpDir)
+		}
+	}
+
+	if extract != nil {
+		var extractErr error
 		fileutils.TempDir("unzipped-app", func(tmpDir string, err error) {
 			if err != nil {
-				handleZipErr = err
+				extractErr = err
 				return
 			}
 
-			err = actor.zipper.Unzip(appDir, tmpDir)
+			err = extract(tmpDir)
 			if err != nil {
-				handleZipErr = err
+				extractErr = err
 				return
 			}
 
 			presentFiles, hasFileToUpload, processAppDirErr = processAppDir(tmpDir)
 		})
 
-		if handleZipErr != nil {
-			return []resources.AppFileResource{}, false, handleZipErr
+		if extractErr != nil {
+			return []resources.AppFileResource{}, false, extractErr
 		}
 	} else {
 		presentFiles, hasFileToUpload, processAppDirErr = processAppDir(appDir)
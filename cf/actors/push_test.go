@@ -22,6 +22,7 @@ var _ = Describe("Push Actor", func() {
 		appBitsRepo  *fakeBits.FakeApplicationBitsRepository
 		appFiles     *fakes.FakeAppFiles
 		zipper       *fakes.FakeZipper
+		gitExporter  *fakes.FakeGitExporter
 		actor        actors.PushActor
 		fixturesDir  string
 		appDir       string
@@ -33,7 +34,8 @@ var _ = Describe("Push Actor", func() {
 		appBitsRepo = &fakeBits.FakeApplicationBitsRepository{}
 		appFiles = &fakes.FakeAppFiles{}
 		zipper = &fakes.FakeZipper{}
-		actor = actors.NewPushActor(appBitsRepo, zipper, appFiles)
+		gitExporter = &fakes.FakeGitExporter{}
+		actor = actors.NewPushActor(appBitsRepo, zipper, appFiles, gitExporter)
 		fixturesDir = filepath.Join("..", "..", "fixtures", "applications")
 	})
 
@@ -116,6 +118,48 @@ var _ = Describe("Push Actor", func() {
 
 		})
 
+		Context("when the input is a git repository", func() {
+			var gitSource string
+
+			BeforeEach(func() {
+				gitSource = "git+https://example.com/app.git#v1.2.3"
+
+				gitExporter.ExportStub = func(source string, dest string) error {
+					err := os.Mkdir(filepath.Join(dest, "example-app"), os.ModeDir|os.ModePerm)
+					Expect(err).NotTo(HaveOccurred())
+
+					return ioutil.WriteFile(filepath.Join(dest, "example-app/ignore-me"), []byte("This is a test file"), os.ModePerm)
+				}
+			})
+
+			It("exports the tree of the ref", func() {
+				fileutils.TempDir("gather-files", func(tmpDir string, err error) {
+					Expect(err).NotTo(HaveOccurred())
+
+					actualFiles, _, err := actor.GatherFiles(gitSource, tmpDir)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(gitExporter.ExportCallCount()).To(Equal(1))
+					source, _ := gitExporter.ExportArgsForCall(0)
+					Expect(source).To(Equal(gitSource))
+					Expect(zipper.UnzipCallCount()).To(Equal(0))
+
+					Expect(actualFiles).To(HaveLen(1))
+					Expect(actualFiles[0].Path).To(Equal("example-app/ignore-me"))
+				})
+			})
+
+			It("returns an error when the tree cannot be exported", func() {
+				gitExporter.ExportStub = nil
+				gitExporter.ExportReturns(errors.New("unknown revision"))
+
+				fileutils.TempDir("gather-files", func(tmpDir string, err error) {
+					_, _, err = actor.GatherFiles(gitSource, tmpDir)
+					Expect(err).To(MatchError("unknown revision"))
+				})
+			})
+		})
+
 		Context("when the input is a directory full of files", func() {
 			BeforeEach(func() {
 				zipper.IsZipFileReturns(false)
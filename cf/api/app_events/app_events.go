@@ -10,8 +10,12 @@ import (
 
 type AppEventsRepository interface {
 	RecentEvents(appGuid string, limit int64) ([]models.EventFields, error)
+	ListEvents(filter models.EventsFilter, limit int64) ([]models.EventFields, error)
 }
 
+// the largest page the cloud controller returns
+const maxEventsPerPage = 100
+
 type CloudControllerAppEventsRepository struct {
 	config   core_config.Reader
 	gateway  net.Gateway
@@ -38,6 +42,31 @@ func (repo CloudControllerAppEventsRepository) RecentEvents(appGuid string, limi
 	return events, apiErr
 }
 
+// ListEvents returns the events matching filter, newest first, following
+// pagination until limit events were found. A limit of 0 lists every event.
+func (repo CloudControllerAppEventsRepository) ListEvents(filter models.EventsFilter, limit int64) ([]models.EventFields, error) {
+	perPage := int64(maxEventsPerPage)
+	if limit > 0 && limit < perPage {
+		perPage = limit
+	}
+
+	events := []models.EventFields{}
+	apiErr := repo.gateway.ListPaginatedResources(
+		repo.config.ApiEndpoint(),
+		repo.strategy.EventsSearchURL(filter, perPage),
+		repo.strategy.EventsResource(),
+
+		func(resource interface{}) bool {
+			event := resource.(resources.EventResource).ToFields()
+			if filter.Matches(event) {
+				events = append(events, event)
+			}
+			return limit == 0 || int64(len(events)) < limit
+		})
+
+	return events, apiErr
+}
+
 func (repo CloudControllerAppEventsRepository) listEvents(appGuid string, limit int64, cb func(models.EventFields) bool) error {
 	return repo.gateway.ListPaginatedResources(
 		repo.config.ApiEndpoint(),
@@ -3,6 +3,7 @@ package app_events_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"time"
 
 	. "github.com/cloudfoundry/cli/cf/api/app_events"
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
 	testnet "github.com/cloudfoundry/cli/testhelpers/net"
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
 	testtime "github.com/cloudfoundry/cli/testhelpers/time"
@@ -72,8 +74,72 @@ var _ = Describe("App Events Repo", func() {
 			}))
 		})
 	})
+
+	Describe("list events", func() {
+		It("follows pagination and filters the events by actor", func() {
+			setupTestServer(searchEventsPage1Request, searchEventsPage2Request)
+
+			filter := models.EventsFilter{SpaceGuid: "my-space-guid", Types: []string{"audit.app.update"}, Actor: "somebody@pivotallabs.com"}
+			list, err := repo.ListEvents(filter, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(list).To(HaveLen(2))
+			Expect(list[0].Guid).To(Equal("event-1-guid"))
+			Expect(list[0].ActeeType).To(Equal("app"))
+			Expect(list[0].ActeeName).To(Equal("dora"))
+			Expect(list[1].Guid).To(Equal("event-3-guid"))
+			Expect(list[1].Actor).To(Equal("somebody-guid"))
+		})
+
+		It("stops when the limit is reached", func() {
+			request := searchEventsPage1Request
+			request.Path = strings.Replace(request.Path, "results-per-page=100", "results-per-page=1", 1)
+			setupTestServer(request)
+
+			list, err := repo.ListEvents(models.EventsFilter{SpaceGuid: "my-space-guid", Types: []string{"audit.app.update"}}, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(list).To(HaveLen(1))
+		})
+	})
 })
 
+func searchEvent(guid, actorGuid, actorName string) string {
+	return `{
+	  "metadata": {"guid": "` + guid + `"},
+	  "entity": {
+		"type": "audit.app.update",
+		"timestamp": "2014-01-21T00:20:11+00:00",
+		"actor": "` + actorGuid + `",
+		"actor_name": "` + actorName + `",
+		"actee_type": "app",
+		"actee_name": "dora",
+		"metadata": {"request": {"instances": 1}}
+	  }
+	}`
+}
+
+var searchEventsPage1Request = testnet.TestRequest{
+	Method: "GET",
+	Path:   "/v2/events?q=space_guid%3Amy-space-guid&q=type%3Aaudit.app.update&order-direction=desc&results-per-page=100",
+	Response: testnet.TestResponse{
+		Status: http.StatusOK,
+		Body: `{
+		  "next_url": "/v2/events?q=space_guid%3Amy-space-guid&page=2",
+		  "resources": [` + searchEvent("event-1-guid", "somebody-guid", "somebody@pivotallabs.com") + `,` +
+			searchEvent("event-2-guid", "nobody-guid", "nobody@pivotallabs.com") + `]
+		}`}}
+
+var searchEventsPage2Request = testnet.TestRequest{
+	Method: "GET",
+	Path:   "/v2/events?q=space_guid%3Amy-space-guid&page=2",
+	Response: testnet.TestResponse{
+		Status: http.StatusOK,
+		Body: `{
+		  "next_url": null,
+		  "resources": [` + searchEvent("event-3-guid", "somebody-guid", "somebody@pivotallabs.com") + `]
+		}`}}
+
 const eventTimestampFormat = "2006-01-02T15:04:05-07:00"
 
 var eventsRequest = testnet.TestRequest{
@@ -19,6 +19,16 @@ type FakeAppEventsRepository struct {
 		result1 []models.EventFields
 		result2 error
 	}
+	ListEventsStub        func(filter models.EventsFilter, limit int64) ([]models.EventFields, error)
+	listEventsMutex       sync.RWMutex
+	listEventsArgsForCall []struct {
+		filter models.EventsFilter
+		limit  int64
+	}
+	listEventsReturns struct {
+		result1 []models.EventFields
+		result2 error
+	}
 }
 
 func (fake *FakeAppEventsRepository) RecentEvents(appGuid string, limit int64) ([]models.EventFields, error) {
@@ -54,4 +64,37 @@ func (fake *FakeAppEventsRepository) RecentEventsReturns(result1 []models.EventF
 	}{result1, result2}
 }
 
+func (fake *FakeAppEventsRepository) ListEvents(filter models.EventsFilter, limit int64) ([]models.EventFields, error) {
+	fake.listEventsMutex.Lock()
+	defer fake.listEventsMutex.Unlock()
+	fake.listEventsArgsForCall = append(fake.listEventsArgsForCall, struct {
+		filter models.EventsFilter
+		limit  int64
+	}{filter, limit})
+	if fake.ListEventsStub != nil {
+		return fake.ListEventsStub(filter, limit)
+	} else {
+		return fake.listEventsReturns.result1, fake.listEventsReturns.result2
+	}
+}
+
+func (fake *FakeAppEventsRepository) ListEventsCallCount() int {
+	fake.listEventsMutex.RLock()
+	defer fake.listEventsMutex.RUnlock()
+	return len(fake.listEventsArgsForCall)
+}
+
+func (fake *FakeAppEventsRepository) ListEventsArgsForCall(i int) (models.EventsFilter, int64) {
+	fake.listEventsMutex.RLock()
+	defer fake.listEventsMutex.RUnlock()
+	return fake.listEventsArgsForCall[i].filter, fake.listEventsArgsForCall[i].limit
+}
+
+func (fake *FakeAppEventsRepository) ListEventsReturns(result1 []models.EventFields, result2 error) {
+	fake.listEventsReturns = struct {
+		result1 []models.EventFields
+		result2 error
+	}{result1, result2}
+}
+
 var _ AppEventsRepository = new(FakeAppEventsRepository)
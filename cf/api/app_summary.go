@@ -39,6 +39,9 @@ type ApplicationFromSummary struct {
 	Command            string     `json:"command"`
 	PackageUpdatedAt   *time.Time `json:"package_updated_at"`
 	Buildpack          string
+	StackGuid          string `json:"stack_guid"`
+	HealthCheckType    string `json:"health_check_type"`
+	DockerImage        string `json:"docker_image"`
 }
 
 func (resource ApplicationFromSummary) ToFields() (app models.ApplicationFields) {
@@ -56,6 +59,8 @@ func (resource ApplicationFromSummary) ToFields() (app models.ApplicationFields)
 	app.HealthCheckTimeout = resource.HealthCheckTimeout
 	app.BuildpackUrl = resource.Buildpack
 	app.Command = resource.Command
+	app.HealthCheckType = resource.HealthCheckType
+	app.DockerImage = resource.DockerImage
 
 	return
 }
@@ -78,6 +83,10 @@ func (resource ApplicationFromSummary) ToModel() (app models.Application) {
 	app.Routes = routes
 	app.Services = services
 
+	if resource.StackGuid != "" {
+		app.Stack = &models.Stack{Guid: resource.StackGuid}
+	}
+
 	return
 }
 
@@ -113,6 +122,7 @@ type DomainSummary struct {
 
 type AppSummaryRepository interface {
 	GetSummariesInCurrentSpace() (apps []models.Application, apiErr error)
+	GetSummariesInSpace(spaceGuid string) (apps []models.Application, apiErr error)
 	GetSummary(appGuid string) (summary models.Application, apiErr error)
 }
 
@@ -128,9 +138,13 @@ func NewCloudControllerAppSummaryRepository(config core_config.Reader, gateway n
 }
 
 func (repo CloudControllerAppSummaryRepository) GetSummariesInCurrentSpace() (apps []models.Application, apiErr error) {
+	return repo.GetSummariesInSpace(repo.config.SpaceFields().Guid)
+}
+
+func (repo CloudControllerAppSummaryRepository) GetSummariesInSpace(spaceGuid string) (apps []models.Application, apiErr error) {
 	resources := new(ApplicationSummaries)
 
-	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.ApiEndpoint(), repo.config.SpaceFields().Guid)
+	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.ApiEndpoint(), spaceGuid)
 	apiErr = repo.gateway.GetResource(path, resources)
 	if apiErr != nil {
 		return
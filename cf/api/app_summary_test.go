@@ -86,6 +86,39 @@ var _ = Describe("AppSummaryRepository", func() {
 		})
 	})
 
+	Describe("GetSummariesInSpace()", func() {
+		BeforeEach(func() {
+			getAppSummariesRequest := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/spaces/other-space-guid/summary",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body:   getAppSummariesResponseBody,
+				},
+			})
+
+			testServer, handler = testnet.NewServer([]testnet.TestRequest{getAppSummariesRequest})
+			configRepo := testconfig.NewRepositoryWithDefaults()
+			configRepo.SetApiEndpoint(testServer.URL)
+			gateway := net.NewCloudControllerGateway(configRepo, time.Now, &testterm.FakeUI{})
+			repo = NewCloudControllerAppSummaryRepository(configRepo, gateway)
+		})
+
+		AfterEach(func() {
+			testServer.Close()
+		})
+
+		It("returns the app summaries of the given space", func() {
+			apps, apiErr := repo.GetSummariesInSpace("other-space-guid")
+			Expect(handler).To(HaveAllRequestsCalled())
+
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(len(apps)).To(Equal(3))
+			Expect(apps[0].Name).To(Equal("app1"))
+			Expect(apps[1].Routes[1].URL()).To(Equal("foo.cfapps.io"))
+		})
+	})
+
 	Describe("GetSummary()", func() {
 		BeforeEach(func() {
 			getAppSummaryRequest := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
@@ -126,6 +159,9 @@ var _ = Describe("AppSummaryRepository", func() {
 			Expect(app.RunningInstances).To(Equal(1))
 			Expect(app.Memory).To(Equal(int64(128)))
 			Expect(app.PackageUpdatedAt.Format("2006-01-02T15:04:05Z07:00")).To(Equal("2014-10-24T19:54:00Z"))
+			Expect(app.Stack.Guid).To(Equal("stack-guid"))
+			Expect(app.HealthCheckType).To(Equal("port"))
+			Expect(app.DockerImage).To(Equal("user/image"))
 		})
 	})
 
@@ -234,5 +270,8 @@ const getAppSummaryResponseBody string = `
 		"service_names":[
 			"my-service-instance"
 		],
-		"package_updated_at":"2014-10-24T19:54:00+00:00"
+		"package_updated_at":"2014-10-24T19:54:00+00:00",
+		"stack_guid":"stack-guid",
+		"health_check_type":"port",
+		"docker_image":"user/image"
 }`
@@ -30,16 +30,33 @@ type ApplicationBitsRepository interface {
 type CloudControllerApplicationBitsRepository struct {
 	config  core_config.Reader
 	gateway net.Gateway
+
+	ChunkSize      int64
+	ChunkRetries   int
+	UploadStateDir string
 }
 
 func NewCloudControllerApplicationBitsRepository(config core_config.Reader, gateway net.Gateway) (repo CloudControllerApplicationBitsRepository) {
 	repo.config = config
 	repo.gateway = gateway
+	repo.ChunkSize = DefaultUploadChunkSize
+	repo.ChunkRetries = DefaultUploadChunkRetries
+	repo.UploadStateDir = DefaultUploadStateDir()
 	return
 }
 
 func (repo CloudControllerApplicationBitsRepository) UploadBits(appGuid string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error) {
-	apiUrl := fmt.Sprintf("/v2/apps/%s/bits", appGuid)
+	if zipFile != nil && repo.ChunkSize > 0 {
+		zipStats, err := zipFile.Stat()
+		if err == nil && zipStats.Size() > repo.ChunkSize {
+			return repo.uploadBitsInChunks(appGuid, zipFile, zipStats.Size(), presentFiles)
+		}
+	}
+
+	return repo.uploadBitsInOneRequest(appGuid, zipFile, presentFiles)
+}
+
+func (repo CloudControllerApplicationBitsRepository) uploadBitsInOneRequest(appGuid string, zipFile *os.File, presentFiles []resources.AppFileResource) (apiErr error) {
 	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
 		if err != nil {
 			apiErr = errors.NewWithError(T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
@@ -57,28 +74,31 @@ func (repo CloudControllerApplicationBitsRepository) UploadBits(appGuid string,
 			return
 		}
 
-		boundary, err := repo.writeUploadBody(zipFile, requestFile, presentFilesJSON)
+		boundary, err := writeUploadBody(zipFile, requestFile, presentFilesJSON)
 		if err != nil {
 			apiErr = errors.NewWithError(T("Error writing to tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
 			return
 		}
 
-		var request *net.Request
-		request, apiErr = repo.gateway.NewRequestForFile("PUT", repo.config.ApiEndpoint()+apiUrl, repo.config.AccessToken(), requestFile)
-		if apiErr != nil {
-			return
-		}
+		apiErr = repo.uploadBody(appGuid, requestFile, boundary, 0, 0)
+	})
 
-		contentType := fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
-		request.HttpReq.Header.Set("Content-Type", contentType)
+	return
+}
 
-		response := &resources.Resource{}
-		_, apiErr = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.ApiEndpoint(), request, response, DefaultAppUploadBitsTimeout)
-		if apiErr != nil {
-			return
-		}
-	})
+func (repo CloudControllerApplicationBitsRepository) uploadBody(appGuid string, body *os.File, boundary string, uploadedBefore, uploadTotal int64) (apiErr error) {
+	apiUrl := fmt.Sprintf("/v2/apps/%s/bits", appGuid)
+
+	request, apiErr := repo.gateway.NewRequestForFilePart("PUT", repo.config.ApiEndpoint()+apiUrl, repo.config.AccessToken(), body, uploadedBefore, uploadTotal)
+	if apiErr != nil {
+		return
+	}
+
+	contentType := fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
+	request.HttpReq.Header.Set("Content-Type", contentType)
 
+	response := &resources.Resource{}
+	_, apiErr = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.ApiEndpoint(), request, response, DefaultAppUploadBitsTimeout)
 	return
 }
 
@@ -132,7 +152,7 @@ func appFilesBySha(in []resources.AppFileResource) (out map[string]resources.App
 	return out
 }
 
-func (repo CloudControllerApplicationBitsRepository) writeUploadBody(zipFile *os.File, body *os.File, presentResourcesJson []byte) (boundary string, err error) {
+func writeUploadBody(zipFile *os.File, body *os.File, presentResourcesJson []byte) (boundary string, err error) {
 	writer := multipart.NewWriter(body)
 	defer writer.Close()
 
@@ -2,7 +2,10 @@ package application_bits_test
 
 import (
 	"archive/zip"
+	"crypto/sha1"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -200,6 +203,198 @@ var _ = Describe("CloudControllerApplicationBitsRepository", func() {
 		})
 	})
 
+	Describe("uploading large zip files", func() {
+		var (
+			uploadFile *os.File
+			stateDir   string
+			tmpDir     string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "chunked-upload")
+			Expect(err).NotTo(HaveOccurred())
+			stateDir = filepath.Join(tmpDir, "uploads")
+
+			uploadFile, err = os.Create(filepath.Join(tmpDir, "app.zip"))
+			Expect(err).NotTo(HaveOccurred())
+			writeZipWithLargeFiles(uploadFile)
+
+			gateway := net.NewCloudControllerGateway(configRepo, time.Now, &testterm.FakeUI{})
+			gateway.PollingThrottle = time.Duration(0)
+
+			chunkedRepo := NewCloudControllerApplicationBitsRepository(configRepo, gateway)
+			chunkedRepo.ChunkSize = 150 * 1024
+			chunkedRepo.ChunkRetries = 1
+			chunkedRepo.UploadStateDir = stateDir
+			repo = chunkedRepo
+		})
+
+		AfterEach(func() {
+			uploadFile.Close()
+			os.RemoveAll(tmpDir)
+			testServer.Close()
+		})
+
+		It("fills the resource pool through a scratch app in the space of the app and uploads the package in the final request", func() {
+			setupTestServer(
+				resourceMatchRequest(),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusCreated),
+				uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-1.bin"}),
+				createProgressEndpoint("finished"),
+				uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-2.bin"}),
+				createProgressEndpoint("finished"),
+				uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"app.rb", "large-1.bin", "large-2.bin"}, []string{"small.txt"}),
+				createProgressEndpoint("finished"),
+				deleteScratchAppRequest,
+			)
+
+			err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+
+			stateFiles, _ := filepath.Glob(filepath.Join(stateDir, "*"))
+			Expect(stateFiles).To(BeEmpty())
+		})
+
+		It("does not upload the files that the resource pool already has", func() {
+			setupTestServer(
+				resourceMatchRequest("large-1.bin"),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusCreated),
+				uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-2.bin"}),
+				createProgressEndpoint("finished"),
+				uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"large-1.bin", "large-2.bin"}, []string{"small.txt"}),
+				createProgressEndpoint("finished"),
+				deleteScratchAppRequest,
+			)
+
+			err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+		})
+
+		It("retries failed parts, deletes the scratch app and skips the pooled files on the next upload", func() {
+			setupTestServer(
+				resourceMatchRequest(),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusCreated),
+				uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-1.bin"}),
+				createProgressEndpoint("finished"),
+				uploadPartRequest("scratch-app-guid", http.StatusInternalServerError, []string{}, []string{"large-2.bin"}),
+				uploadPartRequest("scratch-app-guid", http.StatusInternalServerError, []string{}, []string{"large-2.bin"}),
+				deleteScratchAppRequest,
+			)
+
+			err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+			Expect(err).To(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+
+			stateFiles, _ := filepath.Glob(filepath.Join(stateDir, "*.json"))
+			Expect(stateFiles).To(BeEmpty())
+
+			testServer.Close()
+			setupTestServer(
+				resourceMatchRequest("large-1.bin"),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusCreated),
+				uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-2.bin"}),
+				createProgressEndpoint("finished"),
+				uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"large-1.bin", "large-2.bin"}, []string{"small.txt"}),
+				createProgressEndpoint("finished"),
+				deleteScratchAppRequest,
+			)
+
+			err = repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+
+			stateFiles, _ = filepath.Glob(filepath.Join(stateDir, "*.json"))
+			Expect(stateFiles).To(BeEmpty())
+		})
+
+		Describe("when an earlier upload of the same zip file was interrupted", func() {
+			BeforeEach(func() {
+				zipContents, err := ioutil.ReadFile(uploadFile.Name())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.MkdirAll(stateDir, 0700)).To(Succeed())
+				statePath := filepath.Join(stateDir, fmt.Sprintf("%x.json", sha1.Sum(zipContents)))
+				Expect(ioutil.WriteFile(statePath, []byte(`{"scratch_app_guid":"scratch-app-guid"}`), 0600)).To(Succeed())
+			})
+
+			It("reuses its scratch app and deletes it", func() {
+				setupTestServer(
+					resourceMatchRequest("large-1.bin"),
+					getTargetAppRequest,
+					getScratchAppRequest("app-space-guid"),
+					uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-2.bin"}),
+					createProgressEndpoint("finished"),
+					uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"large-1.bin", "large-2.bin"}, []string{"small.txt"}),
+					createProgressEndpoint("finished"),
+					deleteScratchAppRequest,
+				)
+
+				err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+
+				stateFiles, _ := filepath.Glob(filepath.Join(stateDir, "*"))
+				Expect(stateFiles).To(BeEmpty())
+			})
+
+			It("deletes its scratch app and creates another when it is in a different space", func() {
+				setupTestServer(
+					resourceMatchRequest("large-1.bin"),
+					getTargetAppRequest,
+					getScratchAppRequest("other-space-guid"),
+					deleteScratchAppRequest,
+					createScratchAppRequest(http.StatusCreated),
+					uploadPartRequest("scratch-app-guid", http.StatusCreated, []string{}, []string{"large-2.bin"}),
+					createProgressEndpoint("finished"),
+					uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"large-1.bin", "large-2.bin"}, []string{"small.txt"}),
+					createProgressEndpoint("finished"),
+					deleteScratchAppRequest,
+				)
+
+				err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+			})
+		})
+
+		It("discards the scratch app and the upload state when a part is rejected", func() {
+			setupTestServer(
+				resourceMatchRequest(),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusCreated),
+				uploadPartRequest("scratch-app-guid", http.StatusBadRequest, []string{}, []string{"large-1.bin"}),
+				deleteScratchAppRequest,
+			)
+
+			err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{})
+			Expect(err).To(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+
+			stateFiles, _ := filepath.Glob(filepath.Join(stateDir, "*"))
+			Expect(stateFiles).To(BeEmpty())
+		})
+
+		It("uploads everything in a single request when no scratch app can be created", func() {
+			setupTestServer(
+				resourceMatchRequest(),
+				getTargetAppRequest,
+				createScratchAppRequest(http.StatusForbidden),
+				uploadPartRequest("my-cool-app-guid", http.StatusCreated, []string{"app.rb"}, []string{"large-1.bin", "large-2.bin", "small.txt"}),
+				createProgressEndpoint("finished"),
+			)
+
+			err := repo.UploadBits("my-cool-app-guid", uploadFile, []resources.AppFileResource{file1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler.AllRequestsCalled()).To(BeTrue())
+		})
+	})
+
 	Describe(".GetApplicationFiles", func() {
 		It("accepts a slice of files and returns a slice of the files that it already has", func() {
 			setupTestServer(matchResourceRequest)
@@ -440,3 +635,126 @@ var matchExcludedResourceRequest = testnet.TestRequest{
 func executableBits(mode os.FileMode) os.FileMode {
 	return mode & 0111
 }
+
+func writeZipWithLargeFiles(file *os.File) {
+	writer := zip.NewWriter(file)
+
+	for _, name := range []string{"large-1.bin", "large-2.bin"} {
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = entry.Write(largeFileContent(name))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	entry, err := writer.Create("small.txt")
+	Expect(err).NotTo(HaveOccurred())
+	_, err = entry.Write([]byte("small file"))
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(writer.Close()).To(Succeed())
+}
+
+func largeFileContent(name string) []byte {
+	return []byte(strings.Repeat(name, 100*1024/len(name)))
+}
+
+func resourceMatchRequest(matchedNames ...string) testnet.TestRequest {
+	matched := []resources.IntegrityFields{}
+	for _, name := range matchedNames {
+		content := largeFileContent(name)
+		matched = append(matched, resources.IntegrityFields{
+			Sha1: fmt.Sprintf("%x", sha1.Sum(content)),
+			Size: int64(len(content)),
+		})
+	}
+
+	body, err := json.Marshal(matched)
+	Expect(err).NotTo(HaveOccurred())
+
+	return testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+		Method:   "PUT",
+		Path:     "/v2/resource_match",
+		Response: testnet.TestResponse{Status: http.StatusOK, Body: string(body)},
+	})
+}
+
+func createScratchAppRequest(status int) testnet.TestRequest {
+	return testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+		Method:  "POST",
+		Path:    "/v2/apps",
+		Matcher: testnet.RequestBodyMatcher(`{"name":"cf-upload-my-cool-app-guid","space_guid":"app-space-guid","state":"STOPPED"}`),
+		Response: testnet.TestResponse{
+			Status: status,
+			Body:   `{"metadata": {"guid": "scratch-app-guid"}}`,
+		},
+	})
+}
+
+var getTargetAppRequest = testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+	Method:   "GET",
+	Path:     "/v2/apps/my-cool-app-guid",
+	Response: testnet.TestResponse{Status: http.StatusOK, Body: `{"metadata": {"guid": "my-cool-app-guid"}, "entity": {"space_guid": "app-space-guid"}}`},
+})
+
+func getScratchAppRequest(spaceGuid string) testnet.TestRequest {
+	return testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+		Method:   "GET",
+		Path:     "/v2/apps/scratch-app-guid",
+		Response: testnet.TestResponse{Status: http.StatusOK, Body: `{"metadata": {"guid": "scratch-app-guid"}, "entity": {"space_guid": "` + spaceGuid + `"}}`},
+	})
+}
+
+var deleteScratchAppRequest = testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+	Method:   "DELETE",
+	Path:     "/v2/apps/scratch-app-guid?recursive=true",
+	Response: testnet.TestResponse{Status: http.StatusNoContent},
+})
+
+func uploadPartRequest(appGuid string, status int, expectedResources []string, expectedFiles []string) testnet.TestRequest {
+	return testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+		Method: "PUT",
+		Path:   fmt.Sprintf("/v2/apps/%s/bits", appGuid),
+		Matcher: func(request *http.Request) {
+			err := request.ParseMultipartForm(maxMultipartResponseSizeInBytes)
+			Expect(err).NotTo(HaveOccurred())
+			defer request.MultipartForm.RemoveAll()
+
+			presentFiles := []resources.AppFileResource{}
+			err = json.Unmarshal([]byte(request.MultipartForm.Value["resources"][0]), &presentFiles)
+			Expect(err).NotTo(HaveOccurred())
+
+			presentNames := []string{}
+			for _, presentFile := range presentFiles {
+				presentNames = append(presentNames, presentFile.Path)
+				Expect(presentFile.Sha1).NotTo(BeEmpty())
+			}
+			Expect(presentNames).To(Equal(expectedResources))
+
+			fileHeaders := request.MultipartForm.File["application"]
+			Expect(fileHeaders).To(HaveLen(1))
+
+			file, err := fileHeaders[0].Open()
+			Expect(err).NotTo(HaveOccurred())
+			length, err := strconv.ParseInt(fileHeaders[0].Header.Get("content-length"), 10, 64)
+			Expect(err).NotTo(HaveOccurred())
+
+			zipReader, err := zip.NewReader(file, length)
+			Expect(err).NotTo(HaveOccurred())
+
+			names := []string{}
+			for _, zipFile := range zipReader.File {
+				names = append(names, zipFile.Name)
+			}
+			Expect(names).To(Equal(expectedFiles))
+		},
+		Response: testnet.TestResponse{
+			Status: status,
+			Body: `
+{
+	"metadata":{
+		"guid": "my-job-guid",
+		"url": "/v2/jobs/my-job-guid"
+	}
+}`},
+	})
+}
@@ -0,0 +1,426 @@
+package application_bits
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/configuration/config_helpers"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+)
+
+const (
+	DefaultUploadChunkSize    = 64 * 1024 * 1024
+	DefaultUploadChunkRetries = 3
+
+	// The Cloud Controller only keeps files within these sizes in its
+	// resource pool, so only they can be uploaded ahead of the package.
+	resourcePoolMinimumSize = 64 * 1024
+	resourcePoolMaximumSize = 512 * 1024 * 1024
+)
+
+func DefaultUploadStateDir() string {
+	return filepath.Join(filepath.Dir(config_helpers.DefaultFilePath()), "uploads")
+}
+
+// uploadState records the scratch app that an upload of a zip file fills the
+// resource pool with. Every upload deletes its scratch app when it returns,
+// so the state only matters when the cf process itself is interrupted: the
+// next upload of the same zip file reuses the scratch app and deletes it.
+type uploadState struct {
+	ScratchAppGuid string `json:"scratch_app_guid"`
+}
+
+type uploadPart struct {
+	body     *os.File
+	boundary string
+}
+
+type zipEntry struct {
+	file     *zip.File
+	resource resources.AppFileResource
+}
+
+// uploadBitsInChunks uploads the large files in zipFile into the resource
+// pool in parts of at most ChunkSize bytes before the package is uploaded.
+// The parts are the bits of a stopped scratch app in the space of the app, so
+// the package of the app is only replaced by the final request, which
+// contains the remaining files and refers to everything in the pool. Files
+// the pool already has, e.g. because an earlier upload failed, are not
+// uploaded again.
+func (repo CloudControllerApplicationBitsRepository) uploadBitsInChunks(appGuid string, zipFile *os.File, zipSize int64, presentFiles []resources.AppFileResource) (apiErr error) {
+	reader, err := zip.NewReader(zipFile, zipSize)
+	if err != nil {
+		return errors.NewWithError(T("Error reading zip file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+	}
+
+	zipChecksum, err := fileChecksum(io.NewSectionReader(zipFile, 0, zipSize))
+	if err != nil {
+		return errors.NewWithError(T("Error reading zip file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+	}
+
+	pooled, remaining, err := splitPoolableEntries(reader.File)
+	if err != nil {
+		return errors.NewWithError(T("Error reading zip file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+	}
+
+	references := append([]resources.AppFileResource{}, presentFiles...)
+	if len(pooled) > 0 {
+		var matched []resources.AppFileResource
+		matched, apiErr = repo.GetApplicationFiles(entryResources(pooled))
+		if apiErr != nil {
+			return
+		}
+		references = append(references, matched...)
+		pooled = withoutMatchedEntries(pooled, matched)
+	}
+
+	for _, entry := range pooled {
+		references = append(references, entry.resource)
+	}
+
+	statePath := filepath.Join(repo.UploadStateDir, zipChecksum+".json")
+	scratchAppGuid := ""
+	if len(pooled) > 0 {
+		scratchAppGuid, apiErr = repo.scratchApp(appGuid, statePath)
+		if apiErr != nil {
+			_, err = zipFile.Seek(0, 0)
+			if err != nil {
+				return errors.NewWithError(T("Error reading zip file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+			}
+			return repo.uploadBitsInOneRequest(appGuid, zipFile, presentFiles)
+		}
+		defer repo.discardUpload(statePath, scratchAppGuid)
+	}
+
+	workDir, err := ioutil.TempDir("", "upload-parts")
+	if err != nil {
+		return errors.NewWithError(T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+	}
+	defer os.RemoveAll(workDir)
+
+	parts, err := repo.writeUploadParts(workDir, pooled, remaining, references)
+	defer func() {
+		for _, part := range parts {
+			part.body.Close()
+		}
+	}()
+	if err != nil {
+		return errors.NewWithError(T("Error writing to tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+	}
+
+	var uploadTotal int64
+	for _, part := range parts {
+		stat, err := part.body.Stat()
+		if err != nil {
+			return errors.NewWithError(T("Error getting file info"), err)
+		}
+		uploadTotal += stat.Size()
+	}
+
+	var uploadedBefore int64
+	for index, part := range parts {
+		targetGuid := scratchAppGuid
+		if index == len(parts)-1 {
+			targetGuid = appGuid
+		}
+
+		apiErr = repo.uploadPartWithRetries(targetGuid, part, uploadedBefore, uploadTotal)
+		if apiErr != nil {
+			return
+		}
+
+		stat, _ := part.body.Stat()
+		uploadedBefore += stat.Size()
+	}
+
+	return
+}
+
+func (repo CloudControllerApplicationBitsRepository) uploadPartWithRetries(appGuid string, part uploadPart, uploadedBefore, uploadTotal int64) (apiErr error) {
+	for attempt := 0; attempt <= repo.ChunkRetries; attempt++ {
+		apiErr = repo.uploadBody(appGuid, part.body, part.boundary, uploadedBefore, uploadTotal)
+		if apiErr == nil || !isRetryable(apiErr) {
+			return
+		}
+	}
+	return
+}
+
+func isRetryable(err error) bool {
+	if httpErr, ok := err.(errors.HttpError); ok {
+		return httpErr.StatusCode() >= 500
+	}
+	return true
+}
+
+// scratchApp returns the stopped app in the space of the app that the
+// resource pool is filled with, reusing the one of an interrupted upload when
+// it still exists in that space.
+func (repo CloudControllerApplicationBitsRepository) scratchApp(appGuid string, statePath string) (string, error) {
+	spaceGuid, err := repo.appSpaceGuid(appGuid)
+	if err != nil {
+		return "", err
+	}
+
+	state := loadUploadState(statePath)
+	if state.ScratchAppGuid != "" {
+		scratchSpaceGuid, err := repo.appSpaceGuid(state.ScratchAppGuid)
+		if err == nil && scratchSpaceGuid == spaceGuid {
+			return state.ScratchAppGuid, nil
+		}
+		repo.discardUpload(statePath, state.ScratchAppGuid)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"name":       "cf-upload-" + appGuid,
+		"space_guid": spaceGuid,
+		"state":      "STOPPED",
+	})
+	if err != nil {
+		return "", errors.NewWithError(T("Error marshaling JSON"), err)
+	}
+
+	resource := resources.ApplicationResource{}
+	err = repo.gateway.CreateResource(repo.config.ApiEndpoint(), "/v2/apps", strings.NewReader(string(body)), &resource)
+	if err != nil {
+		return "", err
+	}
+
+	saveUploadState(statePath, uploadState{ScratchAppGuid: resource.Metadata.Guid})
+	return resource.Metadata.Guid, nil
+}
+
+func (repo CloudControllerApplicationBitsRepository) appSpaceGuid(appGuid string) (string, error) {
+	resource := resources.ApplicationResource{}
+	err := repo.gateway.GetResource(fmt.Sprintf("%s/v2/apps/%s", repo.config.ApiEndpoint(), appGuid), &resource)
+	if err != nil {
+		return "", err
+	}
+
+	if resource.Entity.SpaceGuid == nil {
+		return "", nil
+	}
+	return *resource.Entity.SpaceGuid, nil
+}
+
+// discardUpload is best effort; a scratch app that cannot be deleted is only
+// left behind in the space.
+func (repo CloudControllerApplicationBitsRepository) discardUpload(statePath string, scratchAppGuid string) {
+	if scratchAppGuid != "" {
+		repo.gateway.DeleteResource(repo.config.ApiEndpoint(), fmt.Sprintf("/v2/apps/%s?recursive=true", scratchAppGuid))
+	}
+	os.Remove(statePath)
+}
+
+// splitPoolableEntries separates the files the resource pool accepts from the
+// directories and files that have to be part of the package upload itself.
+func splitPoolableEntries(files []*zip.File) (pooled []zipEntry, remaining []*zip.File, err error) {
+	for _, file := range files {
+		size := int64(file.UncompressedSize64)
+		if file.FileInfo().IsDir() || size < resourcePoolMinimumSize || size > resourcePoolMaximumSize {
+			remaining = append(remaining, file)
+			continue
+		}
+
+		var sha1 string
+		sha1, err = zipEntryChecksum(file)
+		if err != nil {
+			return
+		}
+
+		pooled = append(pooled, zipEntry{
+			file: file,
+			resource: resources.AppFileResource{
+				Path: file.Name,
+				Sha1: sha1,
+				Size: size,
+				Mode: fmt.Sprintf("%#o", file.Mode()),
+			},
+		})
+	}
+	return
+}
+
+func entryResources(entries []zipEntry) (out []resources.AppFileResource) {
+	for _, entry := range entries {
+		out = append(out, entry.resource)
+	}
+	return
+}
+
+func withoutMatchedEntries(entries []zipEntry, matched []resources.AppFileResource) (out []zipEntry) {
+	matchedShas := map[string]bool{}
+	for _, file := range matched {
+		matchedShas[file.Sha1] = true
+	}
+
+	for _, entry := range entries {
+		if !matchedShas[entry.resource.Sha1] {
+			out = append(out, entry)
+		}
+	}
+	return
+}
+
+// writeUploadParts writes the request bodies of the parts that fill the
+// resource pool, followed by the body of the final package upload.
+func (repo CloudControllerApplicationBitsRepository) writeUploadParts(workDir string, pooled []zipEntry, remaining []*zip.File, references []resources.AppFileResource) (parts []uploadPart, err error) {
+	chunks := [][]*zip.File{}
+	var chunkSize int64
+
+	for _, entry := range pooled {
+		if len(chunks) == 0 || chunkSize+int64(entry.file.CompressedSize64) > repo.ChunkSize {
+			chunks = append(chunks, []*zip.File{})
+			chunkSize = 0
+		}
+		chunks[len(chunks)-1] = append(chunks[len(chunks)-1], entry.file)
+		chunkSize += int64(entry.file.CompressedSize64)
+	}
+
+	for index, chunk := range chunks {
+		var part uploadPart
+		part, err = writeUploadPart(filepath.Join(workDir, fmt.Sprintf("part-%d", index)), chunk, nil)
+		if err != nil {
+			return
+		}
+		parts = append(parts, part)
+	}
+
+	part, err := writeUploadPart(filepath.Join(workDir, "part-final"), remaining, references)
+	if err != nil {
+		return
+	}
+	parts = append(parts, part)
+	return
+}
+
+func writeUploadPart(path string, files []*zip.File, references []resources.AppFileResource) (part uploadPart, err error) {
+	var zipFile *os.File
+	if len(files) > 0 {
+		zipFile, err = os.Create(path + ".zip")
+		if err != nil {
+			return
+		}
+		defer zipFile.Close()
+
+		err = copyZipEntries(files, zipFile)
+		if err != nil {
+			return
+		}
+
+		_, err = zipFile.Seek(0, 0)
+		if err != nil {
+			return
+		}
+	}
+
+	if references == nil {
+		references = []resources.AppFileResource{}
+	}
+
+	referencesJSON, err := json.Marshal(references)
+	if err != nil {
+		return
+	}
+
+	part.body, err = os.Create(path + ".body")
+	if err != nil {
+		return
+	}
+
+	part.boundary, err = writeUploadBody(zipFile, part.body, referencesJSON)
+	return
+}
+
+func copyZipEntries(files []*zip.File, out io.Writer) (err error) {
+	writer := zip.NewWriter(out)
+	defer func() {
+		closeErr := writer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for _, file := range files {
+		header := file.FileHeader
+		var entry io.Writer
+		entry, err = writer.CreateHeader(&header)
+		if err != nil {
+			return
+		}
+
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		var content io.ReadCloser
+		content, err = file.Open()
+		if err != nil {
+			return
+		}
+
+		_, err = io.Copy(entry, content)
+		content.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func zipEntryChecksum(file *zip.File) (string, error) {
+	content, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	return fileChecksum(content)
+}
+
+func fileChecksum(content io.Reader) (string, error) {
+	hash := sha1.New()
+	_, err := io.Copy(hash, content)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func loadUploadState(path string) (state uploadState) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if json.Unmarshal(contents, &state) != nil {
+		state = uploadState{}
+	}
+	return
+}
+
+// saveUploadState is best effort; without it an interrupted upload only
+// creates a new scratch app.
+func saveUploadState(path string, state uploadState) {
+	contents, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	if os.MkdirAll(filepath.Dir(path), 0700) != nil {
+		return
+	}
+
+	ioutil.WriteFile(path, contents, 0600)
+}
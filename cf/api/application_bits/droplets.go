@@ -0,0 +1,132 @@
+package application_bits
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/gofileutils/fileutils"
+)
+
+//go:generate counterfeiter -o fakes/fake_application_droplet_repository.go . ApplicationDropletRepository
+type ApplicationDropletRepository interface {
+	DownloadDroplet(appGuid string, destination io.Writer) (sha1 string, apiErr error)
+	DownloadBits(appGuid string, destination io.Writer) (sha1 string, apiErr error)
+	UploadDroplet(appGuid string, droplet *os.File) (apiErr error)
+}
+
+type CloudControllerApplicationDropletRepository struct {
+	config  core_config.Reader
+	gateway net.Gateway
+}
+
+func NewCloudControllerApplicationDropletRepository(config core_config.Reader, gateway net.Gateway) (repo CloudControllerApplicationDropletRepository) {
+	repo.config = config
+	repo.gateway = gateway
+	return
+}
+
+// DownloadDroplet writes the staged droplet of an app to destination and
+// returns its SHA1.
+func (repo CloudControllerApplicationDropletRepository) DownloadDroplet(appGuid string, destination io.Writer) (string, error) {
+	return repo.download(fmt.Sprintf("/v2/apps/%s/droplet/download", appGuid), destination)
+}
+
+// DownloadBits writes the package last uploaded for an app to destination and
+// returns its SHA1.
+func (repo CloudControllerApplicationDropletRepository) DownloadBits(appGuid string, destination io.Writer) (string, error) {
+	return repo.download(fmt.Sprintf("/v2/apps/%s/download", appGuid), destination)
+}
+
+func (repo CloudControllerApplicationDropletRepository) download(path string, destination io.Writer) (checksum string, apiErr error) {
+	request, apiErr := repo.gateway.NewRequest("GET", repo.config.ApiEndpoint()+path, repo.config.AccessToken(), nil)
+	if apiErr != nil {
+		return
+	}
+
+	sha1Hash := sha1.New()
+	md5Hash := md5.New()
+
+	headers, apiErr := repo.gateway.PerformRequestForDownload(request, io.MultiWriter(destination, sha1Hash, md5Hash))
+	if apiErr != nil {
+		return
+	}
+
+	if expected := headers.Get("Content-MD5"); expected != "" {
+		actual := base64.StdEncoding.EncodeToString(md5Hash.Sum(nil))
+		if actual != expected {
+			apiErr = errors.New(T("Downloaded file is corrupt: expected MD5 {{.Expected}} but got {{.Actual}}",
+				map[string]interface{}{"Expected": expected, "Actual": actual}))
+			return
+		}
+	}
+
+	checksum = fmt.Sprintf("%x", sha1Hash.Sum(nil))
+	return
+}
+
+// UploadDroplet replaces the droplet of an app, which then starts without
+// staging.
+func (repo CloudControllerApplicationDropletRepository) UploadDroplet(appGuid string, droplet *os.File) (apiErr error) {
+	fileutils.TempFile("requests", func(requestFile *os.File, err error) {
+		if err != nil {
+			apiErr = errors.NewWithError(T("Error creating tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+			return
+		}
+
+		boundary, err := writeDropletUploadBody(droplet, requestFile)
+		if err != nil {
+			apiErr = errors.NewWithError(T("Error writing to tmp file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+			return
+		}
+
+		apiUrl := fmt.Sprintf("/v2/apps/%s/droplet/upload", appGuid)
+
+		var request *net.Request
+		request, apiErr = repo.gateway.NewRequestForFile("PUT", repo.config.ApiEndpoint()+apiUrl, repo.config.AccessToken(), requestFile)
+		if apiErr != nil {
+			return
+		}
+
+		request.HttpReq.Header.Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", boundary))
+
+		response := &resources.Resource{}
+		_, apiErr = repo.gateway.PerformPollingRequestForJSONResponse(repo.config.ApiEndpoint(), request, response, DefaultAppUploadBitsTimeout)
+	})
+	return
+}
+
+func writeDropletUploadBody(droplet *os.File, body *os.File) (boundary string, err error) {
+	writer := multipart.NewWriter(body)
+	defer writer.Close()
+
+	boundary = writer.Boundary()
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="droplet"; filename="droplet.tgz"`)
+	h.Set("Content-Type", "application/gzip")
+	h.Set("Content-Transfer-Encoding", "binary")
+
+	part, err := writer.CreatePart(h)
+	if err != nil {
+		return
+	}
+
+	_, err = droplet.Seek(0, 0)
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(part, droplet)
+	return
+}
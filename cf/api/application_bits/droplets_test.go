@@ -0,0 +1,181 @@
+package application_bits_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/net"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/cf/api/application_bits"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CloudControllerApplicationDropletRepository", func() {
+	var (
+		repo       ApplicationDropletRepository
+		configRepo core_config.ReadWriter
+		testServer *httptest.Server
+		handler    http.HandlerFunc
+		contents   []byte
+	)
+
+	BeforeEach(func() {
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		gateway := net.NewCloudControllerGateway(configRepo, time.Now, &testterm.FakeUI{})
+		gateway.PollingThrottle = time.Duration(0)
+
+		repo = NewCloudControllerApplicationDropletRepository(configRepo, gateway)
+		contents = []byte("droplet contents")
+
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		configRepo.SetApiEndpoint(testServer.URL)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	Describe("DownloadDroplet", func() {
+		It("downloads the droplet of the app and returns its SHA1", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.Method).To(Equal("GET"))
+				Expect(r.URL.Path).To(Equal("/v2/apps/my-app-guid/droplet/download"))
+				Expect(r.Header.Get("Authorization")).NotTo(BeEmpty())
+
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.Write(contents)
+			}
+
+			destination := &bytes.Buffer{}
+			checksum, err := repo.DownloadDroplet("my-app-guid", destination)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(destination.Bytes()).To(Equal(contents))
+			Expect(checksum).To(Equal(fmt.Sprintf("%x", sha1.Sum(contents))))
+		})
+
+		It("follows redirects to the blobstore", func() {
+			blobstore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(contents)
+			}))
+			defer blobstore.Close()
+
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, blobstore.URL+"/droplets/my-app-guid", http.StatusFound)
+			}
+
+			destination := &bytes.Buffer{}
+			_, err := repo.DownloadDroplet("my-app-guid", destination)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destination.Bytes()).To(Equal(contents))
+		})
+
+		It("verifies the Content-MD5 of the download", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				md5Sum := md5.Sum([]byte("something else"))
+				w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum[:]))
+				w.Write(contents)
+			}
+
+			_, err := repo.DownloadDroplet("my-app-guid", &bytes.Buffer{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Downloaded file is corrupt"))
+		})
+
+		It("fails when the download is incomplete", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(len(contents)+10))
+				w.Write(contents)
+			}
+
+			_, err := repo.DownloadDroplet("my-app-guid", &bytes.Buffer{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns the error from the cloud controller", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code": 10010, "description": "Droplet not found"}`))
+			}
+
+			_, err := repo.DownloadDroplet("my-app-guid", &bytes.Buffer{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Droplet not found"))
+		})
+	})
+
+	Describe("DownloadBits", func() {
+		It("downloads the package of the app", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.URL.Path).To(Equal("/v2/apps/my-app-guid/download"))
+				w.Write(contents)
+			}
+
+			destination := &bytes.Buffer{}
+			checksum, err := repo.DownloadBits("my-app-guid", destination)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(destination.Bytes()).To(Equal(contents))
+			Expect(checksum).To(Equal(fmt.Sprintf("%x", sha1.Sum(contents))))
+		})
+	})
+
+	Describe("UploadDroplet", func() {
+		It("uploads the droplet as a multipart form", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				if r.URL.Path == "/v2/jobs/my-job-guid" {
+					w.Write([]byte(`{"metadata": {"guid": "my-job-guid"}, "entity": {"status": "finished"}}`))
+					return
+				}
+
+				Expect(r.Method).To(Equal("PUT"))
+				Expect(r.URL.Path).To(Equal("/v2/apps/my-app-guid/droplet/upload"))
+
+				reader, err := r.MultipartReader()
+				Expect(err).NotTo(HaveOccurred())
+
+				part, err := reader.NextPart()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(part.FormName()).To(Equal("droplet"))
+
+				uploaded, err := ioutil.ReadAll(part)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(uploaded).To(Equal(contents))
+
+				_, err = reader.NextPart()
+				Expect(err).To(HaveOccurred())
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"metadata": {"guid": "my-job-guid", "url": "/v2/jobs/my-job-guid"}, "entity": {"status": "queued"}}`))
+			}
+
+			droplet, err := ioutil.TempFile("", "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(droplet.Name())
+			defer droplet.Close()
+			droplet.Write(contents)
+
+			err = repo.UploadDroplet("my-app-guid", droplet)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,141 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	. "github.com/cloudfoundry/cli/cf/api/application_bits"
+	"io"
+	"os"
+	"sync"
+)
+
+type FakeApplicationDropletRepository struct {
+	DownloadDropletStub        func(arg1 string, arg2 io.Writer) (sha1 string, apiErr error)
+	downloadDropletMutex       sync.RWMutex
+	downloadDropletArgsForCall []struct {
+		arg1 string
+		arg2 io.Writer
+	}
+	downloadDropletReturns struct {
+		result1 string
+		result2 error
+	}
+	DownloadBitsStub        func(arg1 string, arg2 io.Writer) (sha1 string, apiErr error)
+	downloadBitsMutex       sync.RWMutex
+	downloadBitsArgsForCall []struct {
+		arg1 string
+		arg2 io.Writer
+	}
+	downloadBitsReturns struct {
+		result1 string
+		result2 error
+	}
+	UploadDropletStub        func(arg1 string, arg2 *os.File) (apiErr error)
+	uploadDropletMutex       sync.RWMutex
+	uploadDropletArgsForCall []struct {
+		arg1 string
+		arg2 *os.File
+	}
+	uploadDropletReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadDroplet(arg1 string, arg2 io.Writer) (sha1 string, apiErr error) {
+	fake.downloadDropletMutex.Lock()
+	defer fake.downloadDropletMutex.Unlock()
+	fake.downloadDropletArgsForCall = append(fake.downloadDropletArgsForCall, struct {
+		arg1 string
+		arg2 io.Writer
+	}{arg1, arg2})
+	if fake.DownloadDropletStub != nil {
+		return fake.DownloadDropletStub(arg1, arg2)
+	} else {
+		return fake.downloadDropletReturns.result1, fake.downloadDropletReturns.result2
+	}
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadDropletCallCount() int {
+	fake.downloadDropletMutex.RLock()
+	defer fake.downloadDropletMutex.RUnlock()
+	return len(fake.downloadDropletArgsForCall)
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadDropletArgsForCall(i int) (string, io.Writer) {
+	fake.downloadDropletMutex.RLock()
+	defer fake.downloadDropletMutex.RUnlock()
+	return fake.downloadDropletArgsForCall[i].arg1, fake.downloadDropletArgsForCall[i].arg2
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadDropletReturns(result1 string, result2 error) {
+	fake.downloadDropletReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadBits(arg1 string, arg2 io.Writer) (sha1 string, apiErr error) {
+	fake.downloadBitsMutex.Lock()
+	defer fake.downloadBitsMutex.Unlock()
+	fake.downloadBitsArgsForCall = append(fake.downloadBitsArgsForCall, struct {
+		arg1 string
+		arg2 io.Writer
+	}{arg1, arg2})
+	if fake.DownloadBitsStub != nil {
+		return fake.DownloadBitsStub(arg1, arg2)
+	} else {
+		return fake.downloadBitsReturns.result1, fake.downloadBitsReturns.result2
+	}
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadBitsCallCount() int {
+	fake.downloadBitsMutex.RLock()
+	defer fake.downloadBitsMutex.RUnlock()
+	return len(fake.downloadBitsArgsForCall)
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadBitsArgsForCall(i int) (string, io.Writer) {
+	fake.downloadBitsMutex.RLock()
+	defer fake.downloadBitsMutex.RUnlock()
+	return fake.downloadBitsArgsForCall[i].arg1, fake.downloadBitsArgsForCall[i].arg2
+}
+
+func (fake *FakeApplicationDropletRepository) DownloadBitsReturns(result1 string, result2 error) {
+	fake.downloadBitsReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApplicationDropletRepository) UploadDroplet(arg1 string, arg2 *os.File) (apiErr error) {
+	fake.uploadDropletMutex.Lock()
+	defer fake.uploadDropletMutex.Unlock()
+	fake.uploadDropletArgsForCall = append(fake.uploadDropletArgsForCall, struct {
+		arg1 string
+		arg2 *os.File
+	}{arg1, arg2})
+	if fake.UploadDropletStub != nil {
+		return fake.UploadDropletStub(arg1, arg2)
+	} else {
+		return fake.uploadDropletReturns.result1
+	}
+}
+
+func (fake *FakeApplicationDropletRepository) UploadDropletCallCount() int {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return len(fake.uploadDropletArgsForCall)
+}
+
+func (fake *FakeApplicationDropletRepository) UploadDropletArgsForCall(i int) (string, *os.File) {
+	fake.uploadDropletMutex.RLock()
+	defer fake.uploadDropletMutex.RUnlock()
+	return fake.uploadDropletArgsForCall[i].arg1, fake.uploadDropletArgsForCall[i].arg2
+}
+
+func (fake *FakeApplicationDropletRepository) UploadDropletReturns(result1 error) {
+	fake.uploadDropletReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ ApplicationDropletRepository = new(FakeApplicationDropletRepository)
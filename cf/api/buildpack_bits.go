@@ -2,6 +2,7 @@ package api
 
 import (
 	"archive/zip"
+	"crypto/sha1"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -21,11 +22,13 @@ import (
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
+	clifileutils "github.com/cloudfoundry/cli/fileutils"
 	"github.com/cloudfoundry/gofileutils/fileutils"
 )
 
 type BuildpackBitsRepository interface {
 	UploadBuildpack(buildpack models.Buildpack, dir string) (apiErr error)
+	DownloadBuildpack(url string, saveDir string) (size int64, fileName string, apiErr error)
 }
 
 type CloudControllerBuildpackBitsRepository struct {
@@ -95,6 +98,35 @@ func (repo CloudControllerBuildpackBitsRepository) UploadBuildpack(buildpack mod
 	return
 }
 
+// BuildpackArchiveChecksum returns the sha1 of the buildpack zip file at
+// location as UploadBuildpack uploads it, which is the checksum the Cloud
+// Controller keeps for the bits.
+func BuildpackArchiveChecksum(location string) (checksum string, err error) {
+	fileutils.TempFile("buildpack-checksum", func(normalizedFile *os.File, tempErr error) {
+		if tempErr != nil {
+			err = tempErr
+			return
+		}
+
+		var archive *os.File
+		archive, err = os.Open(location)
+		if err != nil {
+			return
+		}
+		defer archive.Close()
+
+		err = normalizeBuildpackArchive(archive, normalizedFile)
+		if err != nil {
+			return
+		}
+
+		hash := sha1.New()
+		_, err = io.Copy(hash, normalizedFile)
+		checksum = fmt.Sprintf("%x", hash.Sum(nil))
+	})
+	return
+}
+
 func normalizeBuildpackArchive(inputFile *os.File, outputFile *os.File) error {
 	stats, err := inputFile.Stat()
 	if err != nil {
@@ -181,22 +213,7 @@ func (repo CloudControllerBuildpackBitsRepository) downloadBuildpack(url string,
 			return
 		}
 
-		var certPool *x509.CertPool
-		if len(repo.TrustedCerts) > 0 {
-			certPool = x509.NewCertPool()
-			for _, tlsCert := range repo.TrustedCerts {
-				cert, _ := x509.ParseCertificate(tlsCert.Certificate[0])
-				certPool.AddCert(cert)
-			}
-		}
-
-		client := &http.Client{
-			Transport: &http.Transport{
-				Dial:            (&gonet.Dialer{Timeout: 5 * time.Second}).Dial,
-				TLSClientConfig: &tls.Config{RootCAs: certPool},
-				Proxy:           http.ProxyFromEnvironment,
-			},
-		}
+		client := &http.Client{Transport: repo.downloadTransport()}
 
 		response, err := client.Get(url)
 		if err != nil {
@@ -211,6 +228,29 @@ func (repo CloudControllerBuildpackBitsRepository) downloadBuildpack(url string,
 	})
 }
 
+// DownloadBuildpack downloads the buildpack at url into saveDir, trusting the
+// same certificates as UploadBuildpack does for urls.
+func (repo CloudControllerBuildpackBitsRepository) DownloadBuildpack(url string, saveDir string) (size int64, fileName string, apiErr error) {
+	return clifileutils.NewDownloaderWithTransport(saveDir, repo.downloadTransport()).DownloadFile(url)
+}
+
+func (repo CloudControllerBuildpackBitsRepository) downloadTransport() *http.Transport {
+	var certPool *x509.CertPool
+	if len(repo.TrustedCerts) > 0 {
+		certPool = x509.NewCertPool()
+		for _, tlsCert := range repo.TrustedCerts {
+			cert, _ := x509.ParseCertificate(tlsCert.Certificate[0])
+			certPool.AddCert(cert)
+		}
+	}
+
+	return &http.Transport{
+		Dial:            (&gonet.Dialer{Timeout: 5 * time.Second}).Dial,
+		TLSClientConfig: &tls.Config{RootCAs: certPool},
+		Proxy:           http.ProxyFromEnvironment,
+	}
+}
+
 func (repo CloudControllerBuildpackBitsRepository) uploadBits(buildpack models.Buildpack, body io.Reader, buildpackName string) error {
 	return repo.performMultiPartUpload(
 		fmt.Sprintf("%s/v2/buildpacks/%s/bits", repo.config.ApiEndpoint(), buildpack.Guid),
@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"archive/zip"
+	"crypto/sha1"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -164,6 +165,89 @@ var _ = Describe("BuildpackBitsRepository", func() {
 			})
 		})
 	})
+
+	Describe("#DownloadBuildpack", func() {
+		var saveDir string
+
+		BeforeEach(func() {
+			var err error
+			saveDir, err = ioutil.TempDir("", "buildpack-download")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(saveDir)
+		})
+
+		It("downloads the file over HTTPS with the trusted certificates", func() {
+			fileServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprint(writer, "buildpack bits")
+			}))
+			defer fileServer.Close()
+
+			repo.TrustedCerts = fileServer.TLS.Certificates
+			size, fileName, err := repo.DownloadBuildpack(fileServer.URL+"/place/example-buildpack.zip", saveDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(size).To(Equal(int64(len("buildpack bits"))))
+			Expect(fileName).To(Equal("example-buildpack.zip"))
+
+			contents, err := ioutil.ReadFile(filepath.Join(saveDir, fileName))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("buildpack bits"))
+		})
+
+		It("fails when the server's SSL cert cannot be verified", func() {
+			fileServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprint(writer, "buildpack bits")
+			}))
+			defer fileServer.Close()
+
+			_, _, err := repo.DownloadBuildpack(fileServer.URL+"/place/example-buildpack.zip", saveDir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("BuildpackArchiveChecksum", func() {
+		It("returns the checksum of the bits UploadBuildpack uploads", func() {
+			buildpackPath := filepath.Join(buildpacksDir, "example-buildpack-in-dir.zip")
+
+			var uploadedChecksum string
+			testServer.Close()
+			testServer, testServerHandler = testnet.NewServer([]testnet.TestRequest{
+				{
+					Method:   "PUT",
+					Path:     "/v2/buildpacks/my-cool-buildpack-guid/bits",
+					Response: testnet.TestResponse{Status: http.StatusCreated},
+					Matcher: func(request *http.Request) {
+						err := request.ParseMultipartForm(4096)
+						Expect(err).NotTo(HaveOccurred())
+						defer request.MultipartForm.RemoveAll()
+
+						file, err := request.MultipartForm.File["buildpack"][0].Open()
+						Expect(err).NotTo(HaveOccurred())
+
+						hash := sha1.New()
+						_, err = io.Copy(hash, file)
+						Expect(err).NotTo(HaveOccurred())
+						uploadedChecksum = fmt.Sprintf("%x", hash.Sum(nil))
+					},
+				},
+			})
+			configRepo.SetApiEndpoint(testServer.URL)
+
+			Expect(repo.UploadBuildpack(buildpack, buildpackPath)).To(Succeed())
+			Expect(testServerHandler).To(HaveAllRequestsCalled())
+
+			checksum, err := BuildpackArchiveChecksum(buildpackPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checksum).To(Equal(uploadedChecksum))
+		})
+
+		It("fails for a zip file that does not contain a buildpack", func() {
+			_, err := BuildpackArchiveChecksum(filepath.Join(buildpacksDir, "bad-buildpack.zip"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
 
 func uploadBuildpackRequest() testnet.TestRequest {
@@ -13,6 +13,8 @@ import (
 type EnvironmentVariableGroupsRepository interface {
 	ListRunning() (variables []models.EnvironmentVariable, apiErr error)
 	ListStaging() (variables []models.EnvironmentVariable, apiErr error)
+	ReadRunning() (map[string]interface{}, error)
+	ReadStaging() (map[string]interface{}, error)
 	SetStaging(string) error
 	SetRunning(string) error
 }
@@ -60,6 +62,28 @@ func (repo CloudControllerEnvironmentVariableGroupsRepository) ListStaging() (va
 	return variables, nil
 }
 
+// ReadRunning returns the running group with its values as sent by the
+// cloud controller, so that they can be written back unchanged.
+func (repo CloudControllerEnvironmentVariableGroupsRepository) ReadRunning() (map[string]interface{}, error) {
+	return repo.read("running")
+}
+
+// ReadStaging returns the staging group with its values as sent by the
+// cloud controller, so that they can be written back unchanged.
+func (repo CloudControllerEnvironmentVariableGroupsRepository) ReadStaging() (map[string]interface{}, error) {
+	return repo.read("staging")
+}
+
+func (repo CloudControllerEnvironmentVariableGroupsRepository) read(group string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	url := fmt.Sprintf("%s/v2/config/environment_variable_groups/%s", repo.config.ApiEndpoint(), group)
+	err := repo.gateway.GetResource(url, &variables)
+	if err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
 func (repo CloudControllerEnvironmentVariableGroupsRepository) SetStaging(staging_vars string) error {
 	return repo.gateway.UpdateResource(repo.config.ApiEndpoint(), "/v2/config/environment_variable_groups/staging", strings.NewReader(staging_vars))
 }
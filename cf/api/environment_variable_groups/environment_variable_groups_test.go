@@ -77,6 +77,34 @@ var _ = Describe("CloudControllerEnvironmentVariableGroupsRepository", func() {
 		})
 	})
 
+	Describe("ReadRunning", func() {
+		BeforeEach(func() {
+			setupTestServer(listRunningRequest)
+		})
+
+		It("returns the running group with the values as they are", func() {
+			envVars, err := repo.ReadRunning()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler).To(HaveAllRequestsCalled())
+			Expect(envVars).To(Equal(map[string]interface{}{"abc": float64(123), "do-re-mi": "fa-sol-la-ti"}))
+		})
+	})
+
+	Describe("ReadStaging", func() {
+		BeforeEach(func() {
+			setupTestServer(listStagingRequest)
+		})
+
+		It("returns the staging group with the values as they are", func() {
+			envVars, err := repo.ReadStaging()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testHandler).To(HaveAllRequestsCalled())
+			Expect(envVars).To(Equal(map[string]interface{}{"abc": float64(123), "do-re-mi": "fa-sol-la-ti"}))
+		})
+	})
+
 	Describe("SetStaging", func() {
 		BeforeEach(func() {
 			setupTestServer(setStagingRequest)
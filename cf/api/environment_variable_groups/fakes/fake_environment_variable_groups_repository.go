@@ -23,6 +23,20 @@ type FakeEnvironmentVariableGroupsRepository struct {
 		result1 []models.EnvironmentVariable
 		result2 error
 	}
+	ReadRunningStub        func() (map[string]interface{}, error)
+	readRunningMutex       sync.RWMutex
+	readRunningArgsForCall []struct{}
+	readRunningReturns     struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	ReadStagingStub        func() (map[string]interface{}, error)
+	readStagingMutex       sync.RWMutex
+	readStagingArgsForCall []struct{}
+	readStagingReturns     struct {
+		result1 map[string]interface{}
+		result2 error
+	}
 	SetStagingStub        func(string) error
 	setStagingMutex       sync.RWMutex
 	setStagingArgsForCall []struct {
@@ -89,6 +103,54 @@ func (fake *FakeEnvironmentVariableGroupsRepository) ListStagingReturns(result1
 	}{result1, result2}
 }
 
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadRunning() (map[string]interface{}, error) {
+	fake.readRunningMutex.Lock()
+	defer fake.readRunningMutex.Unlock()
+	fake.readRunningArgsForCall = append(fake.readRunningArgsForCall, struct{}{})
+	if fake.ReadRunningStub != nil {
+		return fake.ReadRunningStub()
+	} else {
+		return fake.readRunningReturns.result1, fake.readRunningReturns.result2
+	}
+}
+
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadRunningCallCount() int {
+	fake.readRunningMutex.RLock()
+	defer fake.readRunningMutex.RUnlock()
+	return len(fake.readRunningArgsForCall)
+}
+
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadRunningReturns(result1 map[string]interface{}, result2 error) {
+	fake.readRunningReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadStaging() (map[string]interface{}, error) {
+	fake.readStagingMutex.Lock()
+	defer fake.readStagingMutex.Unlock()
+	fake.readStagingArgsForCall = append(fake.readStagingArgsForCall, struct{}{})
+	if fake.ReadStagingStub != nil {
+		return fake.ReadStagingStub()
+	} else {
+		return fake.readStagingReturns.result1, fake.readStagingReturns.result2
+	}
+}
+
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadStagingCallCount() int {
+	fake.readStagingMutex.RLock()
+	defer fake.readStagingMutex.RUnlock()
+	return len(fake.readStagingArgsForCall)
+}
+
+func (fake *FakeEnvironmentVariableGroupsRepository) ReadStagingReturns(result1 map[string]interface{}, result2 error) {
+	fake.readStagingReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeEnvironmentVariableGroupsRepository) SetStaging(arg1 string) error {
 	fake.setStagingMutex.Lock()
 	defer fake.setStagingMutex.Unlock()
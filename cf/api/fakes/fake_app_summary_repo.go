@@ -8,9 +8,13 @@ import (
 type FakeAppSummaryRepo struct {
 	GetSummariesInCurrentSpaceApps []models.Application
 
+	GetSummariesInSpaceApps map[string][]models.Application
+	GetSummariesInSpaceErr  error
+
 	GetSummaryErrorCode string
 	GetSummaryAppGuid   string
 	GetSummarySummary   models.Application
+	GetSummaryStub      func(appGuid string) (models.Application, error)
 }
 
 func (repo *FakeAppSummaryRepo) GetSummariesInCurrentSpace() (apps []models.Application, apiErr error) {
@@ -18,8 +22,18 @@ func (repo *FakeAppSummaryRepo) GetSummariesInCurrentSpace() (apps []models.Appl
 	return
 }
 
+func (repo *FakeAppSummaryRepo) GetSummariesInSpace(spaceGuid string) (apps []models.Application, apiErr error) {
+	apps = repo.GetSummariesInSpaceApps[spaceGuid]
+	apiErr = repo.GetSummariesInSpaceErr
+	return
+}
+
 func (repo *FakeAppSummaryRepo) GetSummary(appGuid string) (summary models.Application, apiErr error) {
 	repo.GetSummaryAppGuid = appGuid
+	if repo.GetSummaryStub != nil {
+		return repo.GetSummaryStub(appGuid)
+	}
+
 	summary = repo.GetSummarySummary
 
 	if repo.GetSummaryErrorCode != "" {
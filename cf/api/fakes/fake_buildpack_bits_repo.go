@@ -1,6 +1,10 @@
 package fakes
 
 import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
 )
@@ -9,6 +13,10 @@ type FakeBuildpackBitsRepository struct {
 	UploadBuildpackErr         bool
 	UploadBuildpackApiResponse error
 	UploadBuildpackPath        string
+
+	DownloadBuildpackContents string
+	DownloadBuildpackErr      error
+	DownloadBuildpackUrls     []string
 }
 
 func (repo *FakeBuildpackBitsRepository) UploadBuildpack(buildpack models.Buildpack, dir string) error {
@@ -19,3 +27,17 @@ func (repo *FakeBuildpackBitsRepository) UploadBuildpack(buildpack models.Buildp
 	repo.UploadBuildpackPath = dir
 	return repo.UploadBuildpackApiResponse
 }
+
+func (repo *FakeBuildpackBitsRepository) DownloadBuildpack(url string, saveDir string) (int64, string, error) {
+	repo.DownloadBuildpackUrls = append(repo.DownloadBuildpackUrls, url)
+	if repo.DownloadBuildpackErr != nil {
+		return 0, "", repo.DownloadBuildpackErr
+	}
+
+	fileName := path.Base(url)
+	err := ioutil.WriteFile(filepath.Join(saveDir, fileName), []byte(repo.DownloadBuildpackContents), 0600)
+	if err != nil {
+		return 0, "", err
+	}
+	return int64(len(repo.DownloadBuildpackContents)), fileName, nil
+}
@@ -23,10 +23,14 @@ type FakeBuildpackRepository struct {
 	UpdateBuildpackArgs struct {
 		Buildpack models.Buildpack
 	}
+	UpdatedBuildpacks []models.Buildpack
 
 	UpdateBuildpackReturns struct {
 		Error error
 	}
+	// UpdateBuildpackErrors are returned by the calls to Update in order,
+	// before UpdateBuildpackReturns.Error is returned.
+	UpdateBuildpackErrors []error
 }
 
 func (repo *FakeBuildpackRepository) ListBuildpacks(cb func(models.Buildpack) bool) error {
@@ -64,6 +68,12 @@ func (repo *FakeBuildpackRepository) Delete(buildpackGuid string) (apiErr error)
 
 func (repo *FakeBuildpackRepository) Update(buildpack models.Buildpack) (updatedBuildpack models.Buildpack, apiErr error) {
 	repo.UpdateBuildpackArgs.Buildpack = buildpack
+	repo.UpdatedBuildpacks = append(repo.UpdatedBuildpacks, buildpack)
+	if len(repo.UpdateBuildpackErrors) > 0 {
+		apiErr = repo.UpdateBuildpackErrors[0]
+		repo.UpdateBuildpackErrors = repo.UpdateBuildpackErrors[1:]
+		return
+	}
 	apiErr = repo.UpdateBuildpackReturns.Error
 	return
 }
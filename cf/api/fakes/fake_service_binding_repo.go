@@ -9,24 +9,41 @@ type FakeServiceBindingRepo struct {
 	CreateServiceInstanceGuid string
 	CreateApplicationGuid     string
 	CreateErrorCode           string
+	CreateErrorCodes          []string
 	CreateParams              map[string]interface{}
+	CreatedApplicationGuids   []string
 
-	DeleteServiceInstance models.ServiceInstance
-	DeleteApplicationGuid string
-	DeleteBindingNotFound bool
-	CreateNonHttpErrCode  string
+	DeleteServiceInstance   models.ServiceInstance
+	DeleteApplicationGuid   string
+	DeletedApplicationGuids []string
+	DeleteBindingNotFound   bool
+	CreateNonHttpErrCode    string
+
+	ListAllForServiceInstanceGuid string
+	ListAllForServiceBindings     []models.ServiceBindingFields
+	ListAllForServiceError        error
 }
 
 func (repo *FakeServiceBindingRepo) Create(instanceGuid, appGuid string, paramsMap map[string]interface{}) (apiErr error) {
 	repo.CreateServiceInstanceGuid = instanceGuid
 	repo.CreateApplicationGuid = appGuid
 	repo.CreateParams = paramsMap
+	repo.CreatedApplicationGuids = append(repo.CreatedApplicationGuids, appGuid)
 
 	if repo.CreateNonHttpErrCode != "" {
 		apiErr = errors.New(repo.CreateNonHttpErrCode)
 		return
 	}
 
+	if len(repo.CreateErrorCodes) > 0 {
+		errorCode := repo.CreateErrorCodes[0]
+		repo.CreateErrorCodes = repo.CreateErrorCodes[1:]
+		if errorCode != "" {
+			apiErr = errors.NewHttpError(400, errorCode, "Error binding service")
+		}
+		return
+	}
+
 	if repo.CreateErrorCode != "" {
 		apiErr = errors.NewHttpError(400, repo.CreateErrorCode, "Error binding service")
 	}
@@ -37,6 +54,12 @@ func (repo *FakeServiceBindingRepo) Create(instanceGuid, appGuid string, paramsM
 func (repo *FakeServiceBindingRepo) Delete(instance models.ServiceInstance, appGuid string) (found bool, apiErr error) {
 	repo.DeleteServiceInstance = instance
 	repo.DeleteApplicationGuid = appGuid
+	repo.DeletedApplicationGuids = append(repo.DeletedApplicationGuids, appGuid)
 	found = !repo.DeleteBindingNotFound
 	return
 }
+
+func (repo *FakeServiceBindingRepo) ListAllForService(instanceGuid string) ([]models.ServiceBindingFields, error) {
+	repo.ListAllForServiceInstanceGuid = instanceGuid
+	return repo.ListAllForServiceBindings, repo.ListAllForServiceError
+}
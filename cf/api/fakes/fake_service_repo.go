@@ -58,12 +58,15 @@ type FakeServiceRepo struct {
 	UpdateServiceInstanceReturnsErr bool
 
 	FindInstanceByNameName            string
+	FindInstanceByNameStub            func(name string) (models.ServiceInstance, error)
 	FindInstanceByNameServiceInstance models.ServiceInstance
 	FindInstanceByNameErr             bool
 	FindInstanceByNameNotFound        bool
 
 	FindInstanceByNameMap generic.Map
 
+	FindInstanceByNameInSpaceSpaceGuid string
+
 	DeleteServiceServiceInstance models.ServiceInstance
 
 	RenameServiceServiceInstance models.ServiceInstance
@@ -175,6 +178,10 @@ func (repo *FakeServiceRepo) UpdateServiceInstance(instanceGuid, planGuid string
 func (repo *FakeServiceRepo) FindInstanceByName(name string) (instance models.ServiceInstance, apiErr error) {
 	repo.FindInstanceByNameName = name
 
+	if repo.FindInstanceByNameStub != nil {
+		return repo.FindInstanceByNameStub(name)
+	}
+
 	if repo.FindInstanceByNameMap != nil && repo.FindInstanceByNameMap.Has(name) {
 		instance = repo.FindInstanceByNameMap.Get(name).(models.ServiceInstance)
 	} else {
@@ -192,6 +199,11 @@ func (repo *FakeServiceRepo) FindInstanceByName(name string) (instance models.Se
 	return
 }
 
+func (repo *FakeServiceRepo) FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiErr error) {
+	repo.FindInstanceByNameInSpaceSpaceGuid = spaceGuid
+	return repo.FindInstanceByName(name)
+}
+
 func (repo *FakeServiceRepo) DeleteService(instance models.ServiceInstance) (apiErr error) {
 	repo.DeleteServiceServiceInstance = instance
 	return
@@ -4,9 +4,18 @@ import "github.com/cloudfoundry/cli/cf/models"
 
 type FakeServiceSummaryRepo struct {
 	GetSummariesInCurrentSpaceInstances []models.ServiceInstance
+
+	GetSummariesInSpaceInstances map[string][]models.ServiceInstance
+	GetSummariesInSpaceErr       error
 }
 
 func (repo *FakeServiceSummaryRepo) GetSummariesInCurrentSpace() (instances []models.ServiceInstance, apiErr error) {
 	instances = repo.GetSummariesInCurrentSpaceInstances
 	return
 }
+
+func (repo *FakeServiceSummaryRepo) GetSummariesInSpace(spaceGuid string) (instances []models.ServiceInstance, apiErr error) {
+	instances = repo.GetSummariesInSpaceInstances[spaceGuid]
+	apiErr = repo.GetSummariesInSpaceErr
+	return
+}
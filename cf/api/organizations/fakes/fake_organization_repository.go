@@ -34,6 +34,15 @@ type FakeOrganizationRepository struct {
 		result1 models.Organization
 		result2 error
 	}
+	GetMemoryUsageStub        func(orgGuid string) (memoryInMegabytes int64, apiErr error)
+	getMemoryUsageMutex       sync.RWMutex
+	getMemoryUsageArgsForCall []struct {
+		orgGuid string
+	}
+	getMemoryUsageReturns struct {
+		result1 int64
+		result2 error
+	}
 	CreateStub        func(org models.Organization) (apiErr error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
@@ -170,6 +179,39 @@ func (fake *FakeOrganizationRepository) FindByNameReturns(result1 models.Organiz
 	}{result1, result2}
 }
 
+func (fake *FakeOrganizationRepository) GetMemoryUsage(orgGuid string) (memoryInMegabytes int64, apiErr error) {
+	fake.getMemoryUsageMutex.Lock()
+	fake.getMemoryUsageArgsForCall = append(fake.getMemoryUsageArgsForCall, struct {
+		orgGuid string
+	}{orgGuid})
+	fake.getMemoryUsageMutex.Unlock()
+	if fake.GetMemoryUsageStub != nil {
+		return fake.GetMemoryUsageStub(orgGuid)
+	} else {
+		return fake.getMemoryUsageReturns.result1, fake.getMemoryUsageReturns.result2
+	}
+}
+
+func (fake *FakeOrganizationRepository) GetMemoryUsageCallCount() int {
+	fake.getMemoryUsageMutex.RLock()
+	defer fake.getMemoryUsageMutex.RUnlock()
+	return len(fake.getMemoryUsageArgsForCall)
+}
+
+func (fake *FakeOrganizationRepository) GetMemoryUsageArgsForCall(i int) string {
+	fake.getMemoryUsageMutex.RLock()
+	defer fake.getMemoryUsageMutex.RUnlock()
+	return fake.getMemoryUsageArgsForCall[i].orgGuid
+}
+
+func (fake *FakeOrganizationRepository) GetMemoryUsageReturns(result1 int64, result2 error) {
+	fake.GetMemoryUsageStub = nil
+	fake.getMemoryUsageReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeOrganizationRepository) Create(org models.Organization) (apiErr error) {
 	fake.createMutex.Lock()
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
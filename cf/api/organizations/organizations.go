@@ -17,6 +17,7 @@ type OrganizationRepository interface {
 	ListOrgs() (orgs []models.Organization, apiErr error)
 	GetManyOrgsByGuid(orgGuids []string) (orgs []models.Organization, apiErr error)
 	FindByName(name string) (org models.Organization, apiErr error)
+	GetMemoryUsage(orgGuid string) (memoryInMegabytes int64, apiErr error)
 	Create(org models.Organization) (apiErr error)
 	Rename(orgGuid string, name string) (apiErr error)
 	Delete(orgGuid string) (apiErr error)
@@ -86,6 +87,19 @@ func (repo CloudControllerOrganizationRepository) FindByName(name string) (org m
 	return
 }
 
+// GetMemoryUsage returns the memory that counts against the quota of the org:
+// the memory of every instance of its started apps.
+func (repo CloudControllerOrganizationRepository) GetMemoryUsage(orgGuid string) (memoryInMegabytes int64, apiErr error) {
+	usage := struct {
+		MemoryUsage int64 `json:"memory_usage_in_mb"`
+	}{}
+	apiErr = repo.gateway.GetResource(
+		fmt.Sprintf("%s/v2/organizations/%s/memory_usage", repo.config.ApiEndpoint(), orgGuid),
+		&usage)
+
+	return usage.MemoryUsage, apiErr
+}
+
 func (repo CloudControllerOrganizationRepository) Create(org models.Organization) (apiErr error) {
 	data := fmt.Sprintf(`{"name":"%s"`, org.Name)
 	if org.QuotaDefinition.Guid != "" {
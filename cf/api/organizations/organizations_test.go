@@ -201,6 +201,24 @@ var _ = Describe("Organization Repository", func() {
 		})
 	})
 
+	Describe("GetMemoryUsage", func() {
+		It("returns the memory used by the org", func() {
+			req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method:   "GET",
+				Path:     "/v2/organizations/my-org-guid/memory_usage",
+				Response: testnet.TestResponse{Status: http.StatusOK, Body: `{"memory_usage_in_mb": 2048}`},
+			})
+
+			testserver, handler, repo := createOrganizationRepo(req)
+			defer testserver.Close()
+
+			memoryUsage, apiErr := repo.GetMemoryUsage("my-org-guid")
+			Expect(handler).To(HaveAllRequestsCalled())
+			Expect(apiErr).NotTo(HaveOccurred())
+			Expect(memoryUsage).To(Equal(int64(2048)))
+		})
+	})
+
 	Describe(".Create", func() {
 		It("creates the org and sends only the org name if the quota flag is not provided", func() {
 			org := models.Organization{
@@ -41,7 +41,8 @@ type RepositoryLocator struct {
 	quotaRepo                       quotas.QuotaRepository
 	spaceRepo                       spaces.SpaceRepository
 	appRepo                         applications.ApplicationRepository
-	appBitsRepo                     application_bits.CloudControllerApplicationBitsRepository
+	appBitsRepo                     application_bits.ApplicationBitsRepository
+	appDropletRepo                  application_bits.ApplicationDropletRepository
 	appSummaryRepo                  AppSummaryRepository
 	appInstancesRepo                app_instances.AppInstancesRepository
 	appEventsRepo                   app_events.AppEventsRepository
@@ -95,6 +96,7 @@ func NewRepositoryLocator(config core_config.ReadWriter, gatewaysByName map[stri
 	logNoaaConsumer := NewNoaaConsumer(noaaLib)
 
 	loc.appBitsRepo = application_bits.NewCloudControllerApplicationBitsRepository(config, cloudControllerGateway)
+	loc.appDropletRepo = application_bits.NewCloudControllerApplicationDropletRepository(config, cloudControllerGateway)
 	loc.appEventsRepo = app_events.NewCloudControllerAppEventsRepository(config, cloudControllerGateway, strategy)
 	loc.appFilesRepo = api_app_files.NewCloudControllerAppFilesRepository(config, cloudControllerGateway)
 	loc.appRepo = applications.NewCloudControllerApplicationRepository(config, cloudControllerGateway)
@@ -197,10 +199,24 @@ func (locator RepositoryLocator) GetApplicationRepository() applications.Applica
 	return locator.appRepo
 }
 
+func (locator RepositoryLocator) SetApplicationBitsRepository(repo application_bits.ApplicationBitsRepository) RepositoryLocator {
+	locator.appBitsRepo = repo
+	return locator
+}
+
 func (locator RepositoryLocator) GetApplicationBitsRepository() application_bits.ApplicationBitsRepository {
 	return locator.appBitsRepo
 }
 
+func (locator RepositoryLocator) SetApplicationDropletRepository(repo application_bits.ApplicationDropletRepository) RepositoryLocator {
+	locator.appDropletRepo = repo
+	return locator
+}
+
+func (locator RepositoryLocator) GetApplicationDropletRepository() application_bits.ApplicationDropletRepository {
+	return locator.appDropletRepo
+}
+
 func (locator RepositoryLocator) SetAppSummaryRepository(repo AppSummaryRepository) RepositoryLocator {
 	locator.appSummaryRepo = repo
 	return locator
@@ -20,7 +20,10 @@ type EventResourceNewV2 struct {
 	Entity struct {
 		Timestamp time.Time
 		Type      string
+		Actor     string
 		ActorName string `json:"actor_name"`
+		ActeeType string `json:"actee_type"`
+		ActeeName string `json:"actee_name"`
 		Metadata  map[string]interface{}
 	}
 }
@@ -47,6 +50,9 @@ func (resource EventResourceNewV2) ToFields() models.EventFields {
 		Timestamp:   resource.Entity.Timestamp,
 		Description: formatDescription(metadata, knownMetadataKeys),
 		ActorName:   resource.Entity.ActorName,
+		Actor:       resource.Entity.Actor,
+		ActeeType:   resource.Entity.ActeeType,
+		ActeeName:   resource.Entity.ActeeName,
 	}
 }
 
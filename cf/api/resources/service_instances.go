@@ -23,6 +23,7 @@ type LastOperation struct {
 type ServiceInstanceEntity struct {
 	Name            string
 	DashboardUrl    string                   `json:"dashboard_url"`
+	Tags            []string                 `json:"tags"`
 	ServiceBindings []ServiceBindingResource `json:"service_bindings"`
 	ServiceKeys     []ServiceKeyResource     `json:"service_keys"`
 	ServicePlan     ServicePlanResource      `json:"service_plan"`
@@ -34,6 +35,7 @@ func (resource ServiceInstanceResource) ToFields() models.ServiceInstanceFields
 		Guid:         resource.Metadata.Guid,
 		Name:         resource.Entity.Name,
 		DashboardUrl: resource.Entity.DashboardUrl,
+		Tags:         resource.Entity.Tags,
 		LastOperation: models.LastOperationFields{
 			Type:        resource.Entity.LastOperation.Type,
 			State:       resource.Entity.LastOperation.State,
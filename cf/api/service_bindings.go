@@ -3,7 +3,9 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 
+	"github.com/cloudfoundry/cli/cf/api/resources"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/net"
@@ -12,6 +14,7 @@ import (
 type ServiceBindingRepository interface {
 	Create(instanceGuid, appGuid string, paramsMap map[string]interface{}) (apiErr error)
 	Delete(instance models.ServiceInstance, appGuid string) (found bool, apiErr error)
+	ListAllForService(instanceGuid string) ([]models.ServiceBindingFields, error)
 }
 
 type CloudControllerServiceBindingRepository struct {
@@ -60,3 +63,17 @@ func (repo CloudControllerServiceBindingRepository) Delete(instance models.Servi
 	apiErr = repo.gateway.DeleteResource(repo.config.ApiEndpoint(), path)
 	return
 }
+
+func (repo CloudControllerServiceBindingRepository) ListAllForService(instanceGuid string) ([]models.ServiceBindingFields, error) {
+	bindings := []models.ServiceBindingFields{}
+	err := repo.gateway.ListPaginatedResources(
+		repo.config.ApiEndpoint(),
+		fmt.Sprintf("/v2/service_instances/%s/service_bindings", instanceGuid),
+		resources.ServiceBindingResource{},
+		func(resource interface{}) bool {
+			bindings = append(bindings, resource.(resources.ServiceBindingResource).ToFields())
+			return true
+		})
+
+	return bindings, err
+}
@@ -163,4 +163,39 @@ var _ = Describe("ServiceBindingsRepository", func() {
 			})
 		})
 	})
+
+	Describe("ListAllForService", func() {
+		BeforeEach(func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method: "GET",
+				Path:   "/v2/service_instances/my-service-instance-guid/service_bindings",
+				Response: testnet.TestResponse{
+					Status: http.StatusOK,
+					Body: `{
+						"resources": [
+							{
+								"metadata": {"guid": "service-binding-1-guid", "url": "/v2/service_bindings/service-binding-1-guid"},
+								"entity": {"app_guid": "app-1-guid"}
+							},
+							{
+								"metadata": {"guid": "service-binding-2-guid", "url": "/v2/service_bindings/service-binding-2-guid"},
+								"entity": {"app_guid": "app-2-guid"}
+							}
+						]
+					}`,
+				},
+			}))
+		})
+
+		It("lists the bindings of the service instance", func() {
+			bindings, err := repo.ListAllForService("my-service-instance-guid")
+
+			Expect(testHandler).To(HaveAllRequestsCalled())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bindings).To(Equal([]models.ServiceBindingFields{
+				{Guid: "service-binding-1-guid", Url: "/v2/service_bindings/service-binding-1-guid", AppGuid: "app-1-guid"},
+				{Guid: "service-binding-2-guid", Url: "/v2/service_bindings/service-binding-2-guid", AppGuid: "app-2-guid"},
+			}))
+		})
+	})
 })
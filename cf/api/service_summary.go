@@ -33,6 +33,7 @@ func (resource ServiceInstancesSummaries) ToModels() (instances []models.Service
 		instance.LastOperation.Type = instanceSummary.LastOperation.Type
 		instance.LastOperation.State = instanceSummary.LastOperation.State
 		instance.LastOperation.Description = instanceSummary.LastOperation.Description
+		instance.LastOperation.UpdatedAt = instanceSummary.LastOperation.UpdatedAt
 		instance.ApplicationNames = applicationNames
 		instance.ServicePlan = servicePlan
 		instance.ServiceOffering = serviceOffering
@@ -64,6 +65,7 @@ type LastOperationSummary struct {
 	Type        string `json:"type"`
 	State       string `json:"state"`
 	Description string `json:"description"`
+	UpdatedAt   string `json:"updated_at"`
 }
 
 type ServiceInstanceSummary struct {
@@ -86,6 +88,7 @@ type ServiceOfferingSummary struct {
 
 type ServiceSummaryRepository interface {
 	GetSummariesInCurrentSpace() (instances []models.ServiceInstance, apiErr error)
+	GetSummariesInSpace(spaceGuid string) (instances []models.ServiceInstance, apiErr error)
 }
 
 type CloudControllerServiceSummaryRepository struct {
@@ -100,7 +103,11 @@ func NewCloudControllerServiceSummaryRepository(config core_config.Reader, gatew
 }
 
 func (repo CloudControllerServiceSummaryRepository) GetSummariesInCurrentSpace() (instances []models.ServiceInstance, apiErr error) {
-	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.ApiEndpoint(), repo.config.SpaceFields().Guid)
+	return repo.GetSummariesInSpace(repo.config.SpaceFields().Guid)
+}
+
+func (repo CloudControllerServiceSummaryRepository) GetSummariesInSpace(spaceGuid string) (instances []models.ServiceInstance, apiErr error) {
+	path := fmt.Sprintf("%s/v2/spaces/%s/summary", repo.config.ApiEndpoint(), spaceGuid)
 	resource := new(ServiceInstancesSummaries)
 
 	apiErr = repo.gateway.GetResource(path, resource)
@@ -44,7 +44,8 @@ var _ = Describe("ServiceSummaryRepository", func() {
 					  "last_operation": {
 						  "type": "create",
 						  "state": "in progress",
-							"description": "50% done"
+							"description": "50% done",
+							"updated_at": "2015-07-15T21:08:56Z"
 					  },
 						"service_plan": {
 							"guid": "service-plan-guid",
@@ -83,6 +84,7 @@ var _ = Describe("ServiceSummaryRepository", func() {
 		Expect(instance1.LastOperation.Type).To(Equal("create"))
 		Expect(instance1.LastOperation.State).To(Equal("in progress"))
 		Expect(instance1.LastOperation.Description).To(Equal("50% done"))
+		Expect(instance1.LastOperation.UpdatedAt).To(Equal("2015-07-15T21:08:56Z"))
 		Expect(instance1.ServicePlan.Name).To(Equal("spark"))
 		Expect(instance1.ServiceOffering.Label).To(Equal("cleardb"))
 		Expect(instance1.ServiceOffering.Label).To(Equal("cleardb"))
@@ -92,6 +94,25 @@ var _ = Describe("ServiceSummaryRepository", func() {
 		Expect(instance1.ApplicationNames[0]).To(Equal("app1"))
 		Expect(instance1.ApplicationNames[1]).To(Equal("app2"))
 	})
+
+	It("gets a summary of services in another space", func() {
+		req := testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+			Method:   "GET",
+			Path:     "/v2/spaces/other-space-guid/summary",
+			Response: serviceInstanceSummariesResponse,
+		})
+
+		ts, handler, repo := createServiceSummaryRepo(req)
+		defer ts.Close()
+
+		serviceInstances, apiErr := repo.GetSummariesInSpace("other-space-guid")
+		Expect(handler).To(HaveAllRequestsCalled())
+
+		Expect(apiErr).NotTo(HaveOccurred())
+		Expect(len(serviceInstances)).To(Equal(1))
+		Expect(serviceInstances[0].Name).To(Equal("my-service-instance"))
+		Expect(serviceInstances[0].IsUserProvided()).To(BeFalse())
+	})
 })
 
 func createServiceSummaryRepo(req testnet.TestRequest) (ts *httptest.Server, handler *testnet.TestHandler, repo ServiceSummaryRepository) {
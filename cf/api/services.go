@@ -25,6 +25,7 @@ type ServiceRepository interface {
 	GetAllServiceOfferings() (offerings models.ServiceOfferings, apiErr error)
 	GetServiceOfferingsForSpace(spaceGuid string) (offerings models.ServiceOfferings, apiErr error)
 	FindInstanceByName(name string) (instance models.ServiceInstance, apiErr error)
+	FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiErr error)
 	CreateServiceInstance(name, planGuid string, params map[string]interface{}, tags []string) (apiErr error)
 	UpdateServiceInstance(instanceGuid, planGuid string, params map[string]interface{}, tags []string) (apiErr error)
 	RenameService(instance models.ServiceInstance, newName string) (apiErr error)
@@ -109,7 +110,11 @@ func (repo CloudControllerServiceRepository) getServiceOfferings(path string) ([
 }
 
 func (repo CloudControllerServiceRepository) FindInstanceByName(name string) (instance models.ServiceInstance, apiErr error) {
-	path := fmt.Sprintf("%s/v2/spaces/%s/service_instances?return_user_provided_service_instances=true&q=%s&inline-relations-depth=1", repo.config.ApiEndpoint(), repo.config.SpaceFields().Guid, url.QueryEscape("name:"+name))
+	return repo.FindInstanceByNameInSpace(name, repo.config.SpaceFields().Guid)
+}
+
+func (repo CloudControllerServiceRepository) FindInstanceByNameInSpace(name, spaceGuid string) (instance models.ServiceInstance, apiErr error) {
+	path := fmt.Sprintf("%s/v2/spaces/%s/service_instances?return_user_provided_service_instances=true&q=%s&inline-relations-depth=1", repo.config.ApiEndpoint(), spaceGuid, url.QueryEscape("name:"+name))
 
 	responseJSON := new(resources.PaginatedServiceInstanceResources)
 	apiErr = repo.gateway.GetResource(path, responseJSON)
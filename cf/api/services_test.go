@@ -565,6 +565,20 @@ var _ = Describe("Services Repo", func() {
 			Expect(testHandler).To(HaveAllRequestsCalled())
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("finds service instances in other spaces", func() {
+			setupTestServer(testapi.NewCloudControllerTestRequest(testnet.TestRequest{
+				Method:   "GET",
+				Path:     "/v2/spaces/other-space-guid/service_instances?return_user_provided_service_instances=true&q=name%3Amy-service",
+				Response: testnet.TestResponse{Status: http.StatusOK, Body: `{ "resources": [ { "metadata": { "guid": "other-instance-guid" }, "entity": { "name": "my-service" } } ] }`},
+			}))
+
+			instance, err := repo.FindInstanceByNameInSpace("my-service", "other-space-guid")
+
+			Expect(testHandler).To(HaveAllRequestsCalled())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instance.Guid).To(Equal("other-instance-guid"))
+		})
 	})
 
 	Describe("DeleteService", func() {
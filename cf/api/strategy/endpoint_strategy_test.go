@@ -1,8 +1,11 @@
 package strategy_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry/cli/cf/api/resources"
 	. "github.com/cloudfoundry/cli/cf/api/strategy"
+	"github.com/cloudfoundry/cli/cf/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -31,6 +34,11 @@ var _ = Describe("EndpointStrategy", func() {
 				Expect(strategy.EventsURL("the-guid", 20)).To(Equal("/v2/apps/the-guid/events?results-per-page=20"))
 			})
 
+			It("only scopes searches to the app", func() {
+				filter := models.EventsFilter{AppGuid: "the-guid", Types: []string{"audit.app.update"}}
+				Expect(strategy.EventsSearchURL(filter, 20)).To(Equal("/v2/apps/the-guid/events?results-per-page=20"))
+			})
+
 			It("returns an old EventResource", func() {
 				Expect(strategy.EventsResource()).To(BeAssignableToTypeOf(resources.EventResourceOldV2{}))
 			})
@@ -45,6 +53,29 @@ var _ = Describe("EndpointStrategy", func() {
 				Expect(strategy.EventsURL("guids-r-us", 42)).To(Equal("/v2/events?order-direction=desc&q=actee%3Aguids-r-us&results-per-page=42"))
 			})
 
+			It("filters searches on the global events endpoint", func() {
+				filter := models.EventsFilter{
+					SpaceGuid: "space-guid",
+					Types:     []string{"audit.app.update", "audit.app.delete-request"},
+					Since:     time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC),
+					Until:     time.Date(2015, 6, 30, 12, 0, 0, 0, time.UTC),
+				}
+
+				Expect(strategy.EventsSearchURL(filter, 100)).To(Equal("/v2/events?order-direction=desc" +
+					"&q=space_guid%3Aspace-guid" +
+					"&q=type+IN+audit.app.update%2Caudit.app.delete-request" +
+					"&q=timestamp%3E%3D2015-06-01T00%3A00%3A00Z" +
+					"&q=timestamp%3C%3D2015-06-30T12%3A00%3A00Z" +
+					"&results-per-page=100"))
+			})
+
+			It("scopes searches to an app or an org", func() {
+				Expect(strategy.EventsSearchURL(models.EventsFilter{AppGuid: "app-guid", Types: []string{"audit.app.update"}}, 50)).
+					To(Equal("/v2/events?order-direction=desc&q=actee%3Aapp-guid&q=type%3Aaudit.app.update&results-per-page=50"))
+				Expect(strategy.EventsSearchURL(models.EventsFilter{OrgGuid: "org-guid"}, 50)).
+					To(Equal("/v2/events?order-direction=desc&q=organization_guid%3Aorg-guid&results-per-page=50"))
+			})
+
 			It("returns a new EventResource", func() {
 				Expect(strategy.EventsResource()).To(BeAssignableToTypeOf(resources.EventResourceNewV2{}))
 			})
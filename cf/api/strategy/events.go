@@ -1,9 +1,16 @@
 package strategy
 
-import "github.com/cloudfoundry/cli/cf/api/resources"
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	"github.com/cloudfoundry/cli/cf/models"
+)
 
 type EventsEndpointStrategy interface {
 	EventsURL(appGuid string, limit int64) string
+	EventsSearchURL(filter models.EventsFilter, limit int64) string
 	EventsResource() resources.EventResource
 }
 
@@ -15,6 +22,12 @@ func (_ eventsEndpointStrategy) EventsURL(appGuid string, limit int64) string {
 	})
 }
 
+// the app events endpoint cannot be filtered, so only the app scope is used
+// and the rest of the filter has to be applied to the results
+func (strategy eventsEndpointStrategy) EventsSearchURL(filter models.EventsFilter, limit int64) string {
+	return strategy.EventsURL(filter.AppGuid, limit)
+}
+
 func (_ eventsEndpointStrategy) EventsResource() resources.EventResource {
 	return resources.EventResourceOldV2{}
 }
@@ -29,6 +42,39 @@ func (strategy globalEventsEndpointStrategy) EventsURL(appGuid string, limit int
 	})
 }
 
+func (strategy globalEventsEndpointStrategy) EventsSearchURL(filter models.EventsFilter, limit int64) string {
+	filters := []string{}
+
+	switch {
+	case filter.AppGuid != "":
+		filters = append(filters, "actee:"+filter.AppGuid)
+	case filter.SpaceGuid != "":
+		filters = append(filters, "space_guid:"+filter.SpaceGuid)
+	case filter.OrgGuid != "":
+		filters = append(filters, "organization_guid:"+filter.OrgGuid)
+	}
+
+	if len(filter.Types) == 1 {
+		filters = append(filters, "type:"+filter.Types[0])
+	} else if len(filter.Types) > 1 {
+		filters = append(filters, "type IN "+strings.Join(filter.Types, ","))
+	}
+
+	if !filter.Since.IsZero() {
+		filters = append(filters, "timestamp>="+filter.Since.UTC().Format(time.RFC3339))
+	}
+
+	if !filter.Until.IsZero() {
+		filters = append(filters, "timestamp<="+filter.Until.UTC().Format(time.RFC3339))
+	}
+
+	return buildURL(v2("events"), params{
+		resultsPerPage: limit,
+		orderDirection: "desc",
+		filters:        filters,
+	})
+}
+
 func (_ globalEventsEndpointStrategy) EventsResource() resources.EventResource {
 	return resources.EventResourceNewV2{}
 }
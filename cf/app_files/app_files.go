@@ -1,8 +1,6 @@
 package app_files
 
 import (
-	"crypto/sha1"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -16,9 +14,19 @@ type AppFiles interface {
 	CopyFiles(appFiles []models.AppFileFields, fromDir, toDir string) (err error)
 	CountFiles(directory string) int64
 	WalkAppFiles(dir string, onEachFile func(string, string) error) (err error)
+	IgnoredFiles(dir string) (ignoredFiles []string, err error)
 }
 
-type ApplicationFiles struct{}
+type ApplicationFiles struct {
+	// FingerprintCacheDir is where the SHA1 of app files are cached between
+	// pushes. No cache is kept when it is empty.
+	FingerprintCacheDir string
+
+	// UseGitignore also excludes the files matched by .gitignore files. The
+	// patterns of a .cfignore take precedence over those of a .gitignore in
+	// the same directory.
+	UseGitignore bool
+}
 
 func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
 	dir, err = filepath.Abs(dir)
@@ -26,6 +34,9 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 		return
 	}
 
+	cache := loadFingerprintCache(appfiles.FingerprintCacheDir, dir)
+	filesToHash := []fileToHash{}
+
 	err = appfiles.WalkAppFiles(dir, func(fileName string, fullPath string) (err error) {
 		fileInfo, err := os.Lstat(fullPath)
 		if err != nil {
@@ -40,18 +51,32 @@ func (appfiles ApplicationFiles) AppFilesInDir(dir string) (appFiles []models.Ap
 		if fileInfo.IsDir() {
 			appFile.Sha1 = "0"
 			appFile.Size = 0
+		} else if sha, found := cache.lookup(appFile.Path, fileInfo); found {
+			appFile.Sha1 = sha
 		} else {
-			hash := sha1.New()
-			err = fileutils.CopyPathToWriter(fullPath, hash)
-			if err != nil {
-				return
-			}
-			appFile.Sha1 = fmt.Sprintf("%x", hash.Sum(nil))
+			filesToHash = append(filesToHash, fileToHash{
+				index:    len(appFiles),
+				fileName: appFile.Path,
+				fullPath: fullPath,
+				fileInfo: fileInfo,
+			})
 		}
 
 		appFiles = append(appFiles, appFile)
 		return
 	})
+	if err != nil {
+		return
+	}
+
+	err = hashFiles(filesToHash, func(index int, sha string) {
+		appFiles[index].Sha1 = sha
+	}, cache)
+	if err != nil {
+		return
+	}
+
+	cache.save()
 	return
 }
 
@@ -81,7 +106,28 @@ func (appfiles ApplicationFiles) CountFiles(directory string) int64 {
 }
 
 func (appfiles ApplicationFiles) WalkAppFiles(dir string, onEachFile func(string, string) error) (err error) {
-	cfIgnore := loadIgnoreFile(dir)
+	return appfiles.walkAppFiles(dir, onEachFile, func(string, bool) {})
+}
+
+// IgnoredFiles lists the files and directories in dir that are excluded by
+// the ignore files. Directories are listed with a trailing '/', and their
+// contents are not listed.
+func (appfiles ApplicationFiles) IgnoredFiles(dir string) (ignoredFiles []string, err error) {
+	err = appfiles.walkAppFiles(dir, func(_, _ string) error {
+		return nil
+	}, func(fileRelativeUnixPath string, isDir bool) {
+		if isDir {
+			fileRelativeUnixPath += "/"
+		}
+		ignoredFiles = append(ignoredFiles, fileRelativeUnixPath)
+	})
+	return
+}
+
+func (appfiles ApplicationFiles) walkAppFiles(dir string, onEachFile func(string, string) error, onIgnoredFile func(string, bool)) (err error) {
+	cfIgnore := NewCfIgnore("").(*cfIgnore)
+	appfiles.loadIgnoreFiles(cfIgnore, dir, "")
+
 	walkFunc := func(fullPath string, f os.FileInfo, inErr error) (err error) {
 		err = inErr
 		if err != nil {
@@ -99,10 +145,24 @@ func (appfiles ApplicationFiles) WalkAppFiles(dir string, onEachFile func(string
 		fileRelativePath, _ := filepath.Rel(dir, fullPath)
 		fileRelativeUnixPath := filepath.ToSlash(fileRelativePath)
 
-		if !cfIgnore.FileShouldBeIgnored(fileRelativeUnixPath) {
-			err = onEachFile(fileRelativePath, fullPath)
+		ignorePath := fileRelativeUnixPath
+		if f.IsDir() {
+			ignorePath += "/"
 		}
 
+		if cfIgnore.FileShouldBeIgnored(ignorePath) {
+			onIgnoredFile(fileRelativeUnixPath, f.IsDir())
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+			return
+		}
+
+		if f.IsDir() {
+			appfiles.loadIgnoreFiles(cfIgnore, fullPath, fileRelativeUnixPath)
+		}
+
+		err = onEachFile(fileRelativePath, fullPath)
 		return
 	}
 
@@ -136,11 +196,18 @@ func copyPathToPath(fromPath, toPath string) (err error) {
 	return err
 }
 
-func loadIgnoreFile(dir string) CfIgnore {
-	fileContents, err := ioutil.ReadFile(filepath.Join(dir, ".cfignore"))
-	if err == nil {
-		return NewCfIgnore(string(fileContents))
-	} else {
-		return NewCfIgnore("")
+// loadIgnoreFiles adds the patterns of the ignore files in fullPath, which is
+// at relativePath in the app directory.
+func (appfiles ApplicationFiles) loadIgnoreFiles(ignore *cfIgnore, fullPath string, relativePath string) {
+	fileNames := []string{".cfignore"}
+	if appfiles.UseGitignore {
+		fileNames = []string{".gitignore", ".cfignore"}
+	}
+
+	for _, fileName := range fileNames {
+		fileContents, err := ioutil.ReadFile(filepath.Join(fullPath, fileName))
+		if err == nil {
+			ignore.addPatterns(relativePath, string(fileContents))
+		}
 	}
 }
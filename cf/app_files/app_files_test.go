@@ -1,9 +1,16 @@
 package app_files_test
 
 import (
-	. "github.com/cloudfoundry/cli/cf/app_files"
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry/cli/cf/app_files"
 
 	"github.com/cloudfoundry/cli/cf/models"
 	cffileutils "github.com/cloudfoundry/cli/fileutils"
@@ -37,19 +44,90 @@ var _ = Describe("AppFiles", func() {
 				paths = append(paths, file.Path)
 			}
 
+			// As with git, dir1/child-dir/file3.txt cannot be included again
+			// because its directory is excluded by dir1/**/*
 			Expect(paths).To(Equal([]string{
 				"dir1",
-				"dir1/child-dir",
-				"dir1/child-dir/file3.txt",
 				"dir1/file1.txt",
 				"dir2",
-
-				// TODO: this should be excluded.
-				// .cfignore doesn't handle ** patterns right now
-				"dir2/child-dir2",
 			}))
 		})
 
+		Context("with ignore files in subdirectories", func() {
+			var appDir string
+
+			BeforeEach(func() {
+				var err error
+				appDir, err = ioutil.TempDir("", "nested-cfignore")
+				Expect(err).NotTo(HaveOccurred())
+
+				writeFile := func(path string, contents string) {
+					fullPath := filepath.Join(appDir, filepath.FromSlash(path))
+					Expect(os.MkdirAll(filepath.Dir(fullPath), 0755)).To(Succeed())
+					Expect(ioutil.WriteFile(fullPath, []byte(contents), 0644)).To(Succeed())
+				}
+
+				writeFile(".cfignore", "*.log\n")
+				writeFile(".gitignore", "secrets.txt\n")
+				writeFile("app.rb", "")
+				writeFile("secrets.txt", "")
+				writeFile("debug.log", "")
+				writeFile("lib/.cfignore", "generated/\n/local.rb\n!keep.log\n")
+				writeFile("lib/local.rb", "")
+				writeFile("lib/keep.log", "")
+				writeFile("lib/generated/code.rb", "")
+				writeFile("lib/nested/local.rb", "")
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(appDir)
+			})
+
+			filePaths := func(files []models.AppFileFields) []string {
+				paths := []string{}
+				for _, file := range files {
+					paths = append(paths, file.Path)
+				}
+				return paths
+			}
+
+			It("applies the patterns of each .cfignore to its directory", func() {
+				files, err := appFiles.AppFilesInDir(appDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filePaths(files)).To(Equal([]string{
+					"app.rb",
+					"lib",
+					"lib/keep.log",
+					"lib/nested",
+					"lib/nested/local.rb",
+					"secrets.txt",
+				}))
+			})
+
+			It("also applies .gitignore files when asked to", func() {
+				files, err := ApplicationFiles{UseGitignore: true}.AppFilesInDir(appDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filePaths(files)).NotTo(ContainElement("secrets.txt"))
+				Expect(filePaths(files)).To(ContainElement("app.rb"))
+			})
+
+			It("lists the ignored files", func() {
+				ignoredFiles, err := appFiles.IgnoredFiles(appDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(ignoredFiles).To(Equal([]string{
+					".cfignore",
+					".gitignore",
+					"debug.log",
+					"lib/.cfignore",
+					"lib/generated/",
+					"lib/local.rb",
+				}))
+			})
+		})
+
 		// NB: on windows, you can never rely on the size of a directory being zero
 		// see: http://msdn.microsoft.com/en-us/library/windows/desktop/aa364946(v=vs.85).aspx
 		// and: https://www.pivotaltracker.com/story/show/70470232
@@ -73,6 +151,116 @@ var _ = Describe("AppFiles", func() {
 		})
 	})
 
+	Describe("AppFilesInDir with a fingerprint cache", func() {
+		var (
+			appDir        string
+			cacheDir      string
+			cachedFiles   ApplicationFiles
+			anHourAgo     time.Time
+			fileSha       string
+			cacheContents func() map[string]map[string]interface{}
+		)
+
+		BeforeEach(func() {
+			var err error
+			appDir, err = ioutil.TempDir("", "fingerprinted-app")
+			Expect(err).NotTo(HaveOccurred())
+
+			cacheDir, err = ioutil.TempDir("", "fingerprints")
+			Expect(err).NotTo(HaveOccurred())
+
+			cachedFiles = ApplicationFiles{FingerprintCacheDir: cacheDir}
+
+			err = ioutil.WriteFile(filepath.Join(appDir, "app.rb"), []byte("puts 'hello'"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			anHourAgo = time.Now().Add(-time.Hour)
+			Expect(os.Chtimes(filepath.Join(appDir, "app.rb"), anHourAgo, anHourAgo)).To(Succeed())
+			fileSha = fmt.Sprintf("%x", sha1.Sum([]byte("puts 'hello'")))
+
+			cacheContents = func() map[string]map[string]interface{} {
+				cacheFiles, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cacheFiles).To(HaveLen(1))
+
+				contents, err := ioutil.ReadFile(cacheFiles[0])
+				Expect(err).NotTo(HaveOccurred())
+
+				// mtimes are in nanoseconds, which do not survive a float64
+				decoder := json.NewDecoder(bytes.NewReader(contents))
+				decoder.UseNumber()
+
+				entries := map[string]map[string]interface{}{}
+				Expect(decoder.Decode(&entries)).To(Succeed())
+				return entries
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(appDir)
+			os.RemoveAll(cacheDir)
+		})
+
+		It("saves the fingerprints of the files", func() {
+			files, err := cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]models.AppFileFields{{Path: "app.rb", Sha1: fileSha, Size: 12}}))
+
+			Expect(cacheContents()["app.rb"]["sha1"]).To(Equal(fileSha))
+		})
+
+		It("does not hash files that have not changed since the last walk", func() {
+			_, err := cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries := cacheContents()
+			entries["app.rb"]["sha1"] = "cached-sha"
+			contents, err := json.Marshal(entries)
+			Expect(err).NotTo(HaveOccurred())
+			cacheFiles, _ := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+			Expect(ioutil.WriteFile(cacheFiles[0], contents, 0600)).To(Succeed())
+
+			files, err := cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files[0].Sha1).To(Equal("cached-sha"))
+
+			anHourLater := anHourAgo.Add(time.Minute)
+			Expect(os.Chtimes(filepath.Join(appDir, "app.rb"), anHourLater, anHourLater)).To(Succeed())
+
+			files, err = cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files[0].Sha1).To(Equal(fileSha))
+		})
+
+		It("does not save the fingerprints of files that were just modified", func() {
+			Expect(os.Chtimes(filepath.Join(appDir, "app.rb"), time.Now(), time.Now())).To(Succeed())
+
+			files, err := cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files[0].Sha1).To(Equal(fileSha))
+
+			cacheFiles, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cacheFiles).To(BeEmpty())
+		})
+
+		It("hashes every file when there are many of them", func() {
+			for i := 0; i < 50; i++ {
+				content := fmt.Sprintf("file %d", i)
+				err := ioutil.WriteFile(filepath.Join(appDir, fmt.Sprintf("file-%02d", i)), []byte(content), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			files, err := cachedFiles.AppFilesInDir(appDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(51))
+
+			for i, file := range files[1:] {
+				Expect(file.Path).To(Equal(fmt.Sprintf("file-%02d", i)))
+				Expect(file.Sha1).To(Equal(fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("file %d", i))))))
+			}
+		})
+	})
+
 	Describe("CopyFiles", func() {
 		It("copies only the files specified", func() {
 			copyDir := filepath.Join(fixturePath, "app-copy-test")
@@ -1,77 +1,230 @@
 package app_files
 
 import (
-	"path"
+	"bytes"
+	"regexp"
 	"strings"
-
-	"github.com/cloudfoundry/cli/glob"
 )
 
 type CfIgnore interface {
 	FileShouldBeIgnored(path string) bool
 }
 
+// NewCfIgnore parses the text of a top-level .cfignore file. Patterns follow
+// the gitignore format, and are applied after the patterns ignored by default.
 func NewCfIgnore(text string) CfIgnore {
-	patterns := []ignorePattern{}
-	lines := strings.Split(text, "\n")
-	lines = append(defaultIgnoreLines, lines...)
+	ignore := &cfIgnore{}
+	ignore.addPatterns("", strings.Join(defaultIgnoreLines, "\n"))
+	ignore.addPatterns("", text)
+	return ignore
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+// FileShouldBeIgnored reports whether path, relative to the app directory and
+// separated by '/', is ignored. Directories are passed with a trailing '/'.
+// As with git, files in an ignored directory cannot be included again.
+func (ignore *cfIgnore) FileShouldBeIgnored(path string) bool {
+	isDir := strings.HasSuffix(path, "/")
+	path = strings.Trim(path, "/")
+
+	components := strings.Split(path, "/")
+	for i := 1; i < len(components); i++ {
+		if ignore.matches(strings.Join(components[:i], "/"), true) {
+			return true
 		}
+	}
 
-		ignore := true
-		if strings.HasPrefix(line, "!") {
-			line = line[1:]
-			ignore = false
-		}
+	return ignore.matches(path, isDir)
+}
 
-		for _, p := range globsForPattern(path.Clean(line)) {
-			patterns = append(patterns, ignorePattern{ignore, p})
+// addPatterns adds the patterns of an ignore file found in dir, which is
+// relative to the app directory. Patterns added later take precedence.
+func (ignore *cfIgnore) addPatterns(dir string, text string) {
+	patterns := []ignorePattern{}
+	for _, line := range strings.Split(text, "\n") {
+		if pattern, ok := compileIgnorePattern(line); ok {
+			patterns = append(patterns, pattern)
 		}
 	}
 
-	return cfIgnore(patterns)
+	if len(patterns) > 0 {
+		ignore.files = append(ignore.files, ignoreFile{dir: dir, patterns: patterns})
+	}
 }
 
-func (ignore cfIgnore) FileShouldBeIgnored(path string) bool {
+func (ignore *cfIgnore) matches(path string, isDir bool) bool {
 	result := false
 
-	for _, pattern := range ignore {
-		if strings.HasPrefix(pattern.glob.String(), "/") && !strings.HasPrefix(path, "/") {
-			path = "/" + path
+	for _, file := range ignore.files {
+		relativePath := path
+		if file.dir != "" {
+			if !strings.HasPrefix(path, file.dir+"/") {
+				continue
+			}
+			relativePath = strings.TrimPrefix(path, file.dir+"/")
 		}
 
-		if pattern.glob.Match(path) {
-			result = pattern.exclude
+		for _, pattern := range file.patterns {
+			if pattern.dirOnly && !isDir {
+				continue
+			}
+
+			if pattern.regexp.MatchString(relativePath) {
+				result = pattern.exclude
+			}
 		}
 	}
 
 	return result
 }
 
-func globsForPattern(pattern string) (globs []glob.Glob) {
-	globs = append(globs, glob.MustCompileGlob(pattern))
-	globs = append(globs, glob.MustCompileGlob(path.Join(pattern, "*")))
-	globs = append(globs, glob.MustCompileGlob(path.Join(pattern, "**", "*")))
+func compileIgnorePattern(line string) (pattern ignorePattern, ok bool) {
+	line = strings.TrimRight(line, "\r")
+	for (strings.HasSuffix(line, " ") || strings.HasSuffix(line, "\t")) && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	line = strings.TrimLeft(line, " \t")
 
-	if !strings.HasPrefix(pattern, "/") {
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern)))
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern, "*")))
-		globs = append(globs, glob.MustCompileGlob(path.Join("**", pattern, "**", "*")))
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
 	}
 
-	return
+	pattern.exclude = true
+	if strings.HasPrefix(line, "!") {
+		line = line[1:]
+		pattern.exclude = false
+	}
+
+	if strings.HasSuffix(line, "/") {
+		line = strings.TrimRight(line, "/")
+		pattern.dirOnly = true
+	}
+
+	// Patterns containing a slash are relative to the directory of the ignore
+	// file; others match a name at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return
+	}
+
+	expr := translateIgnorePattern(line)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return
+	}
+
+	pattern.regexp = compiled
+	return pattern, true
+}
+
+func translateIgnorePattern(pattern string) string {
+	var expr bytes.Buffer
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case strings.HasPrefix(pattern[i:], "**") && (i == 0 || pattern[i-1] == '/') && (i+2 == len(pattern) || pattern[i+2] == '/'):
+			if i+2 == len(pattern) {
+				expr.WriteString(".*")
+				i++
+			} else {
+				expr.WriteString("(?:.*/)?")
+				i += 2
+			}
+		case c == '*':
+			expr.WriteString("[^/]*")
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			if class, length, ok := translateCharClass(pattern[i:]); ok {
+				expr.WriteString(class)
+				i += length - 1
+			} else {
+				expr.WriteString(`\[`)
+			}
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	return expr.String()
+}
+
+// translateCharClass translates the bracket expression at the start of
+// pattern, returning the regexp and the length of the expression.
+func translateCharClass(pattern string) (string, int, bool) {
+	i := 1
+	negate := false
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		negate = true
+		i++
+	}
+
+	var class bytes.Buffer
+	first := true
+	for i < len(pattern) {
+		c := pattern[i]
+
+		if c == ']' && !first {
+			if negate {
+				return "[^/" + class.String() + "]", i + 1, true
+			}
+			return "[" + class.String() + "]", i + 1, true
+		}
+		first = false
+
+		if strings.HasPrefix(pattern[i:], "[:") {
+			if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+				class.WriteString(pattern[i : i+2+end+2])
+				i += 2 + end + 2
+				continue
+			}
+		}
+
+		escaped := false
+		if c == '\\' && i+1 < len(pattern) {
+			i++
+			c = pattern[i]
+			escaped = true
+		}
+
+		if (escaped && c == '-') || strings.IndexByte(`\[]^`, c) >= 0 {
+			class.WriteByte('\\')
+		}
+		class.WriteByte(c)
+		i++
+	}
+
+	return "", 0, false
 }
 
 type ignorePattern struct {
 	exclude bool
-	glob    glob.Glob
+	dirOnly bool
+	regexp  *regexp.Regexp
 }
 
-type cfIgnore []ignorePattern
+type ignoreFile struct {
+	dir      string
+	patterns []ignorePattern
+}
+
+type cfIgnore struct {
+	files []ignoreFile
+}
 
 var defaultIgnoreLines = []string{
 	".cfignore",
@@ -59,6 +59,81 @@ stuff/exclude.c`)
 		Expect(ignore.FileShouldBeIgnored(".git/objects")).To(BeFalse())
 	})
 
+	It("only excludes directories with patterns ending in a slash", func() {
+		ignore := NewCfIgnore(`logs/`)
+		Expect(ignore.FileShouldBeIgnored("logs/")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("logs/today.log")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("app/logs/")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("logs")).To(BeFalse())
+	})
+
+	It("excludes files based on character classes", func() {
+		ignore := NewCfIgnore(`
+*.[oa]
+file[!0-9].txt
+`)
+		Expect(ignore.FileShouldBeIgnored("lib/thing.o")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("thing.a")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("thing.c")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("filex.txt")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("file1.txt")).To(BeFalse())
+	})
+
+	It("matches escaped characters literally", func() {
+		ignore := NewCfIgnore(`
+\#notacomment
+\!important
+what\?
+trailing\ `)
+		Expect(ignore.FileShouldBeIgnored("#notacomment")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("!important")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("what?")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("whatx")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("trailing ")).To(BeTrue())
+	})
+
+	It("skips comments and blank lines", func() {
+		ignore := NewCfIgnore(`
+# a comment
+
+*.log   `)
+		Expect(ignore.FileShouldBeIgnored("# a comment")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("debug.log")).To(BeTrue())
+	})
+
+	It("anchors patterns that contain a slash to the top level", func() {
+		ignore := NewCfIgnore(`
+/tmp
+build/output
+`)
+		Expect(ignore.FileShouldBeIgnored("tmp")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("app/tmp")).To(BeFalse())
+		Expect(ignore.FileShouldBeIgnored("build/output")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("app/build/output")).To(BeFalse())
+	})
+
+	It("matches any number of directories with double-stars", func() {
+		ignore := NewCfIgnore(`
+**/cache
+a/**/b
+vendor/**
+`)
+		Expect(ignore.FileShouldBeIgnored("cache")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("x/y/cache")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("a/b")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("a/x/y/b")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("vendor/lib/file.go")).To(BeTrue())
+		Expect(ignore.FileShouldBeIgnored("vendor/")).To(BeFalse())
+	})
+
+	It("does not include files again inside an excluded directory", func() {
+		ignore := NewCfIgnore(`
+build/
+!build/keep.txt
+`)
+		Expect(ignore.FileShouldBeIgnored("build/keep.txt")).To(BeTrue())
+	})
+
 	Describe("files named manifest.yml", func() {
 		var (
 			ignore CfIgnore
@@ -45,6 +45,15 @@ type FakeAppFiles struct {
 	walkAppFilesReturns struct {
 		result1 error
 	}
+	IgnoredFilesStub        func(dir string) (ignoredFiles []string, err error)
+	ignoredFilesMutex       sync.RWMutex
+	ignoredFilesArgsForCall []struct {
+		dir string
+	}
+	ignoredFilesReturns struct {
+		result1 []string
+		result2 error
+	}
 }
 
 func (fake *FakeAppFiles) AppFilesInDir(dir string) (appFiles []models.AppFileFields, err error) {
@@ -175,4 +184,36 @@ func (fake *FakeAppFiles) WalkAppFilesReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAppFiles) IgnoredFiles(dir string) (ignoredFiles []string, err error) {
+	fake.ignoredFilesMutex.Lock()
+	defer fake.ignoredFilesMutex.Unlock()
+	fake.ignoredFilesArgsForCall = append(fake.ignoredFilesArgsForCall, struct {
+		dir string
+	}{dir})
+	if fake.IgnoredFilesStub != nil {
+		return fake.IgnoredFilesStub(dir)
+	} else {
+		return fake.ignoredFilesReturns.result1, fake.ignoredFilesReturns.result2
+	}
+}
+
+func (fake *FakeAppFiles) IgnoredFilesCallCount() int {
+	fake.ignoredFilesMutex.RLock()
+	defer fake.ignoredFilesMutex.RUnlock()
+	return len(fake.ignoredFilesArgsForCall)
+}
+
+func (fake *FakeAppFiles) IgnoredFilesArgsForCall(i int) string {
+	fake.ignoredFilesMutex.RLock()
+	defer fake.ignoredFilesMutex.RUnlock()
+	return fake.ignoredFilesArgsForCall[i].dir
+}
+
+func (fake *FakeAppFiles) IgnoredFilesReturns(result1 []string, result2 error) {
+	fake.ignoredFilesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 var _ AppFiles = new(FakeAppFiles)
@@ -0,0 +1,54 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	. "github.com/cloudfoundry/cli/cf/app_files"
+
+	"sync"
+)
+
+type FakeGitExporter struct {
+	ExportStub        func(source string, destDir string) (err error)
+	exportMutex       sync.RWMutex
+	exportArgsForCall []struct {
+		source  string
+		destDir string
+	}
+	exportReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeGitExporter) Export(source string, destDir string) (err error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.exportArgsForCall = append(fake.exportArgsForCall, struct {
+		source  string
+		destDir string
+	}{source, destDir})
+	if fake.ExportStub != nil {
+		return fake.ExportStub(source, destDir)
+	} else {
+		return fake.exportReturns.result1
+	}
+}
+
+func (fake *FakeGitExporter) ExportCallCount() int {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return len(fake.exportArgsForCall)
+}
+
+func (fake *FakeGitExporter) ExportArgsForCall(i int) (string, string) {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return fake.exportArgsForCall[i].source, fake.exportArgsForCall[i].destDir
+}
+
+func (fake *FakeGitExporter) ExportReturns(result1 error) {
+	fake.exportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ GitExporter = new(FakeGitExporter)
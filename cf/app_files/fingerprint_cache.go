@@ -0,0 +1,152 @@
+package app_files
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/gofileutils/fileutils"
+)
+
+// Files modified this recently may change again within the resolution of
+// their modification time, so their fingerprints are not cached.
+const racyFingerprintWindow = 2 * time.Second
+
+type fingerprint struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	Sha1    string `json:"sha1"`
+}
+
+// fingerprintCache remembers the SHA1 of the files in an app directory, keyed
+// by path, size and modification time, between pushes.
+type fingerprintCache struct {
+	path    string
+	entries map[string]fingerprint
+	seen    map[string]fingerprint
+	mutex   sync.Mutex
+}
+
+func loadFingerprintCache(cacheDir string, appDir string) *fingerprintCache {
+	cache := &fingerprintCache{
+		entries: map[string]fingerprint{},
+		seen:    map[string]fingerprint{},
+	}
+
+	if cacheDir == "" {
+		return cache
+	}
+
+	cache.path = filepath.Join(cacheDir, fmt.Sprintf("%x.json", sha1.Sum([]byte(appDir))))
+
+	contents, err := ioutil.ReadFile(cache.path)
+	if err == nil {
+		json.Unmarshal(contents, &cache.entries)
+	}
+
+	return cache
+}
+
+func (cache *fingerprintCache) lookup(fileName string, fileInfo os.FileInfo) (string, bool) {
+	entry, found := cache.entries[fileName]
+	if !found || entry.Size != fileInfo.Size() || entry.ModTime != fileInfo.ModTime().UnixNano() {
+		return "", false
+	}
+
+	cache.store(fileName, fileInfo, entry.Sha1)
+	return entry.Sha1, true
+}
+
+func (cache *fingerprintCache) store(fileName string, fileInfo os.FileInfo, sha string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.seen[fileName] = fingerprint{
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime().UnixNano(),
+		Sha1:    sha,
+	}
+}
+
+// save writes the fingerprints of the files seen during this walk, which drops
+// files that no longer exist. Failing to save only makes the next push slower.
+func (cache *fingerprintCache) save() {
+	if cache.path == "" {
+		return
+	}
+
+	cutoff := time.Now().Add(-racyFingerprintWindow).UnixNano()
+	entries := map[string]fingerprint{}
+	for fileName, entry := range cache.seen {
+		if entry.ModTime < cutoff {
+			entries[fileName] = entry
+		}
+	}
+
+	// Unzipped apps are extracted to a new directory with fresh modification
+	// times on every push, so nothing is kept for them.
+	if len(entries) == 0 {
+		os.Remove(cache.path)
+		return
+	}
+
+	contents, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+
+	if os.MkdirAll(filepath.Dir(cache.path), 0700) != nil {
+		return
+	}
+
+	ioutil.WriteFile(cache.path, contents, 0600)
+}
+
+type fileToHash struct {
+	index    int
+	fileName string
+	fullPath string
+	fileInfo os.FileInfo
+}
+
+// hashFiles computes the SHA1 of files on all CPUs, passing each result to
+// setSha1 with the index of the file.
+func hashFiles(files []fileToHash, setSha1 func(index int, sha string), cache *fingerprintCache) error {
+	work := make(chan fileToHash)
+	errs := make(chan error, len(files))
+
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range work {
+				hash := sha1.New()
+				err := fileutils.CopyPathToWriter(file.fullPath, hash)
+				if err != nil {
+					errs <- err
+					continue
+				}
+
+				sha := fmt.Sprintf("%x", hash.Sum(nil))
+				setSha1(file.index, sha)
+				cache.store(file.fileName, file.fileInfo, sha)
+			}
+		}()
+	}
+
+	for _, file := range files {
+		work <- file
+	}
+	close(work)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
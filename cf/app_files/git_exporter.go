@@ -0,0 +1,124 @@
+package app_files
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/gofileutils/fileutils"
+)
+
+const gitSourcePrefix = "git+"
+
+// IsGitSource reports whether an app path names a ref of a git repository,
+// in the form git+URL#REF, instead of a directory or a zip file.
+func IsGitSource(path string) bool {
+	return strings.HasPrefix(path, gitSourcePrefix)
+}
+
+// GitSource returns the app path of ref in the repository at url, which can
+// also be the path of a local repository.
+func GitSource(url string, ref string) string {
+	return gitSourcePrefix + url + "#" + ref
+}
+
+// ParseGitSource splits an app path into the url of the repository and the
+// ref. The ref is HEAD when the path does not name one.
+func ParseGitSource(path string) (url string, ref string) {
+	url = strings.TrimPrefix(path, gitSourcePrefix)
+	ref = "HEAD"
+
+	if index := strings.LastIndex(url, "#"); index >= 0 {
+		if url[index+1:] != "" {
+			ref = url[index+1:]
+		}
+		url = url[:index]
+	}
+	return
+}
+
+//go:generate counterfeiter -o fakes/fake_git_exporter.go . GitExporter
+type GitExporter interface {
+	Export(source string, destDir string) (err error)
+}
+
+// ApplicationGitExporter exports the committed tree of a ref with git
+// archive, so that changes in a working tree are never pushed.
+type ApplicationGitExporter struct {
+	Zipper Zipper
+}
+
+func (exporter ApplicationGitExporter) Export(source string, destDir string) (err error) {
+	url, ref := ParseGitSource(source)
+	if strings.HasPrefix(ref, "-") {
+		return errors.New(T("Invalid git ref: {{.Ref}}", map[string]interface{}{"Ref": ref}))
+	}
+
+	fileutils.TempDir("git-export", func(tmpDir string, tmpErr error) {
+		if tmpErr != nil {
+			err = tmpErr
+			return
+		}
+
+		repoDir := url
+		treeish := ref
+
+		if isLocalDirectory(url) {
+			// Only export the subdirectory of the repository the app is in
+			var prefix string
+			prefix, err = runGit("-C", url, "rev-parse", "--show-prefix")
+			if err != nil {
+				return
+			}
+
+			prefix = strings.TrimSpace(prefix)
+			if prefix != "" {
+				repoDir, err = runGit("-C", url, "rev-parse", "--show-toplevel")
+				if err != nil {
+					return
+				}
+
+				repoDir = strings.TrimSpace(repoDir)
+				treeish = ref + ":" + prefix
+			}
+		} else {
+			repoDir = filepath.Join(tmpDir, "repo.git")
+			_, err = runGit("clone", "--quiet", "--bare", "--", url, repoDir)
+			if err != nil {
+				return
+			}
+		}
+
+		zipPath := filepath.Join(tmpDir, "app.zip")
+		_, err = runGit("-C", repoDir, "archive", "--format=zip", "--output="+zipPath, treeish)
+		if err != nil {
+			return
+		}
+
+		err = exporter.Zipper.Unzip(zipPath, destDir)
+	})
+	return
+}
+
+func isLocalDirectory(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && fileInfo.IsDir()
+}
+
+func runGit(args ...string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.NewWithError(T("git must be installed to push from a git repository"), err)
+	}
+
+	output, err := exec.Command(gitPath, args...).CombinedOutput()
+	if err != nil {
+		return "", errors.NewWithError(T("Error running git:\n{{.Output}}",
+			map[string]interface{}{"Output": strings.TrimSpace(string(output))}), err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,136 @@
+package app_files_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/app_files"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitExporter", func() {
+	Describe("ParseGitSource", func() {
+		It("splits the repository url and the ref", func() {
+			url, ref := ParseGitSource("git+https://example.com/app.git#v1.2.3")
+			Expect(url).To(Equal("https://example.com/app.git"))
+			Expect(ref).To(Equal("v1.2.3"))
+		})
+
+		It("uses HEAD when no ref is given", func() {
+			url, ref := ParseGitSource("git+https://example.com/app.git")
+			Expect(url).To(Equal("https://example.com/app.git"))
+			Expect(ref).To(Equal("HEAD"))
+		})
+
+		It("round trips the app paths made by GitSource", func() {
+			Expect(IsGitSource(GitSource("/path/to/repo", "abc123"))).To(BeTrue())
+			Expect(IsGitSource("/path/to/repo")).To(BeFalse())
+
+			url, ref := ParseGitSource(GitSource("/path/to/repo", "abc123"))
+			Expect(url).To(Equal("/path/to/repo"))
+			Expect(ref).To(Equal("abc123"))
+		})
+	})
+
+	Describe("Export", func() {
+		var (
+			repoDir  string
+			destDir  string
+			exporter ApplicationGitExporter
+			git      func(args ...string)
+		)
+
+		BeforeEach(func() {
+			if _, err := exec.LookPath("git"); err != nil {
+				Skip("git is not installed")
+			}
+
+			var err error
+			repoDir, err = ioutil.TempDir("", "git-repo")
+			Expect(err).NotTo(HaveOccurred())
+
+			destDir, err = ioutil.TempDir("", "git-export")
+			Expect(err).NotTo(HaveOccurred())
+
+			exporter = ApplicationGitExporter{Zipper: ApplicationZipper{}}
+
+			git = func(args ...string) {
+				args = append([]string{"-C", repoDir, "-c", "user.name=cf", "-c", "user.email=cf@example.com"}, args...)
+				output, err := exec.Command("git", args...).CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+			}
+
+			git("init", "--quiet")
+			Expect(os.MkdirAll(filepath.Join(repoDir, "web"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(repoDir, "web", "app.rb"), []byte("v1"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(repoDir, "README"), []byte("readme"), 0644)).To(Succeed())
+			git("add", ".")
+			git("commit", "--quiet", "-m", "first")
+			git("tag", "v1")
+
+			Expect(ioutil.WriteFile(filepath.Join(repoDir, "web", "app.rb"), []byte("v2"), 0644)).To(Succeed())
+			git("commit", "--quiet", "-am", "second")
+
+			Expect(ioutil.WriteFile(filepath.Join(repoDir, "web", "app.rb"), []byte("dirty"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(repoDir, "untracked"), []byte("untracked"), 0644)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(repoDir)
+			os.RemoveAll(destDir)
+		})
+
+		readFile := func(path string) string {
+			contents, err := ioutil.ReadFile(filepath.Join(destDir, filepath.FromSlash(path)))
+			Expect(err).NotTo(HaveOccurred())
+			return string(contents)
+		}
+
+		It("exports the committed tree of the ref in a local repository", func() {
+			err := exporter.Export(GitSource(repoDir, "v1"), destDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("web/app.rb")).To(Equal("v1"))
+			Expect(readFile("README")).To(Equal("readme"))
+			Expect(filepath.Join(destDir, "untracked")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(destDir, ".git")).NotTo(BeAnExistingFile())
+		})
+
+		It("exports HEAD without the changes in the working tree", func() {
+			err := exporter.Export("git+"+repoDir, destDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("web/app.rb")).To(Equal("v2"))
+		})
+
+		It("only exports the subdirectory of the repository the app is in", func() {
+			err := exporter.Export(GitSource(filepath.Join(repoDir, "web"), "v1"), destDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("app.rb")).To(Equal("v1"))
+			Expect(filepath.Join(destDir, "README")).NotTo(BeAnExistingFile())
+		})
+
+		It("clones repositories that are not local directories", func() {
+			err := exporter.Export(GitSource("file://"+filepath.ToSlash(repoDir), "v1"), destDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("web/app.rb")).To(Equal("v1"))
+		})
+
+		It("returns an error for an unknown ref", func() {
+			err := exporter.Export(GitSource(repoDir, "no-such-ref"), destDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Error running git"))
+		})
+
+		It("does not pass refs that look like options to git", func() {
+			err := exporter.Export(GitSource(repoDir, "--output=/tmp/x"), destDir)
+			Expect(err).To(MatchError(ContainSubstring("Invalid git ref")))
+		})
+	})
+})
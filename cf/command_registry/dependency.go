@@ -3,6 +3,7 @@ package command_registry
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/cloudfoundry/cli/cf/actors"
@@ -45,6 +46,7 @@ type Dependency struct {
 	WordGenerator      generator.WordGenerator
 	AppZipper          app_files.Zipper
 	AppFiles           app_files.AppFiles
+	GitExporter        app_files.GitExporter
 	PushActor          actors.PushActor
 	ChecksumUtil       utils.Sha1Checksum
 	WilecardDependency interface{} //use for injecting fakes
@@ -133,9 +135,14 @@ func NewDependency() Dependency {
 	deps.WordGenerator = generator.NewWordGenerator()
 
 	deps.AppZipper = app_files.ApplicationZipper{}
-	deps.AppFiles = app_files.ApplicationFiles{}
+	deps.AppFiles = app_files.ApplicationFiles{
+		FingerprintCacheDir: filepath.Join(filepath.Dir(config_helpers.DefaultFilePath()), "fingerprints"),
+		UseGitignore:        os.Getenv("CF_USE_GITIGNORE") == "true",
+	}
+
+	deps.GitExporter = app_files.ApplicationGitExporter{Zipper: deps.AppZipper}
 
-	deps.PushActor = actors.NewPushActor(deps.RepoLocator.GetApplicationBitsRepository(), deps.AppZipper, deps.AppFiles)
+	deps.PushActor = actors.NewPushActor(deps.RepoLocator.GetApplicationBitsRepository(), deps.AppZipper, deps.AppFiles, deps.GitExporter)
 
 	deps.ChecksumUtil = utils.NewSha1Checksum("")
 
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api/app_instances"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+// appRunWaiter polls apps until they are staged and their instances are
+// running. start uses it while it streams the staging logs; the bulk
+// operations, restage, scale, rebind-service and wait use it without logs, so
+// that they all agree on timeouts and on when an app has failed.
+type appRunWaiter struct {
+	ui               terminal.UI
+	appRepo          applications.ApplicationRepository
+	appInstancesRepo app_instances.AppInstancesRepository
+	stagingTimeout   time.Duration
+	startupTimeout   time.Duration
+	pingerThrottle   time.Duration
+}
+
+func newAppRunWaiter(deps command_registry.Dependency) appRunWaiter {
+	return appRunWaiter{
+		ui:               deps.Ui,
+		appRepo:          deps.RepoLocator.GetApplicationRepository(),
+		appInstancesRepo: deps.RepoLocator.GetAppInstancesRepository(),
+		stagingTimeout:   stagingTimeoutFromEnv(deps.Ui),
+		startupTimeout:   startupTimeoutFromEnv(deps.Ui),
+		pingerThrottle:   DefaultPingerThrottle,
+	}
+}
+
+func stagingTimeoutFromEnv(ui terminal.UI) time.Duration {
+	if os.Getenv("CF_STAGING_TIMEOUT") == "" {
+		return DefaultStagingTimeout
+	}
+
+	duration, err := strconv.ParseInt(os.Getenv("CF_STAGING_TIMEOUT"), 10, 64)
+	if err != nil {
+		ui.Failed(T("invalid value for env var CF_STAGING_TIMEOUT\n{{.Err}}",
+			map[string]interface{}{"Err": err}))
+	}
+	return time.Duration(duration) * time.Minute
+}
+
+func startupTimeoutFromEnv(ui terminal.UI) time.Duration {
+	if os.Getenv("CF_STARTUP_TIMEOUT") == "" {
+		return DefaultStartupTimeout
+	}
+
+	duration, err := strconv.ParseInt(os.Getenv("CF_STARTUP_TIMEOUT"), 10, 64)
+	if err != nil {
+		ui.Failed(T("invalid value for env var CF_STARTUP_TIMEOUT\n{{.Err}}",
+			map[string]interface{}{"Err": err}))
+	}
+	return time.Duration(duration) * time.Minute
+}
+
+// start starts a stopped app and waits for it to run.
+func (waiter appRunWaiter) start(app models.Application) error {
+	state := "STARTED"
+	updatedApp, err := waiter.appRepo.Update(app.Guid, models.AppParams{State: &state})
+	if err != nil {
+		return err
+	}
+
+	app.PackageState = updatedApp.PackageState
+	return waiter.waitUntilRunning(app)
+}
+
+// restart stops an app if it is started, then starts it and waits for it to
+// run.
+func (waiter appRunWaiter) restart(app models.Application) error {
+	if app.State != "stopped" {
+		state := "STOPPED"
+		_, err := waiter.appRepo.Update(app.Guid, models.AppParams{State: &state})
+		if err != nil {
+			return err
+		}
+	}
+
+	return waiter.start(app)
+}
+
+// restage restages an app and waits for it to run.
+func (waiter appRunWaiter) restage(app models.Application) error {
+	err := waiter.appRepo.CreateRestageRequest(app.Guid)
+	if err != nil {
+		return err
+	}
+
+	app.PackageState = ""
+	return waiter.waitUntilRunning(app)
+}
+
+// waitUntilRunning waits for the package of an app to be staged and for one
+// of its instances to be running.
+func (waiter appRunWaiter) waitUntilRunning(app models.Application) error {
+	app, err := waiter.waitForStaging(app, waiter.stagingTimeout)
+	if err != nil {
+		return err
+	}
+
+	return waiter.waitForRunningInstances(app.Guid, 1, waiter.startupTimeout, nil)
+}
+
+// waitForStaging polls an app until its package is staged or has failed to
+// stage, starting from the package state of app. A timeout of 0 checks the
+// app once.
+func (waiter appRunWaiter) waitForStaging(app models.Application, timeout time.Duration) (models.Application, error) {
+	startTime := time.Now()
+	appGuid := app.Guid
+
+	var err error
+	if timeout == 0 {
+		app, err = waiter.appRepo.GetApp(appGuid)
+	} else {
+		for app.PackageState != "STAGED" && app.PackageState != "FAILED" && time.Since(startTime) < timeout {
+			app, err = waiter.appRepo.GetApp(appGuid)
+			if err != nil || app.PackageState == "STAGED" || app.PackageState == "FAILED" {
+				break
+			}
+			waiter.ui.Wait(waiter.pingerThrottle)
+		}
+	}
+
+	if err != nil {
+		return app, err
+	}
+
+	app.Guid = appGuid
+	switch app.PackageState {
+	case "STAGED":
+		return app, nil
+	case "FAILED":
+		return app, &stagingFailedError{reason: app.StagingFailedReason}
+	}
+
+	return app, &waitTimeoutError{T("Staging did not finish within {{.Timeout}}", map[string]interface{}{"Timeout": timeout})}
+}
+
+// waitForRunningInstances polls the instances of an app until count of them
+// are running. It fails as soon as an instance crashes or keeps failing
+// before that. report, when set, is called with the instances of every poll.
+func (waiter appRunWaiter) waitForRunningInstances(appGuid string, count int, timeout time.Duration, report func(instanceCount)) error {
+	startTime := time.Now()
+	for {
+		instances, err := waiter.fetchInstanceCount(appGuid)
+		if err == nil {
+			if report != nil {
+				report(instances)
+			}
+
+			if instances.running >= count {
+				return nil
+			}
+
+			if instances.flapping > 0 || instances.crashed > 0 {
+				return errors.New(T("Start unsuccessful"))
+			}
+		}
+
+		if time.Since(startTime) >= timeout {
+			return &waitTimeoutError{T("Start app timeout")}
+		}
+
+		waiter.ui.Wait(waiter.pingerThrottle)
+	}
+}
+
+type instanceCount struct {
+	running         int
+	starting        int
+	startingDetails map[string]struct{}
+	flapping        int
+	down            int
+	crashed         int
+	total           int
+}
+
+func (waiter appRunWaiter) fetchInstanceCount(appGuid string) (instanceCount, error) {
+	count := instanceCount{
+		startingDetails: make(map[string]struct{}),
+	}
+
+	instances, apiErr := waiter.appInstancesRepo.GetInstances(appGuid)
+	if apiErr != nil {
+		return instanceCount{}, apiErr
+	}
+
+	count.total = len(instances)
+
+	for _, inst := range instances {
+		switch inst.State {
+		case models.InstanceRunning:
+			count.running++
+		case models.InstanceStarting:
+			count.starting++
+			if inst.Details != "" {
+				count.startingDetails[inst.Details] = struct{}{}
+			}
+		case models.InstanceFlapping:
+			count.flapping++
+		case models.InstanceDown:
+			count.down++
+		case models.InstanceCrashed:
+			count.crashed++
+		}
+	}
+
+	return count, nil
+}
+
+// stagingFailedError is returned when the package of an app fails to stage,
+// so that start can show tips based on the reason.
+type stagingFailedError struct {
+	reason string
+}
+
+func (err *stagingFailedError) Error() string {
+	if err.reason == "" {
+		return T("Staging failed")
+	}
+	return T("Staging failed: {{.Reason}}", map[string]interface{}{"Reason": err.reason})
+}
+
+// waitTimeoutError is returned when an app does not reach a state in time, so
+// that callers can tell timeouts from failures.
+type waitTimeoutError struct {
+	message string
+}
+
+func (err *waitTimeoutError) Error() string {
+	return err.message
+}
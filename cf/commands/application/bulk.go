@@ -0,0 +1,175 @@
+package application
+
+import (
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+// maxBulkConcurrency is the number of apps a bulk operation changes at once.
+const maxBulkConcurrency = 5
+
+// bulkAppOperation changes one of the apps selected with --match or --all and
+// returns the resulting status of the app. Operations run concurrently, so
+// they report through their return values instead of the UI.
+type bulkAppOperation func(app models.Application) (status string, err error)
+
+func addBulkFlags(fs map[string]flags.FlagSet) {
+	fs["match"] = &cliFlags.StringFlag{Name: "match", Usage: T("Apply to all apps whose names match PATTERN, e.g. 'payments-*'")}
+	fs["all"] = &cliFlags.BoolFlag{Name: "all", Usage: T("Apply to all apps in the targeted space")}
+	if _, ok := fs["f"]; !ok {
+		fs["f"] = &cliFlags.BoolFlag{Name: "f", Usage: T("Force the operation on the selected apps without confirmation")}
+	}
+}
+
+func bulkSelected(fc flags.FlagContext) bool {
+	return fc.String("match") != "" || fc.Bool("all")
+}
+
+// bulkRequirements returns the requirements of a command run with --match or
+// --all, which takes no app name.
+func bulkRequirements(ui terminal.UI, commandName string, requirementsFactory requirements.Factory, fc flags.FlagContext) []requirements.Requirement {
+	if fc.String("match") != "" && fc.Bool("all") {
+		ui.Failed(T("Incorrect Usage. --match and --all cannot be combined\n\n") + command_registry.Commands.CommandUsage(commandName))
+	}
+
+	if len(fc.Args()) != 0 {
+		ui.Failed(T("Incorrect Usage. An app name cannot be combined with --match or --all\n\n") + command_registry.Commands.CommandUsage(commandName))
+	}
+
+	return []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+}
+
+type bulkAppRunner struct {
+	ui             terminal.UI
+	config         core_config.Reader
+	appSummaryRepo api.AppSummaryRepository
+}
+
+// run applies operation to the selected apps after showing them and asking
+// for one confirmation, then prints the result for each app. action names
+// the operation in the confirmation prompt, e.g. "stop".
+func (runner bulkAppRunner) run(c flags.FlagContext, action string, operation bulkAppOperation) {
+	pattern := c.String("match")
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		runner.ui.Failed(T("Invalid pattern {{.Pattern}}: {{.Err}}", map[string]interface{}{"Pattern": pattern, "Err": err.Error()}))
+	}
+
+	runner.ui.Say(T("Getting apps matching {{.Pattern}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"Pattern":   terminal.EntityNameColor(pattern),
+			"OrgName":   terminal.EntityNameColor(runner.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(runner.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(runner.config.Username()),
+		}))
+
+	allApps, err := runner.appSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		runner.ui.Failed(err.Error())
+	}
+
+	apps := []models.Application{}
+	for _, app := range allApps {
+		if matched, _ := path.Match(pattern, app.Name); matched {
+			apps = append(apps, app)
+		}
+	}
+	sort.Sort(appsByName(apps))
+
+	runner.ui.Ok()
+	runner.ui.Say("")
+
+	if len(apps) == 0 {
+		runner.ui.Say(T("No apps found"))
+		return
+	}
+
+	table := terminal.NewTable(runner.ui, []string{T("name"), T("requested state"), T("instances"), T("memory")})
+	for _, app := range apps {
+		table.Add(
+			app.Name,
+			ui_helpers.ColoredAppState(app.ApplicationFields),
+			ui_helpers.ColoredAppInstances(app.ApplicationFields),
+			formatters.ByteSize(app.Memory*formatters.MEGABYTE),
+		)
+	}
+	table.Print()
+	runner.ui.Say("")
+
+	if !c.Bool("f") {
+		confirmed := runner.ui.Confirm(T("Really {{.Action}} these {{.Count}} apps?",
+			map[string]interface{}{"Action": action, "Count": len(apps)}))
+		if !confirmed {
+			return
+		}
+		runner.ui.Say("")
+	}
+
+	statuses, errs := runBulkOperation(apps, operation)
+
+	failed := 0
+	table = terminal.NewTable(runner.ui, []string{T("name"), T("result"), T("details")})
+	for i, app := range apps {
+		if errs[i] != nil {
+			failed++
+			table.Add(app.Name, terminal.FailureColor(T("failed")), errs[i].Error())
+		} else {
+			table.Add(app.Name, statuses[i], "")
+		}
+	}
+	table.Print()
+	runner.ui.Say("")
+
+	if failed > 0 {
+		runner.ui.Failed(T("{{.FailedCount}} of {{.Count}} apps failed", map[string]interface{}{"FailedCount": failed, "Count": len(apps)}))
+	}
+
+	runner.ui.Ok()
+}
+
+// runBulkOperation applies operation to apps, at most maxBulkConcurrency at
+// a time, and returns the results in the order of apps.
+func runBulkOperation(apps []models.Application, operation bulkAppOperation) ([]string, []error) {
+	statuses := make([]string, len(apps))
+	errs := make([]error, len(apps))
+
+	slots := make(chan struct{}, maxBulkConcurrency)
+	wg := sync.WaitGroup{}
+	for i, app := range apps {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, app models.Application) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			statuses[i], errs[i] = operation(app)
+		}(i, app)
+	}
+	wg.Wait()
+
+	return statuses, errs
+}
+
+type appsByName []models.Application
+
+func (apps appsByName) Len() int           { return len(apps) }
+func (apps appsByName) Swap(i, j int)      { apps[i], apps[j] = apps[j], apps[i] }
+func (apps appsByName) Less(i, j int) bool { return apps[i].Name < apps[j].Name }
@@ -0,0 +1,259 @@
+package application_test
+
+import (
+	testAppInstanaces "github.com/cloudfoundry/cli/cf/api/app_instances/fakes"
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("bulk app operations", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appRepo             *testApplication.FakeApplicationRepository
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		appInstancesRepo    *testAppInstanaces.FakeAppInstancesRepository
+		routeRepo           *testapi.FakeRouteRepository
+		deps                command_registry.Dependency
+	)
+
+	newApp := func(name, state string) models.Application {
+		app := models.Application{}
+		app.Name = name
+		app.Guid = name + "-guid"
+		app.State = state
+		app.InstanceCount = 1
+		app.Memory = 256
+		return app
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo = &testApplication.FakeApplicationRepository{}
+		appRepo.UpdateAppResult = models.Application{ApplicationFields: models.ApplicationFields{PackageState: "STAGED"}}
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{
+			newApp("payments-b", "stopped"),
+			newApp("payments-a", "started"),
+			newApp("orders", "started"),
+		}
+		appInstancesRepo = &testAppInstanaces.FakeAppInstancesRepository{}
+		appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceRunning}}, nil)
+		routeRepo = &testapi.FakeRouteRepository{}
+		deps = command_registry.NewDependency()
+	})
+
+	runCommand := func(name string, args ...string) bool {
+		return testcmd.RunCliCommand(name, args, requirementsFactory, func(pluginCall bool) {
+			deps.Ui = ui
+			deps.Config = configRepo
+			deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+			deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+			deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+			deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+			command_registry.Commands.SetCommand(command_registry.Commands.FindCommand(name).SetDependency(deps, pluginCall))
+		}, false)
+	}
+
+	Describe("requirements", func() {
+		It("does not require an app name", func() {
+			Expect(runCommand("stop", "--all", "-f")).To(BeTrue())
+		})
+
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("stop", "--all")).To(BeFalse())
+		})
+
+		It("fails with usage when given an app name as well", func() {
+			runCommand("stop", "--all", "my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "cannot be combined"},
+			))
+		})
+
+		It("fails with usage when given both --match and --all", func() {
+			runCommand("stop", "--all", "--match", "payments-*")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--match and --all cannot be combined"},
+			))
+		})
+
+		It("requires a change when scaling", func() {
+			runCommand("scale", "--all")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "require -i, -k or -m"},
+			))
+		})
+	})
+
+	It("shows the matching apps and asks for confirmation", func() {
+		ui.Inputs = []string{"n"}
+
+		runCommand("stop", "--match", "payments-*")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting apps matching", "payments-*", "my-org", "my-space", "my-user"},
+			[]string{"name", "requested state", "instances", "memory"},
+			[]string{"payments-a", "started"},
+			[]string{"payments-b", "stopped"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"orders"}))
+		Expect(ui.Prompts).To(ContainSubstrings([]string{"Really stop these 2 apps?"}))
+		Expect(appRepo.UpdateCalls).To(Equal(0))
+	})
+
+	It("applies the operation to the selected apps and prints the results", func() {
+		ui.Inputs = []string{"y"}
+
+		runCommand("stop", "--match", "payments-*")
+
+		Expect(appRepo.UpdateCalls).To(Equal(1))
+		Expect(appRepo.UpdateAppGuid).To(Equal("payments-a-guid"))
+		Expect(*appRepo.UpdateParams.State).To(Equal("STOPPED"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"name", "result", "details"},
+			[]string{"payments-a", "stopped"},
+			[]string{"payments-b", "already stopped"},
+			[]string{"OK"},
+		))
+	})
+
+	It("reports the apps the operation failed for", func() {
+		appRepo.UpdateErr = true
+
+		runCommand("stop", "--all", "-f")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"orders", "failed", "Error updating app."},
+			[]string{"payments-a", "failed", "Error updating app."},
+			[]string{"payments-b", "already stopped"},
+			[]string{"FAILED"},
+			[]string{"2 of 3 apps failed"},
+		))
+	})
+
+	It("says so when no apps match", func() {
+		runCommand("stop", "--match", "billing-*")
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"No apps found"}))
+		Expect(ui.Prompts).To(BeEmpty())
+	})
+
+	It("fails when the pattern is invalid", func() {
+		runCommand("stop", "--match", "payments-[")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Invalid pattern", "payments-["},
+		))
+	})
+
+	Describe("start", func() {
+		It("starts the selected apps and waits for them to run", func() {
+			runCommand("start", "--match", "payments-b", "-f")
+
+			Expect(appRepo.UpdateAppGuid).To(Equal("payments-b-guid"))
+			Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+			Expect(appInstancesRepo.GetInstancesArgsForCall(0)).To(Equal("payments-b-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"payments-b", "started"},
+				[]string{"OK"},
+			))
+		})
+
+		It("reports apps that fail to stage", func() {
+			appRepo.UpdateAppResult.PackageState = "FAILED"
+
+			runCommand("start", "--match", "payments-b", "-f")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"payments-b", "failed", "Staging failed"},
+				[]string{"FAILED"},
+			))
+		})
+
+		It("reports apps whose instances crash", func() {
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceCrashed}}, nil)
+
+			runCommand("start", "--match", "payments-b", "-f")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"payments-b", "failed", "Start unsuccessful"},
+			))
+		})
+	})
+
+	Describe("restart", func() {
+		It("stops and starts the selected apps", func() {
+			runCommand("restart", "--match", "orders", "-f")
+
+			Expect(appRepo.UpdateCalls).To(Equal(2))
+			Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"orders", "restarted"},
+				[]string{"OK"},
+			))
+		})
+	})
+
+	Describe("restage", func() {
+		It("restages the selected apps and waits for them to run", func() {
+			appRepo.GetAppReturns(models.Application{ApplicationFields: models.ApplicationFields{PackageState: "STAGED"}}, nil)
+
+			runCommand("restage", "--match", "orders", "-f")
+
+			Expect(appRepo.CreateRestageRequestArgs.AppGuid).To(Equal("orders-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"orders", "restaged"},
+				[]string{"OK"},
+			))
+		})
+	})
+
+	Describe("scale", func() {
+		It("scales the selected apps", func() {
+			runCommand("scale", "--match", "orders", "-i", "3", "-f")
+
+			Expect(appRepo.UpdateCalls).To(Equal(1))
+			Expect(*appRepo.UpdateParams.InstanceCount).To(Equal(3))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"orders", "scaled"}))
+		})
+
+		It("restarts started apps when their memory changes", func() {
+			runCommand("scale", "--match", "orders", "-m", "1G", "-f")
+
+			Expect(appRepo.UpdateCalls).To(Equal(3))
+			Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"orders", "scaled"}))
+		})
+	})
+
+	Describe("delete", func() {
+		It("deletes the selected apps and their routes", func() {
+			app := newApp("orders", "started")
+			app.Routes = []models.RouteSummary{{Guid: "route-guid"}}
+			appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{app}
+
+			runCommand("delete", "--all", "-r", "-f")
+
+			Expect(routeRepo.DeletedRouteGuids).To(Equal([]string{"route-guid"}))
+			Expect(appRepo.DeletedAppGuid).To(Equal("orders-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"orders", "deleted"}))
+		})
+	})
+})
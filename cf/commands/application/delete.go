@@ -7,6 +7,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
 	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/simonleung8/flags"
@@ -14,11 +15,12 @@ import (
 )
 
 type DeleteApp struct {
-	ui        terminal.UI
-	config    core_config.Reader
-	appRepo   applications.ApplicationRepository
-	routeRepo api.RouteRepository
-	appReq    requirements.ApplicationRequirement
+	ui             terminal.UI
+	config         core_config.Reader
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	routeRepo      api.RouteRepository
+	appReq         requirements.ApplicationRequirement
 }
 
 func init() {
@@ -29,17 +31,23 @@ func (cmd *DeleteApp) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["f"] = &cliFlags.BoolFlag{Name: "f", Usage: T("Force deletion without confirmation")}
 	fs["r"] = &cliFlags.BoolFlag{Name: "r", Usage: T("Also delete any mapped routes")}
+	addBulkFlags(fs)
 
 	return command_registry.CommandMetadata{
 		Name:        "delete",
 		ShortName:   "d",
 		Description: T("Delete an app"),
-		Usage:       T("CF_NAME delete APP_NAME [-f -r]"),
+		Usage:       T("CF_NAME delete APP_NAME [-f -r]\n   CF_NAME delete (--match PATTERN | --all) [-f -r]"),
 		Flags:       fs,
 	}
 }
 
 func (cmd *DeleteApp) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		reqs = bulkRequirements(cmd.ui, "delete", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires app name as argument\n\n") + command_registry.Commands.CommandUsage("delete"))
 	}
@@ -53,11 +61,19 @@ func (cmd *DeleteApp) SetDependency(deps command_registry.Dependency, pluginCall
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
 	return cmd
 }
 
 func (cmd *DeleteApp) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("delete"), func(app models.Application) (string, error) {
+			return cmd.deleteApp(app, c.Bool("r"))
+		})
+		return
+	}
+
 	appName := c.Args()[0]
 
 	if !c.Bool("f") {
@@ -102,3 +118,21 @@ func (cmd *DeleteApp) Execute(c flags.FlagContext) {
 
 	cmd.ui.Ok()
 }
+
+func (cmd *DeleteApp) deleteApp(app models.Application, deleteRoutes bool) (string, error) {
+	if deleteRoutes {
+		for _, route := range app.Routes {
+			err := cmd.routeRepo.Delete(route.Guid)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	err := cmd.appRepo.Delete(app.Guid)
+	if err != nil {
+		return "", err
+	}
+
+	return T("deleted"), nil
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"io"
+
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type DownloadAppBits struct {
+	ui          terminal.UI
+	config      core_config.Reader
+	dropletRepo application_bits.ApplicationDropletRepository
+	appReq      requirements.ApplicationRequirement
+}
+
+func init() {
+	command_registry.Register(&DownloadAppBits{})
+}
+
+func (cmd *DownloadAppBits) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["o"] = &cliFlags.StringFlag{Name: "o", Usage: T("Path of the file to save the app files to (default: APP_NAME.zip)")}
+
+	return command_registry.CommandMetadata{
+		Name:        "download-app-bits",
+		Description: T("Download the files last pushed for an app as a zip file"),
+		Usage:       T("CF_NAME download-app-bits APP_NAME [-o PATH]"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *DownloadAppBits) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("download-app-bits"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *DownloadAppBits) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	return cmd
+}
+
+func (cmd *DownloadAppBits) Execute(c flags.FlagContext) {
+	app := cmd.appReq.GetApplication()
+
+	path := c.String("o")
+	if path == "" {
+		path = app.Name + ".zip"
+	}
+
+	cmd.ui.Say(T("Downloading files of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	sha1, err := downloadToFile(path, func(destination io.Writer) (string, error) {
+		return cmd.dropletRepo.DownloadBits(app.Guid, destination)
+	})
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("App files saved to {{.Path}}", map[string]interface{}{"Path": terminal.EntityNameColor(path)}))
+	cmd.ui.Say(T("SHA1: {{.Sha1}}", map[string]interface{}{"Sha1": sha1}))
+}
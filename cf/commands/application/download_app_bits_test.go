@@ -0,0 +1,109 @@
+package application_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	testbits "github.com/cloudfoundry/cli/cf/api/application_bits/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("download-app-bits command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		dropletRepo         *testbits.FakeApplicationDropletRepository
+		deps                command_registry.Dependency
+		outputDir           string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationDropletRepository(dropletRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("download-app-bits").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		dropletRepo = &testbits.FakeApplicationDropletRepository{}
+
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		var err error
+		outputDir, err = ioutil.TempDir("", "download-app-bits")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputDir)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("download-app-bits", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("my-app")).To(BeFalse())
+		})
+
+		It("fails with usage when not provided an app name", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	It("saves the app files and their checksum", func() {
+		dropletRepo.DownloadBitsStub = func(appGuid string, destination io.Writer) (string, error) {
+			destination.Write([]byte("app contents"))
+			return "app-sha1", nil
+		}
+		path := filepath.Join(outputDir, "app.zip")
+
+		runCommand("-o", path, "my-app")
+
+		appGuid, _ := dropletRepo.DownloadBitsArgsForCall(0)
+		Expect(appGuid).To(Equal("my-app-guid"))
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("app contents"))
+
+		checksum, err := ioutil.ReadFile(path + ".sha1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(checksum)).To(Equal("app-sha1  app.zip\n"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Downloading files of app", "my-app", "my-org", "my-space", "my-user"},
+			[]string{"OK"},
+			[]string{"App files saved to", path},
+			[]string{"SHA1: app-sha1"},
+		))
+	})
+})
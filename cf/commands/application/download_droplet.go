@@ -0,0 +1,124 @@
+package application
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type DownloadDroplet struct {
+	ui          terminal.UI
+	config      core_config.Reader
+	dropletRepo application_bits.ApplicationDropletRepository
+	appReq      requirements.ApplicationRequirement
+}
+
+func init() {
+	command_registry.Register(&DownloadDroplet{})
+}
+
+func (cmd *DownloadDroplet) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["o"] = &cliFlags.StringFlag{Name: "o", Usage: T("Path of the file to save the droplet to (default: APP_NAME-droplet.tgz)")}
+
+	return command_registry.CommandMetadata{
+		Name:        "download-droplet",
+		Description: T("Download the staged droplet of an app"),
+		Usage:       T("CF_NAME download-droplet APP_NAME [-o PATH]"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *DownloadDroplet) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("download-droplet"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *DownloadDroplet) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	return cmd
+}
+
+func (cmd *DownloadDroplet) Execute(c flags.FlagContext) {
+	app := cmd.appReq.GetApplication()
+
+	path := c.String("o")
+	if path == "" {
+		path = app.Name + "-droplet.tgz"
+	}
+
+	cmd.ui.Say(T("Downloading droplet of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	sha1, err := downloadToFile(path, func(destination io.Writer) (string, error) {
+		return cmd.dropletRepo.DownloadDroplet(app.Guid, destination)
+	})
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Droplet saved to {{.Path}}", map[string]interface{}{"Path": terminal.EntityNameColor(path)}))
+	cmd.ui.Say(T("SHA1: {{.Sha1}}", map[string]interface{}{"Sha1": sha1}))
+}
+
+// downloadToFile saves a download to path, along with a file containing its
+// SHA1 in the format of sha1sum. Nothing is written to path unless the whole
+// download succeeds.
+func downloadToFile(path string, download func(io.Writer) (string, error)) (sha1 string, err error) {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), ".download-")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+
+	sha1, err = download(tmpFile)
+	closeErr := tmpFile.Close()
+	if err != nil {
+		return
+	}
+	if closeErr != nil {
+		err = closeErr
+		return
+	}
+
+	err = os.Rename(tmpFile.Name(), path)
+	if err != nil {
+		return
+	}
+
+	err = ioutil.WriteFile(checksumFilePath(path), []byte(fmt.Sprintf("%s  %s\n", sha1, filepath.Base(path))), 0644)
+	return
+}
+
+func checksumFilePath(path string) string {
+	return path + ".sha1"
+}
@@ -3,10 +3,14 @@ package application
 import (
 	"encoding/json"
 	"sort"
+	"strings"
 
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
 	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
 
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
@@ -26,11 +30,24 @@ func init() {
 }
 
 func (cmd *Env) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["format"] = &cliFlags.StringFlag{Name: "format", Usage: T("Print only the env variables the app runs with, as json, as shell exports (env) or as a .env file (dotenv)")}
+
+	baseUsage := T(`CF_NAME env APP_NAME [--format json|env|dotenv]
+
+   With --format env or dotenv the credentials of each bound service instance are also
+   given as variables named after the instance, e.g. MY_DB_URI for the uri of mydb.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME env my-app
+   eval "$(CF_NAME env my-app --format env)"
+   CF_NAME env my-app --format dotenv > .env`)
+
 	return command_registry.CommandMetadata{
 		Name:        "env",
 		ShortName:   "e",
 		Description: T("Show all env variables for an app"),
-		Usage:       T("CF_NAME env APP_NAME"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
 	}
 }
 
@@ -39,6 +56,12 @@ func (cmd *Env) Requirements(requirementsFactory requirements.Factory, fc flags.
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("env"))
 	}
 
+	switch fc.String("format") {
+	case "", "json", ui_helpers.EnvVarFormatEnv, ui_helpers.EnvVarFormatDotenv:
+	default:
+		cmd.ui.Failed(T("Incorrect Usage. --format must be json, env or dotenv\n\n") + command_registry.Commands.CommandUsage("env"))
+	}
+
 	reqs = []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
 		requirementsFactory.NewTargetedSpaceRequirement(),
@@ -54,9 +77,25 @@ func (cmd *Env) SetDependency(deps command_registry.Dependency, pluginCall bool)
 }
 
 func (cmd *Env) Execute(c flags.FlagContext) {
+	format := c.String("format")
+
 	app, err := cmd.appRepo.Read(c.Args()[0])
 	if notFound, ok := err.(*errors.ModelNotFoundError); ok {
-		cmd.ui.Failed(notFound.Error())
+		if format != "" {
+			cmd.ui.FailedOnStderr(notFound.Error())
+		} else {
+			cmd.ui.Failed(notFound.Error())
+		}
+	}
+
+	if format != "" {
+		env, err := cmd.appRepo.ReadEnv(app.Guid)
+		if err != nil {
+			cmd.ui.FailedOnStderr(err.Error())
+		}
+
+		cmd.printEnvVars(env, format)
+		return
 	}
 
 	cmd.ui.Say(T("Getting env variables for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
@@ -84,6 +123,70 @@ func (cmd *Env) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 }
 
+// printEnvVars prints nothing but the env variables the app runs with, so
+// that the output can be redirected to a file or sourced by a shell to run
+// the app locally against the same services. User-provided variables take
+// precedence over the running environment variable group. Failures go to
+// stderr for the same reason.
+func (cmd *Env) printEnvVars(env *models.Environment, format string) {
+	envVars := map[string]interface{}{}
+	for key, value := range env.Running {
+		envVars[key] = value
+	}
+	for key, value := range env.Environment {
+		envVars[key] = value
+	}
+	if vcapServices, ok := env.System["VCAP_SERVICES"]; ok {
+		envVars["VCAP_SERVICES"] = vcapServices
+	}
+	if vcapApplication, ok := env.Application["VCAP_APPLICATION"]; ok {
+		envVars["VCAP_APPLICATION"] = vcapApplication
+	}
+
+	if format == "json" {
+		jsonBytes, err := json.MarshalIndent(envVars, "", " ")
+		if err != nil {
+			cmd.ui.FailedOnStderr(err.Error())
+		}
+		cmd.ui.Say("%s", jsonBytes)
+		return
+	}
+
+	vars := serviceCredentialEnvVars(env.System["VCAP_SERVICES"])
+	for key, value := range envVars {
+		vars[key] = ui_helpers.EnvVarValue(value)
+	}
+
+	for _, line := range ui_helpers.FormatEnvVars(vars, format) {
+		cmd.ui.Say("%s", line)
+	}
+}
+
+// serviceCredentialEnvVars flattens the credentials of each service instance
+// in VCAP_SERVICES into variables prefixed with the instance name.
+func serviceCredentialEnvVars(vcapServices interface{}) map[string]string {
+	vars := map[string]string{}
+
+	services, _ := vcapServices.(map[string]interface{})
+	for _, instances := range services {
+		instanceList, _ := instances.([]interface{})
+		for _, instance := range instanceList {
+			instanceMap, ok := instance.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _ := instanceMap["name"].(string)
+			credentials, _ := instanceMap["credentials"].(map[string]interface{})
+			for key, value := range ui_helpers.FlattenCredentials(name, credentials) {
+				vars[key] = value
+			}
+		}
+	}
+
+	return vars
+}
+
 func (cmd *Env) displaySystemiAndAppProvidedEnvironment(env map[string]interface{}, app map[string]interface{}) {
 	var vcapServices string
 	var vcapApplication string
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/simonleung8/flags"
+)
+
+type EnvDiff struct {
+	ui     terminal.UI
+	config core_config.Reader
+	appReq requirements.ApplicationRequirement
+}
+
+func init() {
+	command_registry.Register(&EnvDiff{})
+}
+
+func (cmd *EnvDiff) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:        "env-diff",
+		Description: T("Show how the user-provided env variables of an app differ from a file"),
+		Usage: T(`CF_NAME env-diff APP_NAME FILE
+
+   Files contain a JSON object or dotenv lines (NAME=value), as read by set-env --from-file.
+   Fails when there are differences.
+
+EXAMPLE:
+   CF_NAME env-diff my-app .env`),
+	}
+}
+
+func (cmd *EnvDiff) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name file' as arguments\n\n") + command_registry.Commands.CommandUsage("env-diff"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *EnvDiff) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	return cmd
+}
+
+func (cmd *EnvDiff) Execute(c flags.FlagContext) {
+	path := c.Args()[1]
+	app := cmd.appReq.GetApplication()
+
+	cmd.ui.Say(T("Comparing env variables of app {{.AppName}} with {{.Path}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"Path":        terminal.EntityNameColor(path),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username())}))
+	cmd.ui.Say("")
+
+	fileVars, err := ui_helpers.ReadEnvVarsFile(path)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
+	lines := ui_helpers.EnvVarsDiff(app.EnvironmentVars, fileVars)
+	if len(lines) == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("No differences found"))
+		return
+	}
+
+	cmd.ui.Say("%s", terminal.DiffRemovedColor("--- "+T("deployed: {{.AppName}}", map[string]interface{}{"AppName": app.Name})))
+	cmd.ui.Say("%s", terminal.DiffAddedColor("+++ "+T("file: {{.Path}}", map[string]interface{}{"Path": path})))
+	for _, line := range lines {
+		cmd.ui.Say("%s", line)
+	}
+	cmd.ui.Say("")
+
+	cmd.ui.Failed(T("Env variables of app {{.AppName}} differ from {{.Path}}",
+		map[string]interface{}{"AppName": app.Name, "Path": path}))
+}
@@ -0,0 +1,137 @@
+package application_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("env-diff command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		deps                command_registry.Dependency
+		dir                 string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("env-diff").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.EnvironmentVars = map[string]interface{}{
+			"LOG_LEVEL":    "info",
+			"DATABASE_URL": "mysql://example.com/my-db",
+			"OLD_FLAG":     "true",
+		}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		var err error
+		dir, err = ioutil.TempDir("", "env-diff")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(contents string) string {
+		path := filepath.Join(dir, ".env")
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("env-diff", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-app", ".env")).To(BeFalse())
+		})
+
+		It("requires the app", func() {
+			Expect(runCommand("my-app", writeFile(""))).To(BeTrue())
+			Expect(requirementsFactory.ApplicationName).To(Equal("my-app"))
+		})
+
+		It("fails with usage when not given an app and a file", func() {
+			runCommand("my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires", "arguments"},
+			))
+		})
+	})
+
+	It("shows the changed, added and removed variables and fails", func() {
+		path := writeFile("LOG_LEVEL=debug\nDATABASE_URL=mysql://example.com/my-db\nWORKERS=4\n")
+
+		runCommand("my-app", path)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Comparing env variables of app", "my-app", path, "my-org", "my-space", "my-user"},
+			[]string{"--- deployed: my-app"},
+			[]string{"+++ file:", path},
+			[]string{"- LOG_LEVEL: info"},
+			[]string{"+ LOG_LEVEL: debug"},
+			[]string{"- OLD_FLAG: true"},
+			[]string{"+ WORKERS: 4"},
+			[]string{"FAILED"},
+			[]string{"Env variables of app my-app differ from", path},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"DATABASE_URL"}))
+	})
+
+	It("shows file paths with percent signs as they are", func() {
+		path := filepath.Join(dir, "100%d.env")
+		Expect(ioutil.WriteFile(path, []byte("LOG_LEVEL=debug\n"), 0600)).To(Succeed())
+
+		runCommand("my-app", path)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"+++ file:", "100%d.env"},
+		))
+	})
+
+	It("says when there are no differences", func() {
+		path := writeFile(`{"LOG_LEVEL": "info", "DATABASE_URL": "mysql://example.com/my-db", "OLD_FLAG": "true"}`)
+
+		runCommand("my-app", path)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"OK"},
+			[]string{"No differences found"},
+		))
+	})
+
+	It("fails when the file cannot be read", func() {
+		runCommand("my-app", filepath.Join(dir, "missing.env"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Error reading env file", "missing.env"},
+		))
+	})
+})
@@ -79,6 +79,17 @@ var _ = Describe("env command", func() {
 		))
 	})
 
+	It("fails on stderr when the app cannot be found and a format is given", func() {
+		appRepo.ReadReturns.Error = errors.NewModelNotFoundError("app", "hocus-pocus")
+		runCommand("hocus-pocus", "--format", "json")
+
+		Expect(ui.Outputs).To(BeEmpty())
+		Expect(ui.ErrOutputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"not found"},
+		))
+	})
+
 	Context("when the app has at least one env var", func() {
 		BeforeEach(func() {
 			app = models.Application{}
@@ -212,11 +223,105 @@ var _ = Describe("env command", func() {
 		})
 	})
 
+	Context("when given --format", func() {
+		BeforeEach(func() {
+			app = models.Application{}
+			app.Name = "my-app"
+			app.Guid = "the-app-guid"
+
+			appRepo.ReadReturns.App = app
+			appRepo.ReadEnvReturns(&models.Environment{
+				Environment: map[string]interface{}{
+					"LOG_LEVEL": "debug",
+					"WORKERS":   4,
+				},
+				Running: map[string]interface{}{
+					"LOG_LEVEL": "info",
+					"REGION":    "eu",
+				},
+				Staging: map[string]interface{}{
+					"STAGING_ONLY": "true",
+				},
+				System: map[string]interface{}{
+					"VCAP_SERVICES": map[string]interface{}{
+						"p-mysql": []interface{}{
+							map[string]interface{}{
+								"name": "my-db",
+								"credentials": map[string]interface{}{
+									"uri":      "mysql://host/db",
+									"password": "it's 100%",
+								},
+							},
+						},
+					},
+				},
+				Application: map[string]interface{}{
+					"VCAP_APPLICATION": map[string]interface{}{
+						"name": "my-app",
+					},
+				},
+			}, nil)
+		})
+
+		It("fails with usage when given an unknown format", func() {
+			runCommand("my-app", "--format", "yaml")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--format must be json, env or dotenv"},
+			))
+		})
+
+		It("prints the env variables as json", func() {
+			runCommand("my-app", "--format", "json")
+
+			Expect(ui.Outputs[0]).To(Equal("{"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{`"LOG_LEVEL": "debug"`},
+				[]string{`"REGION": "eu"`},
+				[]string{`"VCAP_APPLICATION": {`},
+				[]string{`"VCAP_SERVICES": {`},
+			))
+			Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"Getting env variables"}))
+			Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"STAGING_ONLY"}))
+		})
+
+		It("prints the env variables and the flattened service credentials as shell exports", func() {
+			runCommand("my-app", "--format", "env")
+
+			Expect(ui.Outputs).To(Equal([]string{
+				"export LOG_LEVEL='debug'",
+				`export MY_DB_PASSWORD='it'\''s 100%'`,
+				"export MY_DB_URI='mysql://host/db'",
+				"export REGION='eu'",
+				`export VCAP_APPLICATION='{"name":"my-app"}'`,
+				`export VCAP_SERVICES='{"p-mysql":[{"credentials":{"password":"it'\''s 100%","uri":"mysql://host/db"},"name":"my-db"}]}'`,
+				"export WORKERS='4'",
+			}))
+		})
+
+		It("prints the env variables as a .env file", func() {
+			runCommand("my-app", "--format", "dotenv")
+
+			Expect(ui.Outputs).To(ContainElement(`LOG_LEVEL="debug"`))
+			Expect(ui.Outputs).To(ContainElement(`MY_DB_URI="mysql://host/db"`))
+			Expect(ui.Outputs).To(ContainElement(`VCAP_APPLICATION="{\"name\":\"my-app\"}"`))
+		})
+	})
+
 	Context("when reading the environment variables returns an error", func() {
 		It("tells you about that error", func() {
 			appRepo.ReadEnvReturns(nil, errors.New("BOO YOU CANT DO THAT; GO HOME; you're drunk"))
 			runCommand("whatever")
 			Expect(ui.Outputs).To(ContainSubstrings([]string{"you're drunk"}))
 		})
+
+		It("tells you about that error on stderr when a format is given", func() {
+			appRepo.ReadEnvReturns(nil, errors.New("BOO YOU CANT DO THAT; GO HOME; you're drunk"))
+			runCommand("whatever", "--format", "env")
+			Expect(ui.Outputs).To(BeEmpty())
+			Expect(ui.ErrOutputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"you're drunk"},
+			))
+		})
 	})
 })
@@ -1,19 +1,32 @@
 package application
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"time"
+
 	"github.com/cloudfoundry/cli/cf/api/app_events"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
 	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
 )
 
+const recentEventsLimit = 50
+
 type Events struct {
 	ui         terminal.UI
 	config     core_config.Reader
 	appReq     requirements.ApplicationRequirement
+	spaceReq   requirements.SpaceRequirement
+	orgReq     requirements.OrganizationRequirement
 	eventsRepo app_events.AppEventsRepository
 }
 
@@ -22,24 +35,74 @@ func init() {
 }
 
 func (cmd *Events) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["space"] = &cliFlags.StringFlag{Name: "space", Usage: T("Show the events of every app and service in a space of the targeted org")}
+	fs["org"] = &cliFlags.StringFlag{Name: "org", Usage: T("Show the events of everything in an org")}
+	fs["since"] = &cliFlags.StringFlag{Name: "since", Usage: T("Only show events at or after this time (RFC3339 time or YYYY-MM-DD date)")}
+	fs["until"] = &cliFlags.StringFlag{Name: "until", Usage: T("Only show events at or before this time (RFC3339 time or YYYY-MM-DD date, which includes the whole day)")}
+	fs["type"] = &cliFlags.StringSliceFlag{Name: "type", Usage: T("Only show events of this type, flag can be specified multiple times")}
+	fs["actor"] = &cliFlags.StringFlag{Name: "actor", Usage: T("Only show events caused by this user name or guid")}
+	fs["all"] = &cliFlags.BoolFlag{Name: "all", Usage: T("Show every matching event instead of the 50 most recent")}
+	fs["format"] = &cliFlags.StringFlag{Name: "format", Usage: T("Print the events as json or csv")}
+
 	return command_registry.CommandMetadata{
 		Name:        "events",
-		Description: T("Show recent app events"),
-		Usage:       T("CF_NAME events APP_NAME"),
+		Description: T("Show recent or all events of an app, space or org"),
+		Usage: T(`CF_NAME events APP_NAME [--since TIME] [--until TIME] [--type TYPE] [--actor ACTOR] [--all] [--format json|csv]
+   CF_NAME events (--space SPACE | --org ORG) [--since TIME] [--until TIME] [--type TYPE] [--actor ACTOR] [--all] [--format json|csv]
+
+EXAMPLE:
+   CF_NAME events my-app
+   CF_NAME events my-app --type audit.app.update --since 2015-06-01
+   CF_NAME events --org my-org --since 2015-06-01 --until 2015-06-30 --all --format csv > june.csv`),
+		Flags: fs,
 	}
 }
 
 func (cmd *Events) Requirements(requirementsFactory requirements.Factory, c flags.FlagContext) (reqs []requirements.Requirement, err error) {
-	if len(c.Args()) != 1 {
-		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("events"))
+	scopes := len(c.Args())
+	if c.String("space") != "" {
+		scopes++
+	}
+	if c.String("org") != "" {
+		scopes++
 	}
 
-	cmd.appReq = requirementsFactory.NewApplicationRequirement(c.Args()[0])
+	if scopes != 1 || len(c.Args()) > 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an app name as argument, or one of --space and --org\n\n") + command_registry.Commands.CommandUsage("events"))
+	}
+
+	switch c.String("format") {
+	case "", "json", "csv":
+	default:
+		cmd.ui.Failed(T("Incorrect Usage. --format must be json or csv\n\n") + command_registry.Commands.CommandUsage("events"))
+	}
 
+	cmd.appReq, cmd.spaceReq, cmd.orgReq = nil, nil, nil
 	reqs = []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
-		requirementsFactory.NewTargetedSpaceRequirement(),
-		cmd.appReq,
+	}
+
+	switch {
+	case c.String("space") != "":
+		cmd.spaceReq = requirementsFactory.NewSpaceRequirement(c.String("space"))
+		reqs = append(reqs,
+			requirementsFactory.NewMinCCApiVersionRequirement("events --space", 2, 1, 0),
+			requirementsFactory.NewTargetedOrgRequirement(),
+			cmd.spaceReq,
+		)
+	case c.String("org") != "":
+		cmd.orgReq = requirementsFactory.NewOrganizationRequirement(c.String("org"))
+		reqs = append(reqs,
+			requirementsFactory.NewMinCCApiVersionRequirement("events --org", 2, 1, 0),
+			cmd.orgReq,
+		)
+	default:
+		cmd.appReq = requirementsFactory.NewApplicationRequirement(c.Args()[0])
+		reqs = append(reqs,
+			requirementsFactory.NewTargetedSpaceRequirement(),
+			cmd.appReq,
+		)
 	}
 	return
 }
@@ -52,38 +115,228 @@ func (cmd *Events) SetDependency(deps command_registry.Dependency, pluginCall bo
 }
 
 func (cmd *Events) Execute(c flags.FlagContext) {
-	app := cmd.appReq.GetApplication()
+	filter, err := cmd.eventsFilter(c)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
 
-	cmd.ui.Say(T("Getting events for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
-		map[string]interface{}{
-			"AppName":   terminal.EntityNameColor(app.Name),
-			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	format := c.String("format")
+	if format == "" {
+		cmd.sayGettingEvents()
+	}
 
-	table := cmd.ui.Table([]string{T("time"), T("event"), T("actor"), T("description")})
+	var events []models.EventFields
+	var apiErr error
+	if isRecentEventsQuery(c) {
+		events, apiErr = cmd.eventsRepo.RecentEvents(filter.AppGuid, recentEventsLimit)
+	} else {
+		limit := int64(recentEventsLimit)
+		if c.Bool("all") {
+			limit = 0
+		}
+		events, apiErr = cmd.eventsRepo.ListEvents(filter, limit)
+	}
 
-	events, apiErr := cmd.eventsRepo.RecentEvents(app.Guid, 50)
 	if apiErr != nil {
 		cmd.ui.Failed(T("Failed fetching events.\n{{.ApiErr}}",
 			map[string]interface{}{"ApiErr": apiErr.Error()}))
 		return
 	}
 
+	switch format {
+	case "json":
+		cmd.printEventsJSON(events)
+		return
+	case "csv":
+		cmd.printEventsCSV(events)
+		return
+	}
+
+	showActee := filter.AppGuid == ""
+	headers := []string{T("time"), T("event"), T("actor"), T("description")}
+	if showActee {
+		headers = []string{T("time"), T("event"), T("actee"), T("actor"), T("description")}
+	}
+	table := cmd.ui.Table(headers)
+
 	for _, event := range events {
-		table.Add(
+		row := []string{
 			event.Timestamp.Local().Format("2006-01-02T15:04:05.00-0700"),
 			event.Name,
 			event.ActorName,
 			event.Description,
-		)
+		}
+		if showActee {
+			row = []string{row[0], row[1], acteeDescription(event), row[2], row[3]}
+		}
+		table.Add(row...)
 	}
 
 	table.Print()
 
 	if len(events) == 0 {
+		cmd.sayNoEvents()
+		return
+	}
+}
+
+func (cmd *Events) sayGettingEvents() {
+	switch {
+	case cmd.spaceReq != nil:
+		cmd.ui.Say(T("Getting events for space {{.SpaceName}} in org {{.OrgName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"SpaceName": terminal.EntityNameColor(cmd.spaceReq.GetSpace().Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	case cmd.orgReq != nil:
+		cmd.ui.Say(T("Getting events for org {{.OrgName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"OrgName":  terminal.EntityNameColor(cmd.orgReq.GetOrganization().Name),
+				"Username": terminal.EntityNameColor(cmd.config.Username())}))
+	default:
+		cmd.ui.Say(T("Getting events for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...\n",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(cmd.appReq.GetApplication().Name),
+				"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+				"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+				"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	}
+}
+
+func (cmd *Events) sayNoEvents() {
+	switch {
+	case cmd.spaceReq != nil:
+		cmd.ui.Say(T("No events for space {{.SpaceName}}",
+			map[string]interface{}{"SpaceName": terminal.EntityNameColor(cmd.spaceReq.GetSpace().Name)}))
+	case cmd.orgReq != nil:
+		cmd.ui.Say(T("No events for org {{.OrgName}}",
+			map[string]interface{}{"OrgName": terminal.EntityNameColor(cmd.orgReq.GetOrganization().Name)}))
+	default:
 		cmd.ui.Say(T("No events for app {{.AppName}}",
-			map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+			map[string]interface{}{"AppName": terminal.EntityNameColor(cmd.appReq.GetApplication().Name)}))
+	}
+}
+
+func (cmd *Events) printEventsJSON(events []models.EventFields) {
+	type eventJSON struct {
+		Guid        string `json:"guid"`
+		Type        string `json:"type"`
+		Timestamp   string `json:"timestamp"`
+		Actor       string `json:"actor"`
+		ActorName   string `json:"actor_name"`
+		ActeeType   string `json:"actee_type"`
+		ActeeName   string `json:"actee_name"`
+		Description string `json:"description"`
+	}
+
+	output := []eventJSON{}
+	for _, event := range events {
+		output = append(output, eventJSON{
+			Guid:        event.Guid,
+			Type:        event.Name,
+			Timestamp:   event.Timestamp.UTC().Format(time.RFC3339),
+			Actor:       event.Actor,
+			ActorName:   event.ActorName,
+			ActeeType:   event.ActeeType,
+			ActeeName:   event.ActeeName,
+			Description: event.Description,
+		})
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		cmd.ui.Failed(err.Error())
 		return
 	}
+
+	cmd.ui.Say("%s", string(jsonBytes))
+}
+
+func (cmd *Events) printEventsCSV(events []models.EventFields) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	writer.Write([]string{"guid", "type", "timestamp", "actor", "actor_name", "actee_type", "actee_name", "description"})
+	for _, event := range events {
+		writer.Write([]string{
+			event.Guid,
+			event.Name,
+			event.Timestamp.UTC().Format(time.RFC3339),
+			event.Actor,
+			event.ActorName,
+			event.ActeeType,
+			event.ActeeName,
+			event.Description,
+		})
+	}
+	writer.Flush()
+
+	cmd.ui.Say("%s", strings.TrimSuffix(buffer.String(), "\n"))
+}
+
+func (cmd *Events) eventsFilter(c flags.FlagContext) (models.EventsFilter, error) {
+	filter := models.EventsFilter{
+		Types: c.StringSlice("type"),
+		Actor: c.String("actor"),
+	}
+
+	switch {
+	case cmd.spaceReq != nil:
+		filter.SpaceGuid = cmd.spaceReq.GetSpace().Guid
+	case cmd.orgReq != nil:
+		filter.OrgGuid = cmd.orgReq.GetOrganization().Guid
+	default:
+		filter.AppGuid = cmd.appReq.GetApplication().Guid
+	}
+
+	var err error
+	if c.String("since") != "" {
+		filter.Since, err = parseEventsTime(c.String("since"), false)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if c.String("until") != "" {
+		filter.Until, err = parseEventsTime(c.String("until"), true)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, errors.New(T("--until must not be before --since"))
+	}
+
+	return filter, nil
+}
+
+// parseEventsTime accepts RFC3339 times and local dates; a date used as the
+// end of a range includes the whole day.
+func parseEventsTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New(T("Invalid time {{.Time}}, use an RFC3339 time like 2015-06-01T12:00:00Z or a date like 2015-06-01", map[string]interface{}{"Time": value}))
+	}
+
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+func isRecentEventsQuery(c flags.FlagContext) bool {
+	return len(c.Args()) == 1 && !c.Bool("all") && len(c.StringSlice("type")) == 0 &&
+		c.String("actor") == "" && c.String("since") == "" && c.String("until") == ""
+}
+
+func acteeDescription(event models.EventFields) string {
+	if event.ActeeType == "" {
+		return event.ActeeName
+	}
+	return event.ActeeType + " " + event.ActeeName
 }
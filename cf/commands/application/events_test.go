@@ -1,6 +1,8 @@
 package application_test
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	testapi "github.com/cloudfoundry/cli/cf/api/app_events/fakes"
@@ -126,4 +128,149 @@ var _ = Describe("events command", func() {
 			[]string{"No events", "my-app"},
 		))
 	})
+
+	Describe("searching events", func() {
+		var event models.EventFields
+
+		BeforeEach(func() {
+			app := models.Application{}
+			app.Name = "my-app"
+			app.Guid = "my-app-guid"
+			requirementsFactory.Application = app
+
+			space := models.Space{}
+			space.Name = "my-space"
+			space.Guid = "my-space-guid"
+			requirementsFactory.Space = space
+
+			org := models.Organization{}
+			org.Name = "my-org"
+			org.Guid = "my-org-guid"
+			requirementsFactory.Organization = org
+
+			timestamp, err := time.Parse(TIMESTAMP_FORMAT, "2015-06-01T12:00:00.00-0000")
+			Expect(err).NotTo(HaveOccurred())
+			event = models.EventFields{
+				Guid:        "event-guid",
+				Name:        "audit.app.update",
+				Timestamp:   timestamp,
+				Description: "instances: 2",
+				Actor:       "user-guid",
+				ActorName:   "admin",
+				ActeeType:   "app",
+				ActeeName:   "my-app",
+			}
+			eventsRepo.ListEventsReturns([]models.EventFields{event}, nil)
+			requirementsFactory.TargetedOrgSuccess = true
+		})
+
+		It("fails with usage when given both an app and a scope", func() {
+			runCommand("my-app", "--space", "my-space")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "one of --space and --org"},
+			))
+		})
+
+		It("fails with usage when given an unknown format", func() {
+			runCommand("my-app", "--format", "xml")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--format must be json or csv"},
+			))
+		})
+
+		It("filters the events of an app", func() {
+			runCommand("my-app", "--type", "audit.app.update", "--type", "audit.app.start", "--actor", "admin",
+				"--since", "2015-06-01T00:00:00Z", "--until", "2015-06-30")
+
+			Expect(eventsRepo.RecentEventsCallCount()).To(Equal(0))
+			Expect(eventsRepo.ListEventsCallCount()).To(Equal(1))
+			filter, limit := eventsRepo.ListEventsArgsForCall(0)
+			Expect(limit).To(Equal(int64(50)))
+			Expect(filter.AppGuid).To(Equal("my-app-guid"))
+			Expect(filter.Types).To(Equal([]string{"audit.app.update", "audit.app.start"}))
+			Expect(filter.Actor).To(Equal("admin"))
+			Expect(filter.Since).To(Equal(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(filter.Until).To(Equal(time.Date(2015, 7, 1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond)))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"time", "event", "actor", "description"},
+				[]string{"audit.app.update", "admin", "instances: 2"},
+			))
+		})
+
+		It("fails when a time is invalid", func() {
+			runCommand("my-app", "--since", "last tuesday")
+
+			Expect(eventsRepo.ListEventsCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Invalid time last tuesday"},
+			))
+		})
+
+		It("lists the events of a space", func() {
+			Expect(runCommand("--space", "my-space")).To(BeTrue())
+
+			Expect(requirementsFactory.SpaceName).To(Equal("my-space"))
+			Expect(requirementsFactory.MinCCApiVersionMajor).To(Equal(2))
+			Expect(requirementsFactory.MinCCApiVersionMinor).To(Equal(1))
+
+			filter, limit := eventsRepo.ListEventsArgsForCall(0)
+			Expect(limit).To(Equal(int64(50)))
+			Expect(filter.SpaceGuid).To(Equal("my-space-guid"))
+			Expect(filter.AppGuid).To(BeEmpty())
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Getting events for space", "my-space", "my-org", "my-user"},
+				[]string{"time", "event", "actee", "actor", "description"},
+				[]string{"audit.app.update", "app my-app", "admin"},
+			))
+		})
+
+		It("lists every event of an org with --all", func() {
+			Expect(runCommand("--org", "my-org", "--all")).To(BeTrue())
+
+			Expect(requirementsFactory.OrganizationName).To(Equal("my-org"))
+			filter, limit := eventsRepo.ListEventsArgsForCall(0)
+			Expect(limit).To(Equal(int64(0)))
+			Expect(filter.OrgGuid).To(Equal("my-org-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Getting events for org", "my-org"}))
+		})
+
+		It("tells the user when no events exist for an org", func() {
+			eventsRepo.ListEventsReturns([]models.EventFields{}, nil)
+			runCommand("--org", "my-org")
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"No events for org", "my-org"}))
+		})
+
+		It("prints the events as json", func() {
+			runCommand("--space", "my-space", "--format", "json")
+
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Getting events"}))
+
+			var output []map[string]string
+			Expect(json.Unmarshal([]byte(strings.Join(ui.Outputs, "\n")), &output)).To(Succeed())
+			Expect(output).To(Equal([]map[string]string{{
+				"guid":        "event-guid",
+				"type":        "audit.app.update",
+				"timestamp":   "2015-06-01T12:00:00Z",
+				"actor":       "user-guid",
+				"actor_name":  "admin",
+				"actee_type":  "app",
+				"actee_name":  "my-app",
+				"description": "instances: 2",
+			}}))
+		})
+
+		It("prints the events as csv", func() {
+			eventsRepo.RecentEventsReturns([]models.EventFields{event}, nil)
+			runCommand("my-app", "--format", "csv")
+
+			Expect(ui.Outputs).To(Equal([]string{
+				"guid,type,timestamp,actor,actor_name,actee_type,actee_name,description",
+				"event-guid,audit.app.update,2015-06-01T12:00:00Z,user-guid,admin,app,my-app,instances: 2",
+			}))
+		})
+	})
 })
@@ -0,0 +1,307 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+//go:generate counterfeiter -o ../../../testhelpers/commands/fake_manifest_differ.go . ManifestDiffer
+type ManifestDiffer interface {
+	command_registry.Command
+	ShowManifestDiff(apps []models.AppParams)
+}
+
+type ManifestDiff struct {
+	ui             terminal.UI
+	config         core_config.Reader
+	manifestRepo   manifest.ManifestRepository
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+}
+
+func init() {
+	command_registry.Register(&ManifestDiff{})
+}
+
+func (cmd *ManifestDiff) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &cliFlags.StringFlag{Name: "f", Usage: T("Path to manifest")}
+
+	return command_registry.CommandMetadata{
+		Name:        "manifest-diff",
+		Description: T("Show how the apps in a manifest differ from the apps deployed in the targeted space"),
+		Usage:       T("CF_NAME manifest-diff [-f MANIFEST_PATH]"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *ManifestDiff) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 0 {
+		cmd.ui.Failed(T("Incorrect Usage. No argument required\n\n") + command_registry.Commands.CommandUsage("manifest-diff"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+	return
+}
+
+func (cmd *ManifestDiff) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.manifestRepo = deps.ManifestRepo
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	return cmd
+}
+
+func (cmd *ManifestDiff) Execute(c flags.FlagContext) {
+	path := c.String("f")
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			cmd.ui.Failed(T("Could not determine the current working directory!"), err)
+		}
+	}
+
+	m, err := cmd.manifestRepo.ReadManifest(path)
+	if err != nil {
+		cmd.ui.Failed(T("Error reading manifest file:\n{{.Err}}", map[string]interface{}{"Err": err.Error()}))
+	}
+
+	apps, err := m.Applications()
+	if err != nil {
+		cmd.ui.Failed("Error reading manifest file:\n%s", err)
+	}
+
+	cmd.ui.Say(T("Using manifest file {{.Path}}\n",
+		map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+
+	cmd.ShowManifestDiff(apps)
+}
+
+// ShowManifestDiff prints the changes a push of apps would make to the
+// targeted space, and fails when there are any.
+func (cmd *ManifestDiff) ShowManifestDiff(apps []models.AppParams) {
+	cmd.ui.Say(T("Comparing apps in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		map[string]interface{}{
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName": terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+	cmd.ui.Say("")
+
+	drift := false
+	for _, appParams := range apps {
+		if appParams.Name == nil {
+			cmd.ui.Failed(T("Error: No name found for app"))
+		}
+
+		lines, err := cmd.diffApp(appParams)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+
+		if len(lines) == 0 {
+			continue
+		}
+
+		drift = true
+		for _, line := range lines {
+			cmd.ui.Say(line)
+		}
+		cmd.ui.Say("")
+	}
+
+	if drift {
+		cmd.ui.Failed(T("Manifest differs from the deployed apps"))
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("No differences found"))
+}
+
+func (cmd *ManifestDiff) diffApp(appParams models.AppParams) ([]string, error) {
+	app, err := cmd.appRepo.Read(*appParams.Name)
+
+	switch err.(type) {
+	case nil:
+		summary, err := cmd.appSummaryRepo.GetSummary(app.Guid)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := appDiff(appParams, &summary).lines()
+		if len(lines) == 0 {
+			return nil, nil
+		}
+
+		header := []string{
+			terminal.DiffRemovedColor("--- " + T("deployed: {{.AppName}}", map[string]interface{}{"AppName": summary.Name})),
+			terminal.DiffAddedColor("+++ " + T("manifest: {{.AppName}}", map[string]interface{}{"AppName": *appParams.Name})),
+		}
+		return append(header, lines...), nil
+	case *errors.ModelNotFoundError:
+		header := []string{
+			terminal.DiffRemovedColor("--- " + T("deployed: {{.AppName}} (not found)", map[string]interface{}{"AppName": *appParams.Name})),
+			terminal.DiffAddedColor("+++ " + T("manifest: {{.AppName}}", map[string]interface{}{"AppName": *appParams.Name})),
+		}
+		return append(header, appDiff(appParams, nil).lines()...), nil
+	default:
+		return nil, err
+	}
+}
+
+type diffLines []string
+
+func (diff *diffLines) removed(key string, value interface{}) {
+	*diff = append(*diff, terminal.DiffRemovedColor(fmt.Sprintf("- %s: %v", key, value)))
+}
+
+func (diff *diffLines) added(key string, value interface{}) {
+	*diff = append(*diff, terminal.DiffAddedColor(fmt.Sprintf("+ %s: %v", key, value)))
+}
+
+func (diff *diffLines) changed(key string, live interface{}, desired interface{}, deployed bool) {
+	if deployed {
+		diff.removed(key, live)
+	}
+	diff.added(key, desired)
+}
+
+func (diff diffLines) lines() []string {
+	return []string(diff)
+}
+
+// appDiff compares the settings a push would apply to an app with the
+// deployed app. A nil app means the app has not been pushed yet. Settings that
+// a push leaves alone, such as env vars and services missing from the
+// manifest, are not reported.
+func appDiff(appParams models.AppParams, app *models.Application) (diff diffLines) {
+	deployed := app != nil
+	if !deployed {
+		app = &models.Application{}
+	}
+
+	if appParams.Memory != nil && (!deployed || *appParams.Memory != app.Memory) {
+		diff.changed("memory", formatters.ByteSize(app.Memory*formatters.MEGABYTE), formatters.ByteSize(*appParams.Memory*formatters.MEGABYTE), deployed)
+	}
+
+	if appParams.DiskQuota != nil && (!deployed || *appParams.DiskQuota != app.DiskQuota) {
+		diff.changed("disk_quota", formatters.ByteSize(app.DiskQuota*formatters.MEGABYTE), formatters.ByteSize(*appParams.DiskQuota*formatters.MEGABYTE), deployed)
+	}
+
+	if appParams.InstanceCount != nil && (!deployed || *appParams.InstanceCount != app.InstanceCount) {
+		diff.changed("instances", app.InstanceCount, *appParams.InstanceCount, deployed)
+	}
+
+	if appParams.BuildpackUrl != nil && (!deployed || *appParams.BuildpackUrl != app.BuildpackUrl) {
+		diff.changed("buildpack", app.BuildpackUrl, *appParams.BuildpackUrl, deployed)
+	}
+
+	if appParams.Command != nil && (!deployed || *appParams.Command != app.Command) {
+		diff.changed("command", app.Command, *appParams.Command, deployed)
+	}
+
+	if appParams.EnvironmentVars != nil {
+		env := *appParams.EnvironmentVars
+		keys := []string{}
+		for key := range env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			liveValue, found := app.EnvironmentVars[key]
+			if found && fmt.Sprint(liveValue) == fmt.Sprint(env[key]) {
+				continue
+			}
+			diff.changed("env."+key, liveValue, env[key], found)
+		}
+	}
+
+	liveRoutes := map[string]bool{}
+	for _, route := range app.Routes {
+		liveRoutes[route.URL()] = true
+	}
+
+	if appParams.NoRoute {
+		for _, route := range app.Routes {
+			diff.removed("route", route.URL())
+		}
+	} else {
+		for _, route := range desiredRoutes(appParams, app.Routes) {
+			if !liveRoutes[route] {
+				diff.added("route", route)
+			}
+		}
+	}
+
+	if appParams.ServicesToBind != nil {
+		liveServices := map[string]bool{}
+		for _, service := range app.Services {
+			liveServices[service.Name] = true
+		}
+
+		for _, service := range *appParams.ServicesToBind {
+			if !liveServices[service] {
+				diff.added("service", service)
+			}
+		}
+	}
+
+	return
+}
+
+// desiredRoutes lists the routes named by the hosts and domains of an app.
+// When the manifest names hosts but no domains, the domains of the deployed
+// routes are used.
+func desiredRoutes(appParams models.AppParams, liveRoutes []models.RouteSummary) []string {
+	if appParams.UseRandomHostname || (appParams.Hosts == nil && appParams.Domains == nil && !appParams.NoHostname) {
+		return nil
+	}
+
+	hosts := []string{*appParams.Name}
+	if appParams.NoHostname {
+		hosts = []string{""}
+	} else if appParams.Hosts != nil {
+		hosts = *appParams.Hosts
+	}
+
+	domains := []string{}
+	if appParams.Domains != nil {
+		domains = *appParams.Domains
+	} else {
+		seen := map[string]bool{}
+		for _, route := range liveRoutes {
+			if !seen[route.Domain.Name] {
+				seen[route.Domain.Name] = true
+				domains = append(domains, route.Domain.Name)
+			}
+		}
+	}
+
+	routes := []string{}
+	for _, domain := range domains {
+		for _, host := range hosts {
+			routes = append(routes, models.RouteSummary{Host: host, Domain: models.DomainFields{Name: domain}}.URL())
+		}
+	}
+	return routes
+}
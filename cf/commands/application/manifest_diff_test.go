@@ -0,0 +1,222 @@
+package application_test
+
+import (
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/generic"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testmanifest "github.com/cloudfoundry/cli/testhelpers/manifest"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("manifest-diff command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		manifestRepo        *testmanifest.FakeManifestRepository
+		appRepo             *testApplication.FakeApplicationRepository
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		requirementsFactory *testreq.FakeReqFactory
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.ManifestRepo = manifestRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("manifest-diff").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		manifestRepo = &testmanifest.FakeManifestRepository{}
+		appRepo = &testApplication.FakeApplicationRepository{}
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Path: "manifest.yml",
+			Data: generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":      "my-app",
+						"memory":    "256M",
+						"instances": 2,
+						"command":   "run-it",
+						"host":      "my-host",
+						"services":  []interface{}{"my-db", "my-queue"},
+						"env": map[interface{}]interface{}{
+							"SAME":    "value",
+							"CHANGED": "new-value",
+							"ADDED":   "added-value",
+						},
+					},
+				},
+			}),
+		}
+
+		appRepo.ReadReturns.App = models.Application{
+			ApplicationFields: models.ApplicationFields{Name: "my-app", Guid: "my-app-guid"},
+		}
+
+		summary := models.Application{}
+		summary.Name = "my-app"
+		summary.Guid = "my-app-guid"
+		summary.Memory = 128
+		summary.InstanceCount = 2
+		summary.Command = "run-it"
+		summary.EnvironmentVars = map[string]interface{}{
+			"SAME":    "value",
+			"CHANGED": "old-value",
+			"LIVE":    "live-only",
+		}
+		summary.Routes = []models.RouteSummary{
+			{Host: "old-host", Domain: models.DomainFields{Name: "example.com"}},
+		}
+		summary.Services = []models.ServicePlanSummary{{Name: "my-db"}}
+		appSummaryRepo.GetSummarySummary = summary
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("manifest-diff", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails if not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails if a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails with usage when given an argument", func() {
+			runCommand("my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "No argument required"},
+			))
+		})
+	})
+
+	It("reads the manifest at the given path", func() {
+		runCommand("-f", "path/to/manifest.yml")
+		Expect(manifestRepo.ReadManifestArgs.Path).To(Equal("path/to/manifest.yml"))
+	})
+
+	It("fails when the manifest cannot be read", func() {
+		manifestRepo.ReadManifestReturns.Error = errors.New("read manifest error")
+
+		runCommand("-f", "bad/manifest/path")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"read manifest error"},
+		))
+	})
+
+	It("shows the changes a push would make and fails", func() {
+		runCommand()
+
+		Expect(appRepo.ReadArgs.Name).To(Equal("my-app"))
+		Expect(appSummaryRepo.GetSummaryAppGuid).To(Equal("my-app-guid"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"--- deployed: my-app"},
+			[]string{"+++ manifest: my-app"},
+			[]string{"- memory: 128M"},
+			[]string{"+ memory: 256M"},
+			[]string{"+ env.ADDED: added-value"},
+			[]string{"- env.CHANGED: old-value"},
+			[]string{"+ env.CHANGED: new-value"},
+			[]string{"+ route: my-host.example.com"},
+			[]string{"+ service: my-queue"},
+			[]string{"FAILED"},
+			[]string{"Manifest differs from the deployed apps"},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings(
+			[]string{"instances"},
+			[]string{"command"},
+			[]string{"SAME"},
+			[]string{"LIVE"},
+			[]string{"service: my-db"},
+		))
+	})
+
+	It("shows every setting of an app that has not been pushed", func() {
+		appRepo.ReadReturns.Error = errors.NewModelNotFoundError("App", "my-app")
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"--- deployed: my-app (not found)"},
+			[]string{"+ memory: 256M"},
+			[]string{"+ instances: 2"},
+			[]string{"+ command: run-it"},
+			[]string{"+ service: my-db"},
+			[]string{"FAILED"},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"- memory"}))
+	})
+
+	It("succeeds when the deployed apps match the manifest", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Path: "manifest.yml",
+			Data: generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":      "my-app",
+						"memory":    "128M",
+						"instances": 2,
+						"host":      "old-host",
+						"services":  []interface{}{"my-db"},
+					},
+				},
+			}),
+		}
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"OK"},
+			[]string{"No differences found"},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+	})
+
+	It("shows the routes that a push with no-route would remove", func() {
+		manifestRepo.ReadManifestReturns.Manifest = &manifest.Manifest{
+			Path: "manifest.yml",
+			Data: generic.NewMap(map[interface{}]interface{}{
+				"applications": []interface{}{
+					map[interface{}]interface{}{
+						"name":     "my-app",
+						"no-route": true,
+					},
+				},
+			}),
+		}
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"- route: old-host.example.com"},
+			[]string{"FAILED"},
+		))
+	})
+})
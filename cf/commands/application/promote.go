@@ -0,0 +1,646 @@
+package application
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
+	"github.com/cloudfoundry/cli/cf/api/copy_application_source"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/net"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+// PromoteTargetLoader returns the config and repositories of the foundation
+// that the CLI logged in to with CF_HOME set to cfHome.
+type PromoteTargetLoader func(cfHome string) (core_config.Reader, api.RepositoryLocator, error)
+
+type Promote struct {
+	ui                terminal.UI
+	config            core_config.Reader
+	repoLocator       api.RepositoryLocator
+	appReq            requirements.ApplicationRequirement
+	appSummaryRepo    api.AppSummaryRepository
+	stackRepo         stacks.StackRepository
+	dropletRepo       application_bits.ApplicationDropletRepository
+	copyAppSourceRepo copy_application_source.CopyApplicationSourceRepository
+	loadTarget        PromoteTargetLoader
+}
+
+// promoteDestination is the space an app is promoted to, together with the
+// repositories of the foundation the space belongs to.
+type promoteDestination struct {
+	config         core_config.Reader
+	repoLocator    api.RepositoryLocator
+	sameFoundation bool
+	org            models.OrganizationFields
+	space          models.SpaceFields
+}
+
+// promotion holds the changes promoting an app makes to its destination. The
+// app is nil when it does not exist in the destination yet.
+type promotion struct {
+	params   models.AppParams
+	app      *models.Application
+	services []models.ServiceInstance
+	routes   []models.Route
+	diff     diffLines
+}
+
+func init() {
+	command_registry.Register(&Promote{})
+}
+
+func (cmd *Promote) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["to-space"] = &cliFlags.StringFlag{Name: "to-space", Usage: T("Space to promote the app to")}
+	fs["to-org"] = &cliFlags.StringFlag{Name: "to-org", Usage: T("Org to promote the app to (default: the targeted org of the destination)")}
+	fs["to-target"] = &cliFlags.StringFlag{Name: "to-target", Usage: T("CF_HOME directory of a CLI logged in to the foundation to promote the app to")}
+	fs["droplet"] = &cliFlags.BoolFlag{Name: "droplet", Usage: T("Copy the staged droplet instead of the app files, so the app starts without staging")}
+	fs["map-domain"] = &cliFlags.StringSliceFlag{Name: "map-domain", Usage: T("Bind routes of SOURCE domain to DEST domain, as SOURCE:DEST. This flag can be defined more than once.")}
+	fs["map-host"] = &cliFlags.StringSliceFlag{Name: "map-host", Usage: T("Bind routes of SOURCE host to DEST host, as SOURCE:DEST. This flag can be defined more than once.")}
+	fs["no-start"] = &cliFlags.BoolFlag{Name: "no-start", Usage: T("Do not start the promoted app")}
+	fs["dry-run"] = &cliFlags.BoolFlag{Name: "dry-run", Usage: T("Show the changes without promoting the app")}
+	fs["f"] = &cliFlags.BoolFlag{Name: "f", Usage: T("Force promotion without confirmation")}
+
+	baseUsage := T("CF_NAME promote APP_NAME --to-space SPACE [--to-org ORG] [--to-target CF_HOME] [--droplet] [--map-domain SOURCE:DEST] [--map-host SOURCE:DEST] [--no-start] [--dry-run] [-f]")
+	targetUsage := T(`Promote the app to another foundation by logging in to it with CF_HOME set to another directory,
+   and passing that directory to --to-target.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME promote my-app --to-space production --map-host my-app-dev:my-app
+
+   CF_HOME=~/prod CF_NAME login -a https://api.prod.example.com
+   CF_NAME promote my-app --to-space production --to-target ~/prod --map-domain dev.example.com:example.com`)
+
+	return command_registry.CommandMetadata{
+		Name:        "promote",
+		Description: T("Copy an app with its settings, service bindings and routes to another space or foundation"),
+		Usage:       strings.Join([]string{baseUsage, targetUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *Promote) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("promote"))
+	}
+
+	if fc.String("to-space") == "" {
+		cmd.ui.Failed(T("Incorrect Usage. --to-space is required\n\n") + command_registry.Commands.CommandUsage("promote"))
+	}
+
+	cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[0])
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.appReq,
+	}
+	return
+}
+
+func (cmd *Promote) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.repoLocator = deps.RepoLocator
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	cmd.copyAppSourceRepo = deps.RepoLocator.GetCopyApplicationSourceRepository()
+
+	cmd.loadTarget = newPromoteTargetLoader(deps.Ui)
+	if loader, ok := deps.WilecardDependency.(PromoteTargetLoader); ok {
+		cmd.loadTarget = loader
+	}
+
+	return cmd
+}
+
+func (cmd *Promote) Execute(c flags.FlagContext) {
+	domainMap, err := parsePromoteMappings("map-domain", c.StringSlice("map-domain"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	hostMap, err := parsePromoteMappings("map-host", c.StringSlice("map-host"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	source, err := cmd.appSummaryRepo.GetSummary(cmd.appReq.GetApplication().Guid)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	dest := cmd.findDestination(c)
+	if dest.sameFoundation && dest.space.Guid == source.SpaceGuid {
+		cmd.ui.Failed(T("App {{.AppName}} is already in space {{.SpaceName}}",
+			map[string]interface{}{
+				"AppName":   terminal.EntityNameColor(source.Name),
+				"SpaceName": terminal.EntityNameColor(dest.space.Name),
+			}))
+	}
+
+	cmd.ui.Say(T("Promoting app {{.AppName}} to org {{.OrgName}} / space {{.SpaceName}} at {{.ApiEndpoint}} as {{.Username}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(source.Name),
+			"OrgName":     terminal.EntityNameColor(dest.org.Name),
+			"SpaceName":   terminal.EntityNameColor(dest.space.Name),
+			"ApiEndpoint": terminal.EntityNameColor(dest.config.ApiEndpoint()),
+			"Username":    terminal.EntityNameColor(dest.config.Username()),
+		}))
+	cmd.ui.Say("")
+
+	plan, err := cmd.planPromotion(source, dest, domainMap, hostMap, c.Bool("droplet"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	for _, line := range plan.diff.lines() {
+		cmd.ui.Say(line)
+	}
+	cmd.ui.Say("")
+
+	if c.Bool("dry-run") {
+		cmd.ui.Say(T("Dry run, no changes were made"))
+		return
+	}
+
+	if !c.Bool("f") && !cmd.ui.Confirm(T("Really promote app {{.AppName}}?", map[string]interface{}{"AppName": source.Name})) {
+		return
+	}
+
+	cmd.promote(source, dest, plan, c.Bool("droplet"), c.Bool("no-start"))
+}
+
+func (cmd *Promote) findDestination(c flags.FlagContext) (dest promoteDestination) {
+	dest.config = cmd.config
+	dest.repoLocator = cmd.repoLocator
+	dest.sameFoundation = true
+
+	if cfHome := c.String("to-target"); cfHome != "" {
+		var err error
+		dest.config, dest.repoLocator, err = cmd.loadTarget(cfHome)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+
+		if !dest.config.IsLoggedIn() {
+			cmd.ui.Failed(T("Not logged in to the target in {{.CFHome}}. Use '{{.Command}}' to log in.",
+				map[string]interface{}{
+					"CFHome":  cfHome,
+					"Command": terminal.CommandColor(fmt.Sprintf("CF_HOME=%s %s login", cfHome, cf.Name())),
+				}))
+		}
+
+		dest.sameFoundation = dest.config.ApiEndpoint() == cmd.config.ApiEndpoint()
+	}
+
+	if orgName := c.String("to-org"); orgName != "" {
+		org, err := dest.repoLocator.GetOrganizationRepository().FindByName(orgName)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		dest.org = org.OrganizationFields
+	} else {
+		dest.org = dest.config.OrganizationFields()
+		if dest.org.Guid == "" {
+			cmd.ui.Failed(T("No org targeted in the destination. Use --to-org to name one."))
+		}
+	}
+
+	space, err := dest.repoLocator.GetSpaceRepository().FindByNameInOrg(c.String("to-space"), dest.org.Guid)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	dest.space = space.SpaceFields
+
+	return
+}
+
+func (cmd *Promote) planPromotion(source models.Application, dest promoteDestination, domainMap, hostMap map[string]string, useDroplet bool) (plan promotion, err error) {
+	app, err := dest.repoLocator.GetApplicationRepository().ReadFromSpace(source.Name, dest.space.Guid)
+	switch err.(type) {
+	case nil:
+		var summary models.Application
+		summary, err = dest.repoLocator.GetAppSummaryRepository().GetSummary(app.Guid)
+		if err != nil {
+			return
+		}
+		plan.app = &summary
+	case *errors.ModelNotFoundError:
+		err = nil
+	default:
+		return
+	}
+
+	deployed := plan.app != nil
+	current := plan.app
+	if !deployed {
+		current = &models.Application{}
+	}
+
+	plan.params, err = cmd.promotedParams(source, dest)
+	if err != nil {
+		return
+	}
+
+	plan.diff = appDiff(plan.params, plan.app)
+
+	envKeys := []string{}
+	for key := range current.EnvironmentVars {
+		if _, found := source.EnvironmentVars[key]; !found {
+			envKeys = append(envKeys, key)
+		}
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		plan.diff.removed("env."+key, current.EnvironmentVars[key])
+	}
+
+	if source.HealthCheckType != "" && source.HealthCheckType != current.HealthCheckType {
+		plan.diff.changed("health_check_type", current.HealthCheckType, source.HealthCheckType, deployed)
+	}
+
+	if source.HealthCheckTimeout != 0 && source.HealthCheckTimeout != current.HealthCheckTimeout {
+		plan.diff.changed("health_check_timeout", current.HealthCheckTimeout, source.HealthCheckTimeout, deployed)
+	}
+
+	if source.DockerImage != "" {
+		if source.DockerImage != current.DockerImage {
+			plan.diff.changed("docker_image", current.DockerImage, source.DockerImage, deployed)
+		}
+	} else if useDroplet {
+		plan.diff.added("droplet", source.Name)
+	} else {
+		plan.diff.added("app files", source.Name)
+	}
+
+	plan.services, err = cmd.planServices(*plan.params.ServicesToBind, current, dest)
+	if err != nil {
+		return
+	}
+
+	plan.routes, err = cmd.planRoutes(source, current, dest, domainMap, hostMap)
+	if err != nil {
+		return
+	}
+
+	for _, route := range plan.routes {
+		plan.diff.added("route", route.URL())
+	}
+
+	return
+}
+
+func (cmd *Promote) promotedParams(source models.Application, dest promoteDestination) (params models.AppParams, err error) {
+	env := source.EnvironmentVars
+	if env == nil {
+		env = map[string]interface{}{}
+	}
+
+	services := []string{}
+	for _, service := range source.Services {
+		services = append(services, service.Name)
+	}
+
+	params = models.AppParams{
+		BuildpackUrl:    &source.BuildpackUrl,
+		Command:         &source.Command,
+		DiskQuota:       &source.DiskQuota,
+		EnvironmentVars: &env,
+		InstanceCount:   &source.InstanceCount,
+		Memory:          &source.Memory,
+		ServicesToBind:  &services,
+	}
+
+	if source.HealthCheckType != "" {
+		params.HealthCheckType = &source.HealthCheckType
+	}
+
+	if source.HealthCheckTimeout != 0 {
+		params.HealthCheckTimeout = &source.HealthCheckTimeout
+	}
+
+	if source.DockerImage != "" {
+		params.DockerImage = &source.DockerImage
+	}
+
+	if source.Diego {
+		params.Diego = &source.Diego
+	}
+
+	if source.Stack != nil {
+		var stackGuid string
+		stackGuid, err = cmd.destinationStackGuid(source.Stack.Guid, dest)
+		if err != nil {
+			return
+		}
+		if stackGuid != "" {
+			params.StackGuid = &stackGuid
+		}
+	}
+
+	return
+}
+
+// destinationStackGuid finds the stack of the same name in the destination
+// foundation. Stacks the destination does not know are left to its default.
+func (cmd *Promote) destinationStackGuid(stackGuid string, dest promoteDestination) (string, error) {
+	if dest.sameFoundation {
+		return stackGuid, nil
+	}
+
+	sourceStacks, err := cmd.stackRepo.FindAll()
+	if err != nil {
+		return "", err
+	}
+
+	for _, stack := range sourceStacks {
+		if stack.Guid != stackGuid {
+			continue
+		}
+
+		destStack, err := dest.repoLocator.GetStackRepository().FindByName(stack.Name)
+		switch err.(type) {
+		case nil:
+			return destStack.Guid, nil
+		case *errors.ModelNotFoundError:
+			return "", nil
+		default:
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func (cmd *Promote) planServices(names []string, current *models.Application, dest promoteDestination) (instances []models.ServiceInstance, err error) {
+	bound := map[string]bool{}
+	for _, service := range current.Services {
+		bound[service.Name] = true
+	}
+
+	serviceRepo := dest.repoLocator.GetServiceRepository()
+	for _, name := range names {
+		if bound[name] {
+			continue
+		}
+
+		var instance models.ServiceInstance
+		instance, err = serviceRepo.FindInstanceByNameInSpace(name, dest.space.Guid)
+		if _, ok := err.(*errors.ModelNotFoundError); ok {
+			err = errors.New(T("Service instance {{.ServiceName}} not found in space {{.SpaceName}}. Create it before promoting the app.",
+				map[string]interface{}{"ServiceName": name, "SpaceName": dest.space.Name}))
+		}
+		if err != nil {
+			return
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return
+}
+
+// planRoutes finds the routes of the destination that the routes of the
+// source app map to. Routes that do not exist yet are returned without a
+// guid.
+func (cmd *Promote) planRoutes(source models.Application, current *models.Application, dest promoteDestination, domainMap, hostMap map[string]string) (routes []models.Route, err error) {
+	bound := map[string]bool{}
+	for _, route := range current.Routes {
+		bound[route.URL()] = true
+	}
+
+	domainRepo := dest.repoLocator.GetDomainRepository()
+	routeRepo := dest.repoLocator.GetRouteRepository()
+
+	for _, sourceRoute := range source.Routes {
+		host := promoteMapping(hostMap, sourceRoute.Host)
+		domainName := promoteMapping(domainMap, sourceRoute.Domain.Name)
+
+		url := models.RouteSummary{Host: host, Domain: models.DomainFields{Name: domainName}}.URL()
+		if bound[url] {
+			continue
+		}
+
+		var domain models.DomainFields
+		domain, err = domainRepo.FindByNameInOrg(domainName, dest.org.Guid)
+		if err != nil {
+			return
+		}
+
+		var route models.Route
+		route, err = routeRepo.FindByHostAndDomain(host, domain)
+		switch err.(type) {
+		case nil:
+			if route.Space.Guid != dest.space.Guid {
+				cmd.ui.Warn(T("Skipping route {{.URL}} because it belongs to another space. Use --map-host or --map-domain to promote it to another route.",
+					map[string]interface{}{"URL": url}))
+				continue
+			}
+		case *errors.ModelNotFoundError:
+			err = nil
+			route = models.Route{Host: host, Domain: domain}
+		default:
+			return
+		}
+
+		routes = append(routes, route)
+	}
+
+	return
+}
+
+func (cmd *Promote) promote(source models.Application, dest promoteDestination, plan promotion, useDroplet bool, noStart bool) {
+	appRepo := dest.repoLocator.GetApplicationRepository()
+
+	var app models.Application
+	var err error
+	if plan.app == nil {
+		cmd.ui.Say(T("Creating app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(source.Name)}))
+
+		params := plan.params
+		params.Name = &source.Name
+		params.SpaceGuid = &dest.space.Guid
+		app, err = appRepo.Create(params)
+	} else {
+		cmd.ui.Say(T("Updating app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(source.Name)}))
+		app, err = appRepo.Update(plan.app.Guid, plan.params)
+	}
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	if source.DockerImage == "" {
+		if useDroplet {
+			cmd.ui.Say(T("Copying droplet..."))
+		} else {
+			cmd.ui.Say(T("Copying app files..."))
+		}
+
+		err = cmd.copyBits(source.Guid, app.Guid, dest, useDroplet)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	bindingRepo := dest.repoLocator.GetServiceBindingRepository()
+	for _, instance := range plan.services {
+		cmd.ui.Say(T("Binding service {{.ServiceName}} to app {{.AppName}}...",
+			map[string]interface{}{
+				"ServiceName": terminal.EntityNameColor(instance.Name),
+				"AppName":     terminal.EntityNameColor(app.Name),
+			}))
+
+		err = bindingRepo.Create(instance.Guid, app.Guid, nil)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	routeRepo := dest.repoLocator.GetRouteRepository()
+	for _, route := range plan.routes {
+		url := route.URL()
+		if route.Guid == "" {
+			cmd.ui.Say(T("Creating route {{.URL}}...", map[string]interface{}{"URL": terminal.EntityNameColor(url)}))
+
+			route, err = routeRepo.CreateInSpace(route.Host, route.Domain.Guid, dest.space.Guid)
+			if err != nil {
+				cmd.ui.Failed(err.Error())
+			}
+		}
+
+		cmd.ui.Say(T("Binding {{.URL}} to {{.AppName}}...",
+			map[string]interface{}{
+				"URL":     terminal.EntityNameColor(url),
+				"AppName": terminal.EntityNameColor(app.Name),
+			}))
+
+		err = routeRepo.Bind(route.Guid, app.Guid)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	if !noStart && source.State == "started" {
+		if plan.app != nil && plan.app.State == "started" {
+			cmd.ui.Say(T("Stopping app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+			state := "STOPPED"
+			_, err = appRepo.Update(app.Guid, models.AppParams{State: &state})
+			if err != nil {
+				cmd.ui.Failed(err.Error())
+			}
+		}
+
+		cmd.ui.Say(T("Starting app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+		state := "STARTED"
+		_, err = appRepo.Update(app.Guid, models.AppParams{State: &state})
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	cmd.ui.Ok()
+}
+
+// copyBits copies the package or droplet of an app. Packages are copied by
+// the cloud controller when possible, otherwise they are downloaded and
+// uploaded again.
+func (cmd *Promote) copyBits(sourceGuid, destGuid string, dest promoteDestination, useDroplet bool) error {
+	if dest.sameFoundation && !useDroplet {
+		return cmd.copyAppSourceRepo.CopyApplication(sourceGuid, destGuid)
+	}
+
+	file, err := ioutil.TempFile("", "promote")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if useDroplet {
+		_, err = cmd.dropletRepo.DownloadDroplet(sourceGuid, file)
+		if err != nil {
+			return err
+		}
+		return dest.repoLocator.GetApplicationDropletRepository().UploadDroplet(destGuid, file)
+	}
+
+	_, err = cmd.dropletRepo.DownloadBits(sourceGuid, file)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+
+	return dest.repoLocator.GetApplicationBitsRepository().UploadBits(destGuid, file, nil)
+}
+
+func parsePromoteMappings(flagName string, values []string) (map[string]string, error) {
+	mappings := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New(T("Invalid --{{.Flag}} '{{.Value}}', expected SOURCE:DEST",
+				map[string]interface{}{"Flag": flagName, "Value": value}))
+		}
+		mappings[parts[0]] = parts[1]
+	}
+	return mappings, nil
+}
+
+func promoteMapping(mappings map[string]string, name string) string {
+	if mapped, found := mappings[name]; found {
+		return mapped
+	}
+	return name
+}
+
+func newPromoteTargetLoader(ui terminal.UI) PromoteTargetLoader {
+	return func(cfHome string) (core_config.Reader, api.RepositoryLocator, error) {
+		path := filepath.Join(cfHome, ".cf", "config.json")
+		if _, err := os.Stat(path); err != nil {
+			return nil, api.RepositoryLocator{}, errors.New(T("No CLI configuration found in {{.CFHome}}", map[string]interface{}{"CFHome": cfHome}))
+		}
+
+		var configErr error
+		config := core_config.NewRepositoryFromFilepath(path, func(err error) {
+			if err != nil {
+				configErr = err
+			}
+		})
+
+		gateways := map[string]net.Gateway{
+			"auth":             net.NewUAAGateway(config, ui),
+			"cloud-controller": net.NewCloudControllerGateway(config, time.Now, ui),
+			"uaa":              net.NewUAAGateway(config, ui),
+		}
+		repoLocator := api.NewRepositoryLocator(config, gateways)
+
+		if configErr != nil {
+			return nil, api.RepositoryLocator{}, configErr
+		}
+		return config, repoLocator, nil
+	}
+}
@@ -0,0 +1,462 @@
+package application_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	testbits "github.com/cloudfoundry/cli/cf/api/application_bits/fakes"
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testCopyApplication "github.com/cloudfoundry/cli/cf/api/copy_application_source/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/api/resources"
+	teststacks "github.com/cloudfoundry/cli/cf/api/stacks/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	. "github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("promote command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		appRepo             *testApplication.FakeApplicationRepository
+		spaceRepo           *testapi.FakeSpaceRepository
+		serviceRepo         *testapi.FakeServiceRepo
+		bindingRepo         *testapi.FakeServiceBindingRepo
+		domainRepo          *testapi.FakeDomainRepository
+		routeRepo           *testapi.FakeRouteRepository
+		copyAppSourceRepo   *testCopyApplication.FakeCopyApplicationSourceRepository
+		dropletRepo         *testbits.FakeApplicationDropletRepository
+		stackRepo           *teststacks.FakeStackRepository
+		targetLoader        PromoteTargetLoader
+		deps                command_registry.Dependency
+		source              models.Application
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceBindingRepository(bindingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
+		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+		deps.RepoLocator = deps.RepoLocator.SetCopyApplicationSourceRepository(copyAppSourceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationDropletRepository(dropletRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
+		deps.WilecardDependency = targetLoader
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("promote").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		appRepo = &testApplication.FakeApplicationRepository{}
+		spaceRepo = &testapi.FakeSpaceRepository{}
+		serviceRepo = &testapi.FakeServiceRepo{}
+		bindingRepo = &testapi.FakeServiceBindingRepo{}
+		domainRepo = &testapi.FakeDomainRepository{}
+		routeRepo = &testapi.FakeRouteRepository{}
+		copyAppSourceRepo = &testCopyApplication.FakeCopyApplicationSourceRepository{}
+		dropletRepo = &testbits.FakeApplicationDropletRepository{}
+		stackRepo = &teststacks.FakeStackRepository{}
+		targetLoader = nil
+
+		source = models.Application{}
+		source.Name = "my-app"
+		source.Guid = "my-app-guid"
+		source.SpaceGuid = "my-space-guid"
+		source.State = "started"
+		source.InstanceCount = 3
+		source.Memory = 512
+		source.DiskQuota = 1024
+		source.Command = "run-it"
+		source.HealthCheckType = "none"
+		source.EnvironmentVars = map[string]interface{}{"LOG_LEVEL": "debug"}
+		source.Stack = &models.Stack{Guid: "stack-guid"}
+		source.Services = []models.ServicePlanSummary{{Name: "my-db", Guid: "dev-db-guid"}}
+		source.Routes = []models.RouteSummary{{Host: "my-app-dev", Domain: models.DomainFields{Name: "example.com"}}}
+		appSummaryRepo.GetSummarySummary = source
+
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: source}
+
+		prodSpace := models.Space{}
+		prodSpace.Name = "production"
+		prodSpace.Guid = "prod-space-guid"
+		spaceRepo.FindByNameInOrgSpace = prodSpace
+
+		appRepo.ReadFromSpaceReturns(models.Application{}, errors.NewModelNotFoundError("App", "my-app"))
+
+		prodDb := models.ServiceInstance{}
+		prodDb.Name = "my-db"
+		prodDb.Guid = "prod-db-guid"
+		serviceRepo.FindInstanceByNameServiceInstance = prodDb
+
+		domainRepo.FindByNameInOrgDomain = []models.DomainFields{{Name: "example.com", Guid: "domain-guid"}}
+		routeRepo.FindByHostAndDomainReturns.Error = errors.NewModelNotFoundError("Route", "my-app")
+		routeRepo.CreateInSpaceCreatedRoute = models.Route{Guid: "new-route-guid"}
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("promote", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("--to-space", "production", "my-app")).To(BeFalse())
+		})
+
+		It("fails with usage when not provided an app name", func() {
+			runCommand("--to-space", "production")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+
+		It("fails with usage when not provided a destination space", func() {
+			runCommand("my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--to-space is required"},
+			))
+		})
+	})
+
+	Context("when promoting to another space of the targeted foundation", func() {
+		It("creates the app with the settings of the source app", func() {
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(spaceRepo.FindByNameInOrgName).To(Equal("production"))
+			Expect(spaceRepo.FindByNameInOrgOrgGuid).To(Equal("my-org-guid"))
+
+			name, spaceGuid := appRepo.ReadFromSpaceArgsForCall(0)
+			Expect(name).To(Equal("my-app"))
+			Expect(spaceGuid).To(Equal("prod-space-guid"))
+
+			params := appRepo.CreatedAppParams()
+			Expect(*params.Name).To(Equal("my-app"))
+			Expect(*params.SpaceGuid).To(Equal("prod-space-guid"))
+			Expect(*params.InstanceCount).To(Equal(3))
+			Expect(*params.Memory).To(Equal(int64(512)))
+			Expect(*params.DiskQuota).To(Equal(int64(1024)))
+			Expect(*params.Command).To(Equal("run-it"))
+			Expect(*params.HealthCheckType).To(Equal("none"))
+			Expect(*params.StackGuid).To(Equal("stack-guid"))
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{"LOG_LEVEL": "debug"}))
+		})
+
+		It("copies the app files with the cloud controller", func() {
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(copyAppSourceRepo.CopyApplicationCallCount()).To(Equal(1))
+			sourceGuid, destGuid := copyAppSourceRepo.CopyApplicationArgsForCall(0)
+			Expect(sourceGuid).To(Equal("my-app-guid"))
+			Expect(destGuid).To(Equal("my-app-guid"))
+			Expect(dropletRepo.DownloadBitsCallCount()).To(Equal(0))
+		})
+
+		It("binds the service instances of the same name in the destination space", func() {
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(serviceRepo.FindInstanceByNameName).To(Equal("my-db"))
+			Expect(serviceRepo.FindInstanceByNameInSpaceSpaceGuid).To(Equal("prod-space-guid"))
+			Expect(bindingRepo.CreateServiceInstanceGuid).To(Equal("prod-db-guid"))
+			Expect(bindingRepo.CreateApplicationGuid).To(Equal("my-app-guid"))
+		})
+
+		It("creates and binds the mapped routes", func() {
+			runCommand("-f", "--to-space", "production", "--map-host", "my-app-dev:my-app", "my-app")
+
+			Expect(routeRepo.CreateInSpaceHost).To(Equal("my-app"))
+			Expect(routeRepo.CreateInSpaceDomainGuid).To(Equal("domain-guid"))
+			Expect(routeRepo.CreateInSpaceSpaceGuid).To(Equal("prod-space-guid"))
+			Expect(routeRepo.BoundRouteGuid).To(Equal("new-route-guid"))
+			Expect(routeRepo.BoundAppGuid).To(Equal("my-app-guid"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Promoting app", "my-app", "my-org", "production", "my-user"},
+				[]string{"+ memory: 512M"},
+				[]string{"+ instances: 3"},
+				[]string{"+ env.LOG_LEVEL: debug"},
+				[]string{"+ service: my-db"},
+				[]string{"+ route: my-app.example.com"},
+				[]string{"Creating app", "my-app"},
+				[]string{"Binding service", "my-db"},
+				[]string{"Creating route", "my-app.example.com"},
+				[]string{"Starting app", "my-app"},
+				[]string{"OK"},
+			))
+		})
+
+		It("starts the promoted app when the source app is started", func() {
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(appRepo.UpdateAppGuid).To(Equal("my-app-guid"))
+			Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+		})
+
+		It("does not start the promoted app with --no-start", func() {
+			runCommand("-f", "--no-start", "--to-space", "production", "my-app")
+
+			Expect(appRepo.UpdateCalls).To(Equal(0))
+		})
+
+		It("skips routes that belong to another space", func() {
+			routeRepo.FindByHostAndDomainReturns.Error = nil
+			routeRepo.FindByHostAndDomainReturns.Route = models.Route{Guid: "dev-route-guid", Space: models.SpaceFields{Guid: "my-space-guid"}}
+
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(routeRepo.BoundRouteGuid).To(BeEmpty())
+			Expect(ui.WarnOutputs).To(ContainSubstrings(
+				[]string{"Skipping route my-app-dev.example.com", "another space"},
+			))
+		})
+
+		It("fails before changing anything when a service instance is missing", func() {
+			serviceRepo.FindInstanceByNameNotFound = true
+
+			runCommand("-f", "--to-space", "production", "my-app")
+
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Service instance my-db not found in space production"},
+			))
+		})
+
+		It("fails when the destination is the space of the app", func() {
+			space := models.Space{}
+			space.Name = "my-space"
+			space.Guid = "my-space-guid"
+			spaceRepo.FindByNameInOrgSpace = space
+
+			runCommand("-f", "--to-space", "my-space", "my-app")
+
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"already in space", "my-space"},
+			))
+		})
+
+		It("only shows the changes with --dry-run", func() {
+			runCommand("--dry-run", "--to-space", "production", "my-app")
+
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+			Expect(copyAppSourceRepo.CopyApplicationCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"+ instances: 3"},
+				[]string{"Dry run, no changes were made"},
+			))
+		})
+
+		It("does not promote the app when the user does not confirm", func() {
+			ui.Inputs = []string{"n"}
+
+			runCommand("--to-space", "production", "my-app")
+
+			Expect(ui.Prompts).To(ContainSubstrings([]string{"Really promote app my-app?"}))
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+		})
+
+		It("copies the droplet with --droplet", func() {
+			dropletRepo.DownloadDropletStub = func(appGuid string, destination io.Writer) (string, error) {
+				destination.Write([]byte("droplet contents"))
+				return "droplet-sha1", nil
+			}
+
+			uploaded := ""
+			dropletRepo.UploadDropletStub = func(appGuid string, droplet *os.File) error {
+				droplet.Seek(0, 0)
+				contents, _ := ioutil.ReadAll(droplet)
+				uploaded = string(contents)
+				return nil
+			}
+
+			runCommand("-f", "--droplet", "--to-space", "production", "my-app")
+
+			Expect(copyAppSourceRepo.CopyApplicationCallCount()).To(Equal(0))
+			appGuid, _ := dropletRepo.DownloadDropletArgsForCall(0)
+			Expect(appGuid).To(Equal("my-app-guid"))
+			appGuid, _ = dropletRepo.UploadDropletArgsForCall(0)
+			Expect(appGuid).To(Equal("my-app-guid"))
+			Expect(uploaded).To(Equal("droplet contents"))
+		})
+
+		Context("when the app exists in the destination space", func() {
+			BeforeEach(func() {
+				existing := models.Application{}
+				existing.Name = "my-app"
+				existing.Guid = "prod-app-guid"
+				appRepo.ReadFromSpaceReturns(existing, nil)
+
+				appRepo.UpdateAppResult = existing
+
+				existing.State = "started"
+				existing.InstanceCount = 1
+				existing.Services = []models.ServicePlanSummary{{Name: "my-db"}}
+				existing.EnvironmentVars = map[string]interface{}{"OLD": "value"}
+				appSummaryRepo.GetSummaryStub = func(appGuid string) (models.Application, error) {
+					if appGuid == "prod-app-guid" {
+						return existing, nil
+					}
+					return source, nil
+				}
+			})
+
+			It("updates the app instead of creating it", func() {
+				runCommand("-f", "--no-start", "--to-space", "production", "my-app")
+
+				Expect(appRepo.CreateAppParams).To(BeEmpty())
+				Expect(appRepo.UpdateAppGuid).To(Equal("prod-app-guid"))
+				Expect(*appRepo.UpdateParams.InstanceCount).To(Equal(3))
+
+				_, destGuid := copyAppSourceRepo.CopyApplicationArgsForCall(0)
+				Expect(destGuid).To(Equal("prod-app-guid"))
+			})
+
+			It("shows the changes to the existing app", func() {
+				runCommand("-f", "--no-start", "--to-space", "production", "my-app")
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"- instances: 1"},
+					[]string{"+ instances: 3"},
+					[]string{"- env.OLD: value"},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"service: my-db"}))
+				Expect(bindingRepo.CreateServiceInstanceGuid).To(BeEmpty())
+			})
+
+			It("restarts the app when it is running", func() {
+				runCommand("-f", "--to-space", "production", "my-app")
+
+				Expect(appRepo.UpdateCalls).To(Equal(3))
+				Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Stopping app", "my-app"},
+					[]string{"Starting app", "my-app"},
+				))
+			})
+		})
+	})
+
+	Context("when promoting to another foundation", func() {
+		var (
+			targetConfig      core_config.Repository
+			targetAppRepo     *testApplication.FakeApplicationRepository
+			targetSpaceRepo   *testapi.FakeSpaceRepository
+			targetBitsRepo    *testbits.FakeApplicationBitsRepository
+			targetStackRepo   *teststacks.FakeStackRepository
+			targetServiceRepo *testapi.FakeServiceRepo
+			loadedCFHome      string
+		)
+
+		BeforeEach(func() {
+			targetConfig = testconfig.NewRepositoryWithDefaults()
+			targetConfig.SetApiEndpoint("https://api.prod.example.com")
+
+			targetAppRepo = &testApplication.FakeApplicationRepository{}
+			targetAppRepo.ReadFromSpaceReturns(models.Application{}, errors.NewModelNotFoundError("App", "my-app"))
+
+			targetSpaceRepo = &testapi.FakeSpaceRepository{}
+			space := models.Space{}
+			space.Name = "production"
+			space.Guid = "target-space-guid"
+			targetSpaceRepo.FindByNameInOrgSpace = space
+
+			targetBitsRepo = &testbits.FakeApplicationBitsRepository{}
+
+			stackRepo.FindAllReturns([]models.Stack{{Name: "cflinuxfs2", Guid: "stack-guid"}}, nil)
+			targetStackRepo = &teststacks.FakeStackRepository{}
+			targetStackRepo.FindByNameReturns(models.Stack{Name: "cflinuxfs2", Guid: "target-stack-guid"}, nil)
+
+			targetServiceRepo = &testapi.FakeServiceRepo{}
+			targetServiceRepo.FindInstanceByNameServiceInstance = models.ServiceInstance{}
+
+			targetRouteRepo := &testapi.FakeRouteRepository{}
+			targetRouteRepo.FindByHostAndDomainReturns.Error = errors.NewModelNotFoundError("Route", "my-app")
+
+			targetDomainRepo := &testapi.FakeDomainRepository{}
+			targetDomainRepo.FindByNameInOrgDomain = []models.DomainFields{{Name: "prod.example.com", Guid: "target-domain-guid"}}
+
+			targetLoader = func(cfHome string) (core_config.Reader, api.RepositoryLocator, error) {
+				loadedCFHome = cfHome
+				locator := api.RepositoryLocator{}.
+					SetApplicationRepository(targetAppRepo).
+					SetAppSummaryRepository(&testapi.FakeAppSummaryRepo{}).
+					SetSpaceRepository(targetSpaceRepo).
+					SetApplicationBitsRepository(targetBitsRepo).
+					SetStackRepository(targetStackRepo).
+					SetServiceRepository(targetServiceRepo).
+					SetServiceBindingRepository(&testapi.FakeServiceBindingRepo{}).
+					SetRouteRepository(targetRouteRepo).
+					SetDomainRepository(targetDomainRepo)
+				return targetConfig, locator, nil
+			}
+		})
+
+		It("uploads the files of the source app to the target", func() {
+			dropletRepo.DownloadBitsStub = func(appGuid string, destination io.Writer) (string, error) {
+				destination.Write([]byte("app files"))
+				return "bits-sha1", nil
+			}
+
+			uploaded := ""
+			targetBitsRepo.UploadBitsStub = func(appGuid string, zipFile *os.File, presentFiles []resources.AppFileResource) error {
+				contents, _ := ioutil.ReadAll(zipFile)
+				uploaded = string(contents)
+				return nil
+			}
+
+			runCommand("-f", "--to-space", "production", "--to-target", "/home/me/prod", "--map-domain", "example.com:prod.example.com", "my-app")
+
+			Expect(loadedCFHome).To(Equal("/home/me/prod"))
+			Expect(copyAppSourceRepo.CopyApplicationCallCount()).To(Equal(0))
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+
+			params := targetAppRepo.CreatedAppParams()
+			Expect(*params.SpaceGuid).To(Equal("target-space-guid"))
+			Expect(*params.StackGuid).To(Equal("target-stack-guid"))
+
+			appGuid, _, _ := targetBitsRepo.UploadBitsArgsForCall(0)
+			Expect(appGuid).To(Equal("my-app-guid"))
+			Expect(uploaded).To(Equal("app files"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Promoting app", "https://api.prod.example.com"},
+				[]string{"+ route: my-app-dev.prod.example.com"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails when the target is not logged in", func() {
+			targetConfig.SetAccessToken("")
+
+			runCommand("-f", "--to-space", "production", "--to-target", "/home/me/prod", "my-app")
+
+			Expect(targetAppRepo.CreateAppParams).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Not logged in to the target in /home/me/prod"},
+			))
+		})
+	})
+})
@@ -1,8 +1,12 @@
 package application
 
 import (
+	"crypto/sha1"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,8 +18,12 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/actors"
 	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/api/authentication"
+	"github.com/cloudfoundry/cli/cf/api/organizations"
+	"github.com/cloudfoundry/cli/cf/api/space_quotas"
+	"github.com/cloudfoundry/cli/cf/api/spaces"
 	"github.com/cloudfoundry/cli/cf/api/stacks"
 	"github.com/cloudfoundry/cli/cf/app_files"
 	"github.com/cloudfoundry/cli/cf/command_registry"
@@ -37,6 +45,7 @@ type Push struct {
 	appStarter    ApplicationStarter
 	appStopper    ApplicationStopper
 	serviceBinder service.ServiceBinder
+	differ        ManifestDiffer
 	appRepo       applications.ApplicationRepository
 	domainRepo    api.DomainRepository
 	routeRepo     api.RouteRepository
@@ -47,6 +56,13 @@ type Push struct {
 	actor         actors.PushActor
 	zipper        app_files.Zipper
 	app_files     app_files.AppFiles
+	gitExporter   app_files.GitExporter
+	dropletRepo   application_bits.ApplicationDropletRepository
+
+	appSummaryRepo api.AppSummaryRepository
+	orgRepo        organizations.OrganizationRepository
+	spaceRepo      spaces.SpaceRepository
+	spaceQuotaRepo space_quotas.SpaceQuotaRepository
 }
 
 func init() {
@@ -66,20 +82,26 @@ func (cmd *Push) MetaData() command_registry.CommandMetadata {
 	fs["p"] = &cliFlags.StringFlag{Name: "p", Usage: T("Path to app directory or to a zip file of the contents of the app directory")}
 	fs["s"] = &cliFlags.StringFlag{Name: "s", Usage: T("Stack to use (a stack is a pre-built file system, including an operating system, that can run apps)")}
 	fs["t"] = &cliFlags.StringFlag{Name: "t", Usage: T("Maximum time (in seconds) for CLI to wait for application start, other server side timeouts may apply")}
+	fs["diff"] = &cliFlags.BoolFlag{Name: "diff", Usage: T("Show how the apps differ from the deployed apps and exit without pushing")}
+	fs["droplet"] = &cliFlags.StringFlag{Name: "droplet", Usage: T("Path to a droplet downloaded with download-droplet, to run instead of staging the app files")}
 	fs["docker-image"] = &cliFlags.StringFlag{Name: "docker-image", ShortName: "o", Usage: T("docker-image to be used (e.g. user/docker-image-name)")}
+	fs["git-ref"] = &cliFlags.StringFlag{Name: "git-ref", Usage: T("Push the committed tree of this git ref (e.g. a tag or commit) instead of the files in the app directory")}
 	fs["health-check-type"] = &cliFlags.StringFlag{Name: "health-check-type", ShortName: "u", Usage: T("Application health check type (e.g. port or none)")}
 	fs["no-hostname"] = &cliFlags.BoolFlag{Name: "no-hostname", Usage: T("Map the root domain to this app")}
 	fs["no-manifest"] = &cliFlags.BoolFlag{Name: "no-manifest", Usage: T("Ignore manifest file")}
 	fs["no-route"] = &cliFlags.BoolFlag{Name: "no-route", Usage: T("Do not map a route to this app and remove routes from previous pushes of this app.")}
 	fs["no-start"] = &cliFlags.BoolFlag{Name: "no-start", Usage: T("Do not start an app after pushing")}
+	fs["skip-quota-check"] = &cliFlags.BoolFlag{Name: "skip-quota-check", Usage: T("Do not check that the apps fit in the memory quotas of the org and space before pushing")}
+	fs["show-ignored"] = &cliFlags.BoolFlag{Name: "show-ignored", Usage: T("List the files excluded by .cfignore and exit without pushing")}
 	fs["random-route"] = &cliFlags.BoolFlag{Name: "random-route", Usage: T("Create a random route for this app")}
+	fs["strict-manifest"] = &cliFlags.BoolFlag{Name: "strict-manifest", Usage: T("Fail if the manifest contains unknown keys or invalid values")}
 
 	return command_registry.CommandMetadata{
 		Name:        "push",
 		ShortName:   "p",
 		Description: T("Push a new app or sync changes to an existing app"),
 		Usage: T("Push a single app (with or without a manifest):\n") + T("   CF_NAME push APP_NAME [-b BUILDPACK_NAME] [-c COMMAND] [-d DOMAIN] [-f MANIFEST_PATH] [--docker-image DOCKER_IMAGE]\n") + T("   [-i NUM_INSTANCES] [-k DISK] [-m MEMORY] [-n HOST] [-p PATH] [-s STACK] [-t TIMEOUT] [-u HEALTH_CHECK_TYPE] \n") +
-			"   [--no-hostname] [--no-manifest] [--no-route] [--no-start]\n" +
+			"   [--droplet DROPLET_PATH] [--git-ref REF] [--no-hostname] [--no-manifest] [--no-route] [--no-start] [--strict-manifest] [--skip-quota-check] [--diff] [--show-ignored]\n" +
 			"\n" + T("   Push multiple apps with a manifest:\n") + T("   CF_NAME push [-f MANIFEST_PATH]\n"),
 		Flags: fs,
 	}
@@ -117,6 +139,11 @@ func (cmd *Push) SetDependency(deps command_registry.Dependency, pluginCall bool
 	appCommand = appCommand.SetDependency(deps, false)
 	cmd.serviceBinder = appCommand.(service.ServiceBinder)
 
+	//set differ
+	appCommand = command_registry.Commands.FindCommand("manifest-diff")
+	appCommand = appCommand.SetDependency(deps, false)
+	cmd.differ = appCommand.(ManifestDiffer)
+
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
 	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
 	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
@@ -127,24 +154,49 @@ func (cmd *Push) SetDependency(deps command_registry.Dependency, pluginCall bool
 	cmd.actor = deps.PushActor
 	cmd.zipper = deps.AppZipper
 	cmd.app_files = deps.AppFiles
+	cmd.gitExporter = deps.GitExporter
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.orgRepo = deps.RepoLocator.GetOrganizationRepository()
+	cmd.spaceRepo = deps.RepoLocator.GetSpaceRepository()
+	cmd.spaceQuotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
 
 	return cmd
 }
 
 func (cmd *Push) Execute(c flags.FlagContext) {
 	appSet := cmd.findAndValidateAppsToPush(c)
+	if c.Bool("diff") {
+		cmd.differ.ShowManifestDiff(appSet)
+		return
+	}
+
+	if c.Bool("show-ignored") {
+		cmd.showIgnoredFiles(appSet)
+		return
+	}
+
+	dropletPath := c.String("droplet")
+	if dropletPath != "" {
+		cmd.verifyDroplet(dropletPath, appSet)
+	}
+
 	_, apiErr := cmd.authRepo.RefreshAuthToken()
 	if apiErr != nil {
 		cmd.ui.Failed(apiErr.Error())
 		return
 	}
 
+	if !c.Bool("skip-quota-check") && !c.Bool("no-start") {
+		cmd.checkQuotaCapacity(appSet)
+	}
+
 	routeActor := actors.NewRouteActor(cmd.ui, cmd.routeRepo)
 
 	for _, appParams := range appSet {
 		cmd.fetchStackGuid(&appParams)
 
-		if c.IsSet("docker-image") {
+		if appParams.DockerImage != nil {
 			diego := true
 			appParams.Diego = &diego
 		}
@@ -153,7 +205,18 @@ func (cmd *Push) Execute(c flags.FlagContext) {
 
 		cmd.updateRoutes(routeActor, app, appParams)
 
-		if c.String("docker-image") == "" {
+		if dropletPath != "" {
+			cmd.ui.Say(T("Uploading droplet for {{.AppName}}...",
+				map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+			apiErr := cmd.uploadDroplet(app.Guid, dropletPath)
+			if apiErr != nil {
+				cmd.ui.Failed(T("Error uploading droplet.\n{{.ApiErr}}",
+					map[string]interface{}{"ApiErr": apiErr.Error()}))
+				return
+			}
+			cmd.ui.Ok()
+		} else if appParams.DockerImage == nil {
 			cmd.ui.Say(T("Uploading {{.AppName}}...",
 				map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
 
@@ -398,7 +461,32 @@ func (cmd *Push) updateApp(app models.Application, appParams models.AppParams) (
 func (cmd *Push) findAndValidateAppsToPush(c flags.FlagContext) []models.AppParams {
 	appsFromManifest := cmd.getAppParamsFromManifest(c)
 	appFromContext := cmd.getAppParamsFromContext(c)
-	return cmd.createAppSetFromContextAndManifest(appFromContext, appsFromManifest)
+	appSet := cmd.createAppSetFromContextAndManifest(appFromContext, appsFromManifest)
+
+	if ref := c.String("git-ref"); ref != "" {
+		for i := range appSet {
+			path := cmd.gitSourceWithRef(*appSet[i].Path, ref)
+			appSet[i].Path = &path
+		}
+	}
+
+	return appSet
+}
+
+func (cmd *Push) gitSourceWithRef(path string, ref string) string {
+	if app_files.IsGitSource(path) {
+		if strings.Contains(path, "#") {
+			cmd.ui.Failed(T("Incorrect Usage. The app path {{.Path}} already names a git ref, --git-ref cannot also be used",
+				map[string]interface{}{"Path": path}))
+		}
+		return path + "#" + ref
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	return app_files.GitSource(absPath, ref)
 }
 
 func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParams {
@@ -417,6 +505,10 @@ func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParam
 		}
 	}
 
+	if c.Bool("strict-manifest") {
+		return cmd.getAppParamsFromValidatedManifest(c, path)
+	}
+
 	m, err := cmd.manifestRepo.ReadManifest(path)
 
 	if err != nil {
@@ -437,6 +529,31 @@ func (cmd *Push) getAppParamsFromManifest(c flags.FlagContext) []models.AppParam
 	return apps
 }
 
+func (cmd *Push) getAppParamsFromValidatedManifest(c flags.FlagContext, path string) []models.AppParams {
+	m, errs := cmd.manifestRepo.ValidateManifest(path)
+
+	if len(errs) > 0 {
+		if m.Path == "" && c.String("f") == "" {
+			return []models.AppParams{}
+		}
+
+		message := T("Manifest is invalid:")
+		for _, err := range errs {
+			message += "\n" + err.Error()
+		}
+		cmd.ui.Failed(message)
+	}
+
+	apps, err := m.Applications()
+	if err != nil {
+		cmd.ui.Failed("Error reading manifest file:\n%s", err)
+	}
+
+	cmd.ui.Say(T("Using manifest file {{.Path}}\n",
+		map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+	return apps
+}
+
 func (cmd *Push) createAppSetFromContextAndManifest(contextApp models.AppParams, manifestApps []models.AppParams) (apps []models.AppParams) {
 	var err error
 
@@ -604,6 +721,105 @@ func (cmd *Push) getAppParamsFromContext(c flags.FlagContext) (appParams models.
 	return
 }
 
+func (cmd *Push) showIgnoredFiles(appSet []models.AppParams) {
+	for _, appParams := range appSet {
+		if appParams.DockerImage != nil {
+			continue
+		}
+
+		ignoredFiles, err := cmd.ignoredFiles(*appParams.Path)
+		if err != nil {
+			cmd.ui.Failed(T("Error listing ignored files of {{.AppName}}:\n{{.Err}}",
+				map[string]interface{}{"AppName": *appParams.Name, "Err": err.Error()}))
+			return
+		}
+
+		cmd.ui.Say(T("Files of {{.AppName}} that will not be uploaded:",
+			map[string]interface{}{"AppName": terminal.EntityNameColor(*appParams.Name)}))
+		if len(ignoredFiles) == 0 {
+			cmd.ui.Say(T("   none"))
+		}
+		for _, fileName := range ignoredFiles {
+			cmd.ui.Say("   " + fileName)
+		}
+		cmd.ui.Say("")
+	}
+}
+
+func (cmd *Push) ignoredFiles(appDir string) (ignoredFiles []string, err error) {
+	isGitSource := app_files.IsGitSource(appDir)
+	if !isGitSource && !cmd.zipper.IsZipFile(appDir) {
+		return cmd.app_files.IgnoredFiles(appDir)
+	}
+
+	fileutils.TempDir("unzipped-app", func(tmpDir string, tmpErr error) {
+		if tmpErr != nil {
+			err = tmpErr
+			return
+		}
+
+		if isGitSource {
+			err = cmd.gitExporter.Export(appDir, tmpDir)
+		} else {
+			err = cmd.zipper.Unzip(appDir, tmpDir)
+		}
+		if err != nil {
+			return
+		}
+
+		ignoredFiles, err = cmd.app_files.IgnoredFiles(tmpDir)
+	})
+	return
+}
+
+// verifyDroplet fails unless the droplet matches the SHA1 saved next to it by
+// download-droplet. Droplets without a checksum file are not verified.
+func (cmd *Push) verifyDroplet(dropletPath string, appSet []models.AppParams) {
+	for _, appParams := range appSet {
+		if appParams.DockerImage != nil {
+			cmd.ui.Failed(T("Incorrect Usage. --droplet cannot be used with docker images"))
+		}
+	}
+
+	checksumFile, err := ioutil.ReadFile(checksumFilePath(dropletPath))
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		cmd.ui.Failed(T("Checksum file {{.Path}} is empty", map[string]interface{}{"Path": checksumFilePath(dropletPath)}))
+	}
+
+	hash := sha1.New()
+	droplet, err := os.Open(dropletPath)
+	if err == nil {
+		_, err = io.Copy(hash, droplet)
+		droplet.Close()
+	}
+	if err != nil {
+		cmd.ui.Failed(T("Error reading droplet {{.Path}}:\n{{.Err}}", map[string]interface{}{"Path": dropletPath, "Err": err.Error()}))
+	}
+
+	if actual := fmt.Sprintf("%x", hash.Sum(nil)); actual != fields[0] {
+		cmd.ui.Failed(T("Droplet {{.Path}} does not match its checksum: expected SHA1 {{.Expected}} but got {{.Actual}}",
+			map[string]interface{}{"Path": dropletPath, "Expected": fields[0], "Actual": actual}))
+	}
+}
+
+func (cmd *Push) uploadDroplet(appGuid string, dropletPath string) error {
+	droplet, err := os.Open(dropletPath)
+	if err != nil {
+		return err
+	}
+	defer droplet.Close()
+
+	return cmd.dropletRepo.UploadDroplet(appGuid, droplet)
+}
+
 func (cmd *Push) uploadApp(appGuid string, appDir string) (apiErr error) {
 	fileutils.TempDir("apps", func(uploadDir string, err error) {
 		if err != nil {
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+// defaultAppMemory is the memory the Cloud Controller gives new apps that
+// do not ask for any, in megabytes.
+const defaultAppMemory = 1024
+
+// quotaCapacity is what a push asks of the memory quotas of the targeted
+// org and space.
+type quotaCapacity struct {
+	spaceUsage     int64 // in megabytes, of the started apps of the space
+	memoryDelta    int64 // in megabytes, more memory the pushed apps will use
+	instanceMemory int64 // in megabytes, of the largest pushed app instance
+}
+
+// newQuotaCapacity compares the apps being pushed with the apps of the space.
+// Like the Cloud Controller, only started apps count against quotas; push
+// stops every app and only starts it again without --no-start.
+func newQuotaCapacity(appSet []models.AppParams, spaceApps []models.Application) (capacity quotaCapacity) {
+	current := map[string]models.Application{}
+	for _, app := range spaceApps {
+		current[app.Name] = app
+		capacity.spaceUsage += startedAppMemory(app.ApplicationFields)
+	}
+
+	for _, appParams := range appSet {
+		if appParams.Name == nil {
+			continue
+		}
+
+		app, exists := current[*appParams.Name]
+
+		memory := int64(defaultAppMemory)
+		if appParams.Memory != nil {
+			memory = *appParams.Memory
+		} else if exists {
+			memory = app.Memory
+		}
+
+		instances := 1
+		if appParams.InstanceCount != nil {
+			instances = *appParams.InstanceCount
+		} else if exists {
+			instances = app.InstanceCount
+		}
+
+		capacity.memoryDelta += memory * int64(instances)
+		if exists {
+			capacity.memoryDelta -= startedAppMemory(app.ApplicationFields)
+		}
+		if memory > capacity.instanceMemory {
+			capacity.instanceMemory = memory
+		}
+	}
+	return
+}
+
+func startedAppMemory(app models.ApplicationFields) int64 {
+	if strings.ToLower(app.State) != "started" {
+		return 0
+	}
+	return app.Memory * int64(app.InstanceCount)
+}
+
+// problems explains each limit of a quota the push would exceed. usage is
+// the memory the org or space already uses and owner names it.
+func (capacity quotaCapacity) problems(owner string, quotaName string, usage int64, memoryLimit int64, instanceMemoryLimit int64) (problems []string) {
+	if instanceMemoryLimit != -1 && capacity.instanceMemory > instanceMemoryLimit {
+		problems = append(problems, T("{{.Owner}} allows at most {{.Limit}} per app instance with quota {{.QuotaName}}, but an app asks for {{.Memory}}", map[string]interface{}{
+			"Owner":     owner,
+			"QuotaName": quotaName,
+			"Limit":     megabytes(instanceMemoryLimit),
+			"Memory":    megabytes(capacity.instanceMemory),
+		}))
+	}
+
+	if memoryLimit != -1 && capacity.memoryDelta > 0 && usage+capacity.memoryDelta > memoryLimit {
+		headroom := memoryLimit - usage
+		if headroom < 0 {
+			headroom = 0
+		}
+		problems = append(problems, T("{{.Owner}} has {{.Headroom}} left of the {{.Limit}} of quota {{.QuotaName}}, but the push needs {{.Delta}} more: {{.Missing}} is missing", map[string]interface{}{
+			"Owner":     owner,
+			"QuotaName": quotaName,
+			"Headroom":  megabytes(headroom),
+			"Limit":     megabytes(memoryLimit),
+			"Delta":     megabytes(capacity.memoryDelta),
+			"Missing":   megabytes(usage + capacity.memoryDelta - memoryLimit),
+		}))
+	}
+	return
+}
+
+func megabytes(value int64) string {
+	return formatters.ByteSize(value * formatters.MEGABYTE)
+}
+
+// checkQuotaCapacity fails before anything is created or uploaded when the
+// apps would not fit in the memory quotas of the targeted org and space.
+// Quotas that cannot be read are not checked; the push finds out anyway.
+func (cmd *Push) checkQuotaCapacity(appSet []models.AppParams) {
+	spaceApps, err := cmd.appSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		cmd.ui.Warn(T("Could not check the quotas before pushing: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+		return
+	}
+
+	capacity := newQuotaCapacity(appSet, spaceApps)
+	problems := []string{}
+
+	space, err := cmd.spaceRepo.FindByName(cmd.config.SpaceFields().Name)
+	if err == nil && space.SpaceQuotaGuid != "" {
+		var quota models.SpaceQuota
+		quota, err = cmd.spaceQuotaRepo.FindByGuid(space.SpaceQuotaGuid)
+		if err == nil {
+			owner := T("Space {{.SpaceName}}", map[string]interface{}{"SpaceName": space.Name})
+			problems = append(problems, capacity.problems(owner, quota.Name, capacity.spaceUsage, quota.MemoryLimit, quota.InstanceMemoryLimit)...)
+		}
+	}
+	if err != nil {
+		cmd.ui.Warn(T("Could not check the space quota before pushing: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	org, err := cmd.orgRepo.FindByName(cmd.config.OrganizationFields().Name)
+	if err == nil && org.QuotaDefinition.Guid != "" {
+		var orgUsage int64
+		orgUsage, err = cmd.orgRepo.GetMemoryUsage(org.Guid)
+		if err == nil {
+			quota := org.QuotaDefinition
+			owner := T("Org {{.OrgName}}", map[string]interface{}{"OrgName": org.Name})
+			problems = append(problems, capacity.problems(owner, quota.Name, orgUsage, quota.MemoryLimit, quota.InstanceMemoryLimit)...)
+		}
+	}
+	if err != nil {
+		cmd.ui.Warn(T("Could not check the org quota before pushing: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	if len(problems) > 0 {
+		cmd.ui.Failed(T("The apps do not fit in the memory quota:\n{{.Problems}}\n\nLower the memory or instances of the apps, or use --skip-quota-check to push anyway.",
+			map[string]interface{}{"Problems": strings.Join(problems, "\n")}))
+	}
+}
@@ -1,14 +1,20 @@
 package application_test
 
 import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"syscall"
 
 	fakeactors "github.com/cloudfoundry/cli/cf/actors/fakes"
+	testbits "github.com/cloudfoundry/cli/cf/api/application_bits/fakes"
 	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
 	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	testorg "github.com/cloudfoundry/cli/cf/api/organizations/fakes"
 	"github.com/cloudfoundry/cli/cf/api/resources"
+	testspacequota "github.com/cloudfoundry/cli/cf/api/space_quotas/fakes"
 	testStacks "github.com/cloudfoundry/cli/cf/api/stacks/fakes"
 	fakeappfiles "github.com/cloudfoundry/cli/cf/app_files/fakes"
 	"github.com/cloudfoundry/cli/cf/command_registry"
@@ -38,6 +44,7 @@ var _ = Describe("Push Command", func() {
 		starter                    *testcmd.FakeApplicationStarter
 		stopper                    *testcmd.FakeApplicationStopper
 		serviceBinder              *testcmd.FakeAppBinder
+		differ                     *testcmd.FakeManifestDiffer
 		appRepo                    *testApplication.FakeApplicationRepository
 		domainRepo                 *testapi.FakeDomainRepository
 		routeRepo                  *testapi.FakeRouteRepository
@@ -49,9 +56,16 @@ var _ = Describe("Push Command", func() {
 		actor                      *fakeactors.FakePushActor
 		app_files                  *fakeappfiles.FakeAppFiles
 		zipper                     *fakeappfiles.FakeZipper
+		gitExporter                *fakeappfiles.FakeGitExporter
+		dropletRepo                *testbits.FakeApplicationDropletRepository
+		appSummaryRepo             *testapi.FakeAppSummaryRepo
+		orgRepo                    *testorg.FakeOrganizationRepository
+		spaceRepo                  *testapi.FakeSpaceRepository
+		spaceQuotaRepo             *testspacequota.FakeSpaceQuotaRepository
 		OriginalCommandStart       command_registry.Command
 		OriginalCommandStop        command_registry.Command
 		OriginalCommandServiceBind command_registry.Command
+		OriginalCommandDiff        command_registry.Command
 		deps                       command_registry.Dependency
 	)
 
@@ -65,15 +79,22 @@ var _ = Describe("Push Command", func() {
 		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
 		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
 		deps.RepoLocator = deps.RepoLocator.SetAuthenticationRepository(authRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationDropletRepository(dropletRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetOrganizationRepository(orgRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceRepository(spaceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(spaceQuotaRepo)
 		deps.WordGenerator = wordGenerator
 		deps.PushActor = actor
 		deps.AppZipper = zipper
 		deps.AppFiles = app_files
+		deps.GitExporter = gitExporter
 
 		//inject fake commands dependencies into registry
 		command_registry.Register(starter)
 		command_registry.Register(stopper)
 		command_registry.Register(serviceBinder)
+		command_registry.Register(differ)
 
 		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("push").SetDependency(deps, false))
 	}
@@ -84,6 +105,7 @@ var _ = Describe("Push Command", func() {
 		starter = &testcmd.FakeApplicationStarter{}
 		stopper = &testcmd.FakeApplicationStopper{}
 		serviceBinder = &testcmd.FakeAppBinder{}
+		differ = &testcmd.FakeManifestDiffer{}
 
 		//setup fake commands (counterfeiter) to correctly interact with command_registry
 		starter.SetDependencyStub = func(_ command_registry.Dependency, _ bool) command_registry.Command {
@@ -96,6 +118,11 @@ var _ = Describe("Push Command", func() {
 		}
 		stopper.MetaDataReturns(command_registry.CommandMetadata{Name: "stop"})
 
+		differ.SetDependencyStub = func(_ command_registry.Dependency, _ bool) command_registry.Command {
+			return differ
+		}
+		differ.MetaDataReturns(command_registry.CommandMetadata{Name: "manifest-diff"})
+
 		appRepo = &testApplication.FakeApplicationRepository{}
 
 		domainRepo = &testapi.FakeDomainRepository{}
@@ -106,6 +133,7 @@ var _ = Describe("Push Command", func() {
 		OriginalCommandStart = command_registry.Commands.FindCommand("start")
 		OriginalCommandStop = command_registry.Commands.FindCommand("stop")
 		OriginalCommandServiceBind = command_registry.Commands.FindCommand("bind-service")
+		OriginalCommandDiff = command_registry.Commands.FindCommand("manifest-diff")
 
 		routeRepo = &testapi.FakeRouteRepository{}
 		stackRepo = &testStacks.FakeStackRepository{}
@@ -121,14 +149,21 @@ var _ = Describe("Push Command", func() {
 
 		zipper = &fakeappfiles.FakeZipper{}
 		app_files = &fakeappfiles.FakeAppFiles{}
+		gitExporter = &fakeappfiles.FakeGitExporter{}
+		dropletRepo = &testbits.FakeApplicationDropletRepository{}
 		actor = &fakeactors.FakePushActor{}
 
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		orgRepo = &testorg.FakeOrganizationRepository{}
+		spaceRepo = &testapi.FakeSpaceRepository{Spaces: []models.Space{{SpaceFields: models.SpaceFields{Name: "my-space", Guid: "my-space-guid"}}}}
+		spaceQuotaRepo = &testspacequota.FakeSpaceQuotaRepository{}
 	})
 
 	AfterEach(func() {
 		command_registry.Register(OriginalCommandStart)
 		command_registry.Register(OriginalCommandStop)
 		command_registry.Register(OriginalCommandServiceBind)
+		command_registry.Register(OriginalCommandDiff)
 	})
 
 	callPush := func(args ...string) bool {
@@ -431,6 +466,81 @@ var _ = Describe("Push Command", func() {
 				})
 			})
 
+			Context("when the --droplet flag is passed", func() {
+				var dropletPath string
+
+				BeforeEach(func() {
+					dropletFile, err := ioutil.TempFile("", "droplet")
+					Expect(err).NotTo(HaveOccurred())
+					dropletFile.WriteString("droplet contents")
+					dropletFile.Close()
+					dropletPath = dropletFile.Name()
+				})
+
+				AfterEach(func() {
+					os.Remove(dropletPath)
+					os.Remove(dropletPath + ".sha1")
+				})
+
+				It("uploads the droplet instead of the app files", func() {
+					callPush("testApp", "--droplet", dropletPath)
+
+					Expect(dropletRepo.UploadDropletCallCount()).To(Equal(1))
+					appGuid, droplet := dropletRepo.UploadDropletArgsForCall(0)
+					Expect(appGuid).To(Equal("testApp-guid"))
+					Expect(droplet.Name()).To(Equal(dropletPath))
+
+					Expect(actor.GatherFilesCallCount()).To(Equal(0))
+					Expect(actor.UploadAppCallCount()).To(Equal(0))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Uploading droplet for", "testApp"},
+						[]string{"OK"},
+					))
+				})
+
+				It("uploads a droplet that matches its checksum file", func() {
+					ioutil.WriteFile(dropletPath+".sha1", []byte(fmt.Sprintf("%x  droplet.tgz\n", sha1.Sum([]byte("droplet contents")))), 0644)
+
+					callPush("testApp", "--droplet", dropletPath)
+
+					Expect(dropletRepo.UploadDropletCallCount()).To(Equal(1))
+				})
+
+				It("fails when the droplet does not match its checksum file", func() {
+					ioutil.WriteFile(dropletPath+".sha1", []byte("0000000000000000000000000000000000000000  droplet.tgz\n"), 0644)
+
+					callPush("testApp", "--droplet", dropletPath)
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"does not match its checksum"},
+					))
+					Expect(appRepo.CreateAppParams).To(BeEmpty())
+					Expect(dropletRepo.UploadDropletCallCount()).To(Equal(0))
+				})
+
+				It("fails when the droplet cannot be uploaded", func() {
+					dropletRepo.UploadDropletReturns(errors.New("upload failed"))
+
+					callPush("testApp", "--droplet", dropletPath)
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Error uploading droplet"},
+						[]string{"upload failed"},
+					))
+				})
+
+				It("cannot be used with a docker image", func() {
+					callPush("testApp", "--droplet", dropletPath, "--docker-image", "sample/dockerImage")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"--droplet cannot be used with docker images"},
+					))
+				})
+			})
+
 			Context("when health-check-type '-u' or '--health-check-type' is supplied", func() {
 				It("shows error if value is not 'port' or none'", func() {
 					callPush("app-name", "-u", "bad-value")
@@ -544,6 +654,40 @@ var _ = Describe("Push Command", func() {
 				Expect(appDir).To(Equal("../some/path-to/an-app/zip-file"))
 			})
 
+			It("pushes a ref of a git repository specified using the -p flag", func() {
+				callPush("-p", "git+https://example.com/app.git#v1.2.3", "app-with-path")
+
+				appDir, _ := actor.GatherFilesArgsForCall(0)
+				Expect(appDir).To(Equal("git+https://example.com/app.git#v1.2.3"))
+			})
+
+			Context("when the --git-ref flag is passed", func() {
+				It("pushes that ref of the repository in the app directory", func() {
+					callPush("-p", "some/repo", "--git-ref", "v1.2.3", "app-with-path")
+
+					absPath, _ := filepath.Abs("some/repo")
+					appDir, _ := actor.GatherFilesArgsForCall(0)
+					Expect(appDir).To(Equal("git+" + absPath + "#v1.2.3"))
+				})
+
+				It("pushes that ref of a git repository URL", func() {
+					callPush("-p", "git+https://example.com/app.git", "--git-ref", "v1.2.3", "app-with-path")
+
+					appDir, _ := actor.GatherFilesArgsForCall(0)
+					Expect(appDir).To(Equal("git+https://example.com/app.git#v1.2.3"))
+				})
+
+				It("fails when the app path already names a ref", func() {
+					callPush("-p", "git+https://example.com/app.git#v1.0.0", "--git-ref", "v1.2.3", "app-with-path")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"already names a git ref"},
+					))
+					Expect(actor.GatherFilesCallCount()).To(Equal(0))
+				})
+			})
+
 			It("pushes the contents of the current working directory by default", func() {
 				callPush("app-with-default-path")
 				dir, _ := os.Getwd()
@@ -564,6 +708,91 @@ var _ = Describe("Push Command", func() {
 				))
 			})
 
+			Context("when the --diff flag is passed", func() {
+				It("shows the differences instead of pushing the apps", func() {
+					manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+
+					callPush("--diff")
+
+					Expect(differ.ShowManifestDiffCallCount()).To(Equal(1))
+					apps := differ.ShowManifestDiffArgsForCall(0)
+					Expect(apps).To(HaveLen(1))
+					Expect(*apps[0].Name).To(Equal("manifest-app-name"))
+
+					Expect(appRepo.CreateAppParams).To(BeEmpty())
+					Expect(actor.GatherFilesCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the --show-ignored flag is passed", func() {
+				It("lists the ignored files instead of pushing the apps", func() {
+					manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+					app_files.IgnoredFilesReturns([]string{"node_modules/", "debug.log"}, nil)
+
+					callPush("--show-ignored")
+
+					Expect(app_files.IgnoredFilesCallCount()).To(Equal(1))
+					Expect(app_files.IgnoredFilesArgsForCall(0)).To(ContainSubstring(filepath.Clean("some/path/from/manifest")))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"Files of", "manifest-app-name", "will not be uploaded"},
+						[]string{"node_modules/"},
+						[]string{"debug.log"},
+					))
+
+					Expect(appRepo.CreateAppParams).To(BeEmpty())
+					Expect(actor.GatherFilesCallCount()).To(Equal(0))
+				})
+
+				It("fails when the ignored files cannot be listed", func() {
+					manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
+					app_files.IgnoredFilesReturns(nil, errors.New("walk error"))
+
+					callPush("--show-ignored")
+
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"walk error"},
+					))
+				})
+			})
+
+			Context("when the --strict-manifest flag is passed", func() {
+				It("fails with every problem found in the manifest", func() {
+					manifestRepo.ValidateManifestReturns.Manifest = singleAppManifest()
+					manifestRepo.ValidateManifestReturns.Errors = []error{
+						manifest.NewValidationError("manifest.yml", 3, "Unknown manifest key 'memroy', did you mean 'memory'?"),
+						manifest.NewValidationError("manifest.yml", 5, "Expected no-route to be a boolean."),
+					}
+
+					callPush("--strict-manifest", "-f", "manifest.yml")
+
+					Expect(manifestRepo.ValidateManifestArgs.Path).To(Equal("manifest.yml"))
+					Expect(ui.Outputs).To(ContainSubstrings(
+						[]string{"FAILED"},
+						[]string{"Manifest is invalid"},
+						[]string{"manifest.yml:3: Unknown manifest key 'memroy', did you mean 'memory'?"},
+						[]string{"manifest.yml:5: Expected no-route to be a boolean."},
+					))
+					Expect(appRepo.CreateAppParams).To(BeEmpty())
+				})
+
+				It("pushes the apps in a valid manifest", func() {
+					domainRepo.FindByNameInOrgDomain = []models.DomainFields{
+						models.DomainFields{
+							Name: "manifest-example.com",
+							Guid: "bar-domain-guid",
+						},
+					}
+					manifestRepo.ValidateManifestReturns.Manifest = singleAppManifest()
+
+					callPush("--strict-manifest")
+
+					Expect(ui.Outputs).To(ContainSubstrings([]string{"Using manifest file", "manifest.yml"}))
+					Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"FAILED"}))
+					Expect(*appRepo.CreatedAppParams().Name).To(Equal("manifest-app-name"))
+				})
+			})
+
 			It("does not fail when the current working directory does not contain a manifest", func() {
 				manifestRepo.ReadManifestReturns.Manifest = singleAppManifest()
 				manifestRepo.ReadManifestReturns.Error = syscall.ENOENT
@@ -1170,6 +1399,109 @@ var _ = Describe("Push Command", func() {
 		})
 	})
 
+	Describe("checking the quotas before pushing", func() {
+		BeforeEach(func() {
+			appRepo.ReadReturns.Error = errors.NewModelNotFoundError("App", "the-app")
+
+			zipper.ZipReturns(nil)
+			zipper.GetZipSizeReturns(9001, nil)
+			actor.GatherFilesReturns(nil, true, nil)
+			actor.UploadAppReturns(nil)
+
+			existingApp := models.Application{}
+			existingApp.Name = "existing-app"
+			existingApp.State = "started"
+			existingApp.Memory = 256
+			existingApp.InstanceCount = 2
+			stoppedApp := models.Application{}
+			stoppedApp.Name = "stopped-app"
+			stoppedApp.State = "stopped"
+			stoppedApp.Memory = 4096
+			stoppedApp.InstanceCount = 4
+			appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{existingApp, stoppedApp}
+
+			spaceRepo.Spaces[0].SpaceQuotaGuid = "space-quota-guid"
+			spaceQuotaRepo.FindByGuidReturns(models.SpaceQuota{Name: "space-quota", MemoryLimit: 2048, InstanceMemoryLimit: -1}, nil)
+
+			org := models.Organization{}
+			org.Name = "my-org"
+			org.Guid = "my-org-guid"
+			org.QuotaDefinition = models.NewQuotaFields("org-quota", 10240, 1024, 10, 10, true)
+			org.QuotaDefinition.Guid = "org-quota-guid"
+			orgRepo.FindByNameReturns(org, nil)
+			orgRepo.GetMemoryUsageReturns(8192, nil)
+		})
+
+		It("pushes when the apps fit in the quotas", func() {
+			callPush("-m", "512M", "-i", "2", "my-new-app")
+
+			Expect(spaceQuotaRepo.FindByGuidArgsForCall(0)).To(Equal("space-quota-guid"))
+			Expect(orgRepo.GetMemoryUsageArgsForCall(0)).To(Equal("my-org-guid"))
+			Expect(appRepo.CreateAppParams).To(HaveLen(1))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"FAILED"}))
+		})
+
+		It("fails before creating or uploading anything, explaining the missing headroom", func() {
+			callPush("-m", "1G", "-i", "2", "my-new-app")
+
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+			Expect(actor.UploadAppCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"do not fit in the memory quota"},
+				[]string{"Space my-space has 1.5G left of the 2G of quota space-quota, but the push needs 2G more: 512M is missing"},
+				[]string{"--skip-quota-check"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Org my-org has"}))
+		})
+
+		It("only counts the memory an existing started app adds", func() {
+			callPush("-i", "10", "existing-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Space my-space has 1.5G left of the 2G of quota space-quota, but the push needs 2G more: 512M is missing"},
+			))
+		})
+
+		It("checks the org quota, including its instance memory limit", func() {
+			spaceRepo.Spaces[0].SpaceQuotaGuid = ""
+
+			callPush("-m", "2G", "-i", "2", "my-new-app")
+
+			Expect(spaceQuotaRepo.FindByGuidCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Org my-org allows at most 1G per app instance with quota org-quota, but an app asks for 2G"},
+				[]string{"Org my-org has 2G left of the 10G of quota org-quota, but the push needs 4G more: 2G is missing"},
+			))
+		})
+
+		It("does not check the quotas with --skip-quota-check", func() {
+			callPush("--skip-quota-check", "-m", "1G", "-i", "2", "my-new-app")
+
+			Expect(orgRepo.FindByNameCallCount()).To(Equal(0))
+			Expect(appRepo.CreateAppParams).To(HaveLen(1))
+		})
+
+		It("does not check the quotas with --no-start", func() {
+			callPush("--no-start", "-m", "1G", "-i", "2", "my-new-app")
+
+			Expect(orgRepo.FindByNameCallCount()).To(Equal(0))
+			Expect(appRepo.CreateAppParams).To(HaveLen(1))
+		})
+
+		It("warns and pushes when a quota cannot be read", func() {
+			orgRepo.GetMemoryUsageReturns(0, errors.New("usage failed"))
+			spaceRepo.Spaces[0].SpaceQuotaGuid = ""
+
+			callPush("-m", "1G", "-i", "2", "my-new-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Could not check the org quota before pushing: usage failed"},
+			))
+			Expect(appRepo.CreateAppParams).To(HaveLen(1))
+		})
+	})
+
 	It("fails when neither a manifest nor a name is given", func() {
 		manifestRepo.ReadManifestReturns.Error = errors.New("No such manifest")
 		callPush()
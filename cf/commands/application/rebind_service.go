@@ -0,0 +1,261 @@
+package application
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/cloudfoundry/cli/json"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type RebindService struct {
+	ui                 terminal.UI
+	config             core_config.Reader
+	serviceBindingRepo api.ServiceBindingRepository
+	appSummaryRepo     api.AppSummaryRepository
+	serviceInstanceReq requirements.ServiceInstanceRequirement
+	runWaiter          appRunWaiter
+}
+
+func init() {
+	command_registry.Register(&RebindService{})
+}
+
+func (cmd *RebindService) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["apps"] = &cliFlags.StringFlag{Name: "apps", Usage: T("Comma-delimited list of the bound apps to rebind (Default: all bound apps)")}
+	fs["c"] = &cliFlags.StringFlag{Name: "c", Usage: T("Valid JSON object containing service-specific configuration parameters for the new bindings, provided either in-line or in a file")}
+	fs["restart"] = &cliFlags.BoolFlag{Name: "restart", Usage: T("Restart each app after rebinding it")}
+	fs["restage"] = &cliFlags.BoolFlag{Name: "restage", Usage: T("Restage each app after rebinding it")}
+
+	baseUsage := T(`CF_NAME rebind-service SERVICE_INSTANCE [--apps APP1,APP2] [-c PARAMETERS_AS_JSON] [--restart | --restage]
+
+   Unbinds and binds the apps again one at a time, so that they get new credentials.
+   When an app cannot be bound again, its binding is restored without parameters.
+   With --restart or --restage each app must be running again before the next one is rebound,
+   and the remaining apps are left alone once an app fails.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME rebind-service mydb --restage
+   CF_NAME rebind-service mydb --apps app1,app2 -c '{"permissions":"read-only"}' --restart`)
+
+	return command_registry.CommandMetadata{
+		Name:        "rebind-service",
+		Description: T("Re-create the bindings of a service instance to apps"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *RebindService) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("rebind-service"))
+	}
+
+	if fc.Bool("restart") && fc.Bool("restage") {
+		cmd.ui.Failed(T("Incorrect Usage. --restart and --restage cannot be combined\n\n") + command_registry.Commands.CommandUsage("rebind-service"))
+	}
+
+	cmd.serviceInstanceReq = requirementsFactory.NewServiceInstanceRequirement(fc.Args()[0])
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+		cmd.serviceInstanceReq,
+	}
+	return
+}
+
+func (cmd *RebindService) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.serviceBindingRepo = deps.RepoLocator.GetServiceBindingRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.runWaiter = newAppRunWaiter(deps)
+	return cmd
+}
+
+func (cmd *RebindService) Execute(c flags.FlagContext) {
+	serviceInstance := cmd.serviceInstanceReq.GetServiceInstance()
+
+	paramsMap, err := json.ParseJsonFromFileOrString(c.String("c"))
+	if err != nil {
+		cmd.ui.Failed(T("Invalid configuration provided for -c flag. Please provide a valid JSON object or path to a file containing a valid JSON object."))
+	}
+
+	cmd.ui.Say(T("Rebinding service {{.ServiceInstanceName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"ServiceInstanceName": terminal.EntityNameColor(serviceInstance.Name),
+			"OrgName":             terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":           terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser":         terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	bindings, err := cmd.serviceBindingRepo.ListAllForService(serviceInstance.Guid)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+	serviceInstance.ServiceBindings = bindings
+
+	apps := cmd.boundApps(serviceInstance, c.String("apps"))
+	if len(apps) == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say("")
+		cmd.ui.Say(T("No apps are bound to service {{.ServiceInstanceName}}", map[string]interface{}{"ServiceInstanceName": serviceInstance.Name}))
+		return
+	}
+
+	table := terminal.NewTable(cmd.ui, []string{T("app"), T("result"), T("details")})
+	var failedApp string
+	var unbound bool
+	for _, app := range apps {
+		if failedApp != "" {
+			table.Add(app.Name, T("skipped"), "")
+			continue
+		}
+
+		cmd.ui.Say(T("Rebinding app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+		result, details, err := cmd.rebindApp(serviceInstance, app, paramsMap, c)
+		if err != nil {
+			failedApp = app.Name
+			if unboundErr, ok := err.(*appUnboundError); ok {
+				unbound = true
+				table.Add(app.Name, terminal.FailureColor(T("failed, app is now unbound")), unboundErr.Error())
+			} else {
+				table.Add(app.Name, terminal.FailureColor(T("failed")), err.Error())
+			}
+			continue
+		}
+		table.Add(app.Name, result, details)
+	}
+
+	cmd.ui.Say("")
+	table.Print()
+	cmd.ui.Say("")
+
+	if unbound {
+		cmd.ui.Failed(T("Stopped rebinding after app {{.AppName}} failed. The app is no longer bound to service {{.ServiceInstanceName}}; bind it again with '{{.CFCommand}}'",
+			map[string]interface{}{
+				"AppName":             failedApp,
+				"ServiceInstanceName": serviceInstance.Name,
+				"CFCommand":           terminal.CommandColor(cf.Name() + " bind-service " + failedApp + " " + serviceInstance.Name),
+			}))
+	}
+
+	if failedApp != "" {
+		cmd.ui.Failed(T("Stopped rebinding after app {{.AppName}} failed", map[string]interface{}{"AppName": failedApp}))
+	}
+
+	cmd.ui.Ok()
+
+	if !c.Bool("restart") && !c.Bool("restage") {
+		cmd.ui.Say(T("TIP: Use '{{.CFCommand}}' or --restage to ensure your env variable changes take effect",
+			map[string]interface{}{"CFCommand": terminal.CommandColor(cf.Name() + " restage")}))
+	}
+}
+
+// boundApps returns the apps bound to the service instance, sorted by name,
+// restricted to the comma-delimited appNames when given.
+func (cmd *RebindService) boundApps(serviceInstance models.ServiceInstance, appNames string) []models.Application {
+	allApps, err := cmd.appSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	boundAppGuids := map[string]bool{}
+	for _, binding := range serviceInstance.ServiceBindings {
+		boundAppGuids[binding.AppGuid] = true
+	}
+
+	boundApps := map[string]models.Application{}
+	for _, app := range allApps {
+		if boundAppGuids[app.Guid] {
+			boundApps[app.Name] = app
+		}
+	}
+
+	apps := []models.Application{}
+	if appNames == "" {
+		for _, app := range boundApps {
+			apps = append(apps, app)
+		}
+	} else {
+		for _, name := range ui_helpers.ParseTags(appNames) {
+			app, ok := boundApps[name]
+			if !ok {
+				cmd.ui.Failed(T("App {{.AppName}} is not bound to service {{.ServiceInstanceName}}",
+					map[string]interface{}{"AppName": name, "ServiceInstanceName": serviceInstance.Name}))
+			}
+			apps = append(apps, app)
+		}
+	}
+
+	sort.Sort(appsByName(apps))
+	return apps
+}
+
+func (cmd *RebindService) rebindApp(serviceInstance models.ServiceInstance, app models.Application, paramsMap map[string]interface{}, c flags.FlagContext) (result string, details string, err error) {
+	_, err = cmd.serviceBindingRepo.Delete(serviceInstance, app.Guid)
+	if err != nil {
+		return
+	}
+
+	err = cmd.serviceBindingRepo.Create(serviceInstance.Guid, app.Guid, paramsMap)
+	if err != nil {
+		err = cmd.restoreBinding(serviceInstance, app, err)
+		return
+	}
+
+	restart, restage := c.Bool("restart"), c.Bool("restage")
+	if (restart || restage) && app.State == "stopped" {
+		return T("rebound"), T("not restarted because the app is stopped"), nil
+	}
+
+	switch {
+	case restart:
+		err = cmd.runWaiter.restart(app)
+		result = T("rebound and restarted")
+	case restage:
+		err = cmd.runWaiter.restage(app)
+		result = T("rebound and restaged")
+	default:
+		result = T("rebound")
+	}
+
+	return
+}
+
+// restoreBinding binds an app again after its new binding could not be
+// created, so that it is not left without the service. The parameters of the
+// original binding cannot be read, so it is restored without any.
+func (cmd *RebindService) restoreBinding(serviceInstance models.ServiceInstance, app models.Application, bindErr error) error {
+	restoreErr := cmd.serviceBindingRepo.Create(serviceInstance.Guid, app.Guid, nil)
+	if restoreErr != nil {
+		return &appUnboundError{bindErr: bindErr, restoreErr: restoreErr}
+	}
+
+	return errors.New(T("{{.Err}}; the original binding was restored without parameters", map[string]interface{}{"Err": bindErr.Error()}))
+}
+
+// appUnboundError is returned when neither the new binding nor the original
+// one could be created, which leaves the app without the service.
+type appUnboundError struct {
+	bindErr    error
+	restoreErr error
+}
+
+func (err *appUnboundError) Error() string {
+	return T("{{.Err}}; restoring the original binding failed too: {{.RestoreErr}}",
+		map[string]interface{}{"Err": err.bindErr.Error(), "RestoreErr": err.restoreErr.Error()})
+}
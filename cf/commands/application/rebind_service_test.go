@@ -0,0 +1,224 @@
+package application_test
+
+import (
+	testAppInstanaces "github.com/cloudfoundry/cli/cf/api/app_instances/fakes"
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rebind-service command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		serviceBindingRepo  *testapi.FakeServiceBindingRepo
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		appRepo             *testApplication.FakeApplicationRepository
+		appInstancesRepo    *testAppInstanaces.FakeAppInstancesRepository
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetServiceBindingRepository(serviceBindingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("rebind-service").SetDependency(deps, pluginCall))
+	}
+
+	newApp := func(name, state string) models.Application {
+		app := models.Application{}
+		app.Name = name
+		app.Guid = name + "-guid"
+		app.State = state
+		return app
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		serviceInstance := models.ServiceInstance{}
+		serviceInstance.Name = "my-db"
+		serviceInstance.Guid = "my-db-guid"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, ServiceInstance: serviceInstance}
+
+		serviceBindingRepo = &testapi.FakeServiceBindingRepo{}
+		serviceBindingRepo.ListAllForServiceBindings = []models.ServiceBindingFields{
+			{AppGuid: "orders-guid"},
+			{AppGuid: "billing-guid"},
+		}
+
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{
+			newApp("orders", "started"),
+			newApp("billing", "started"),
+			newApp("unbound", "started"),
+		}
+
+		appRepo = &testApplication.FakeApplicationRepository{}
+		appRepo.UpdateAppResult = models.Application{ApplicationFields: models.ApplicationFields{PackageState: "STAGED"}}
+		appRepo.GetAppReturns(models.Application{ApplicationFields: models.ApplicationFields{PackageState: "STAGED"}}, nil)
+		appInstancesRepo = &testAppInstanaces.FakeAppInstancesRepository{}
+		appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceRunning}}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("rebind-service", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("requires the service instance", func() {
+			Expect(runCommand("my-db")).To(BeTrue())
+			Expect(requirementsFactory.ServiceInstanceName).To(Equal("my-db"))
+		})
+
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-db")).To(BeFalse())
+		})
+
+		It("fails with usage when not provided a service instance", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+
+		It("fails with usage when given both --restart and --restage", func() {
+			runCommand("my-db", "--restart", "--restage")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--restart and --restage cannot be combined"},
+			))
+		})
+	})
+
+	It("re-creates the bindings of all bound apps", func() {
+		runCommand("my-db", "-c", `{"permissions":"read-only"}`)
+
+		Expect(serviceBindingRepo.ListAllForServiceInstanceGuid).To(Equal("my-db-guid"))
+		Expect(serviceBindingRepo.DeletedApplicationGuids).To(Equal([]string{"billing-guid", "orders-guid"}))
+		Expect(serviceBindingRepo.DeleteServiceInstance.ServiceBindings).To(HaveLen(2))
+		Expect(serviceBindingRepo.CreatedApplicationGuids).To(Equal([]string{"billing-guid", "orders-guid"}))
+		Expect(serviceBindingRepo.CreateServiceInstanceGuid).To(Equal("my-db-guid"))
+		Expect(serviceBindingRepo.CreateParams).To(Equal(map[string]interface{}{"permissions": "read-only"}))
+		Expect(appRepo.UpdateCalls).To(Equal(0))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Rebinding service", "my-db", "my-org", "my-space", "my-user"},
+			[]string{"app", "result", "details"},
+			[]string{"billing", "rebound"},
+			[]string{"orders", "rebound"},
+			[]string{"OK"},
+			[]string{"TIP", "restage"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"unbound"}))
+	})
+
+	It("rebinds only the given apps", func() {
+		runCommand("my-db", "--apps", "orders")
+
+		Expect(serviceBindingRepo.CreatedApplicationGuids).To(Equal([]string{"orders-guid"}))
+	})
+
+	It("fails when a given app is not bound to the service instance", func() {
+		runCommand("my-db", "--apps", "orders,unbound")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"App unbound is not bound to service my-db"},
+		))
+		Expect(serviceBindingRepo.CreatedApplicationGuids).To(BeEmpty())
+	})
+
+	It("says so when no apps are bound", func() {
+		serviceBindingRepo.ListAllForServiceBindings = []models.ServiceBindingFields{}
+
+		runCommand("my-db")
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"No apps are bound to service my-db"}))
+	})
+
+	It("restarts each app after rebinding it", func() {
+		runCommand("my-db", "--restart")
+
+		Expect(appRepo.UpdateCalls).To(Equal(4))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"billing", "rebound and restarted"},
+			[]string{"orders", "rebound and restarted"},
+		))
+	})
+
+	It("restages each app after rebinding it", func() {
+		runCommand("my-db", "--restage")
+
+		Expect(appRepo.CreateRestageRequestArgs.AppGuid).To(Equal("orders-guid"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"billing", "rebound and restaged"},
+			[]string{"orders", "rebound and restaged"},
+		))
+	})
+
+	It("does not restart stopped apps", func() {
+		appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{newApp("orders", "stopped")}
+
+		runCommand("my-db", "--restart")
+
+		Expect(appRepo.UpdateCalls).To(Equal(0))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"orders", "rebound", "not restarted"}))
+	})
+
+	It("stops at the first app that fails", func() {
+		appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceCrashed}}, nil)
+
+		runCommand("my-db", "--restart")
+
+		Expect(serviceBindingRepo.CreatedApplicationGuids).To(Equal([]string{"billing-guid"}))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"billing", "failed", "Start unsuccessful"},
+			[]string{"orders", "skipped"},
+			[]string{"FAILED"},
+			[]string{"Stopped rebinding after app billing failed"},
+		))
+	})
+
+	It("restores the binding when the new one cannot be created", func() {
+		serviceBindingRepo.CreateErrorCodes = []string{"90003", ""}
+
+		runCommand("my-db", "-c", `{"permissions":"read-only"}`)
+
+		Expect(serviceBindingRepo.CreatedApplicationGuids).To(Equal([]string{"billing-guid", "billing-guid"}))
+		Expect(serviceBindingRepo.CreateParams).To(BeNil())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"billing", "failed", "Error binding service", "restored without parameters"},
+			[]string{"orders", "skipped"},
+			[]string{"FAILED"},
+			[]string{"Stopped rebinding after app billing failed"},
+		))
+	})
+
+	It("reports apps that are left unbound when the binding cannot be restored", func() {
+		serviceBindingRepo.CreateErrorCode = "90003"
+
+		runCommand("my-db")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"billing", "failed, app is now unbound", "restoring the original binding failed too"},
+			[]string{"FAILED"},
+			[]string{"billing", "no longer bound to service my-db", "bind-service billing my-db"},
+		))
+	})
+})
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
@@ -16,7 +17,9 @@ type Restage struct {
 	ui                terminal.UI
 	config            core_config.Reader
 	appRepo           applications.ApplicationRepository
+	appSummaryRepo    api.AppSummaryRepository
 	appStagingWatcher ApplicationStagingWatcher
+	runWaiter         appRunWaiter
 }
 
 func init() {
@@ -24,15 +27,24 @@ func init() {
 }
 
 func (cmd *Restage) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	addBulkFlags(fs)
+
 	return command_registry.CommandMetadata{
 		Name:        "restage",
 		ShortName:   "rg",
 		Description: T("Restage an app"),
-		Usage:       T("CF_NAME restage APP_NAME"),
+		Usage:       T("CF_NAME restage APP_NAME\n   CF_NAME restage (--match PATTERN | --all) [-f]"),
+		Flags:       fs,
 	}
 }
 
 func (cmd *Restage) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		reqs = bulkRequirements(cmd.ui, "restage", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("restage"))
 	}
@@ -48,6 +60,8 @@ func (cmd *Restage) SetDependency(deps command_registry.Dependency, pluginCall b
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.runWaiter = newAppRunWaiter(deps)
 
 	//get command from registry for dependency
 	commandDep := command_registry.Commands.FindCommand("start")
@@ -58,6 +72,11 @@ func (cmd *Restage) SetDependency(deps command_registry.Dependency, pluginCall b
 }
 
 func (cmd *Restage) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("restage"), cmd.restageApp)
+		return
+	}
+
 	app, err := cmd.appRepo.Read(c.Args()[0])
 	if notFound, ok := err.(*errors.ModelNotFoundError); ok {
 		cmd.ui.Failed(notFound.Error())
@@ -77,3 +96,12 @@ func (cmd *Restage) Execute(c flags.FlagContext) {
 		return app, cmd.appRepo.CreateRestageRequest(app.Guid)
 	})
 }
+
+func (cmd *Restage) restageApp(app models.Application) (string, error) {
+	err := cmd.runWaiter.restage(app)
+	if err != nil {
+		return "", err
+	}
+
+	return T("restaged"), nil
+}
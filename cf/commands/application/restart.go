@@ -1,6 +1,7 @@
 package application
 
 import (
+	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -17,11 +18,13 @@ type ApplicationRestarter interface {
 }
 
 type Restart struct {
-	ui      terminal.UI
-	config  core_config.Reader
-	starter ApplicationStarter
-	stopper ApplicationStopper
-	appReq  requirements.ApplicationRequirement
+	ui             terminal.UI
+	config         core_config.Reader
+	starter        ApplicationStarter
+	stopper        ApplicationStopper
+	appReq         requirements.ApplicationRequirement
+	appSummaryRepo api.AppSummaryRepository
+	runWaiter      appRunWaiter
 }
 
 func init() {
@@ -29,15 +32,24 @@ func init() {
 }
 
 func (cmd *Restart) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	addBulkFlags(fs)
+
 	return command_registry.CommandMetadata{
 		Name:        "restart",
 		ShortName:   "rs",
 		Description: T("Restart an app"),
-		Usage:       T("CF_NAME restart APP_NAME"),
+		Usage:       T("CF_NAME restart APP_NAME\n   CF_NAME restart (--match PATTERN | --all) [-f]"),
+		Flags:       fs,
 	}
 }
 
 func (cmd *Restart) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		reqs = bulkRequirements(cmd.ui, "restart", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("restart"))
 	}
@@ -55,6 +67,8 @@ func (cmd *Restart) Requirements(requirementsFactory requirements.Factory, fc fl
 func (cmd *Restart) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.runWaiter = newAppRunWaiter(deps)
 
 	//get start for dependency
 	starter := command_registry.Commands.FindCommand("start")
@@ -70,6 +84,11 @@ func (cmd *Restart) SetDependency(deps command_registry.Dependency, pluginCall b
 }
 
 func (cmd *Restart) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("restart"), cmd.restartApp)
+		return
+	}
+
 	app := cmd.appReq.GetApplication()
 	cmd.ApplicationRestart(app, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
 }
@@ -89,3 +108,12 @@ func (cmd *Restart) ApplicationRestart(app models.Application, orgName, spaceNam
 		return
 	}
 }
+
+func (cmd *Restart) restartApp(app models.Application) (string, error) {
+	err := cmd.runWaiter.restart(app)
+	if err != nil {
+		return "", err
+	}
+
+	return T("restarted"), nil
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
@@ -14,11 +15,13 @@ import (
 )
 
 type Scale struct {
-	ui        terminal.UI
-	config    core_config.Reader
-	restarter ApplicationRestarter
-	appReq    requirements.ApplicationRequirement
-	appRepo   applications.ApplicationRepository
+	ui             terminal.UI
+	config         core_config.Reader
+	restarter      ApplicationRestarter
+	appReq         requirements.ApplicationRequirement
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	runWaiter      appRunWaiter
 }
 
 func init() {
@@ -31,16 +34,26 @@ func (cmd *Scale) MetaData() command_registry.CommandMetadata {
 	fs["k"] = &cliFlags.StringFlag{Name: "k", Usage: T("Disk limit (e.g. 256M, 1024M, 1G)")}
 	fs["m"] = &cliFlags.StringFlag{Name: "m", Usage: T("Memory limit (e.g. 256M, 1024M, 1G)")}
 	fs["f"] = &cliFlags.BoolFlag{Name: "f", Usage: T("Force restart of app without prompt")}
+	addBulkFlags(fs)
 
 	return command_registry.CommandMetadata{
 		Name:        "scale",
 		Description: T("Change or view the instance count, disk space limit, and memory limit for an app"),
-		Usage:       T("CF_NAME scale APP_NAME [-i INSTANCES] [-k DISK] [-m MEMORY] [-f]"),
+		Usage:       T("CF_NAME scale APP_NAME [-i INSTANCES] [-k DISK] [-m MEMORY] [-f]\n   CF_NAME scale (--match PATTERN | --all) [-i INSTANCES] [-k DISK] [-m MEMORY] [-f]"),
 		Flags:       fs,
 	}
 }
 
 func (cmd *Scale) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		if !anyFlagsSet(fc) {
+			cmd.ui.Failed(T("Incorrect Usage. --match and --all require -i, -k or -m\n\n") + command_registry.Commands.CommandUsage("scale"))
+		}
+
+		reqs = bulkRequirements(cmd.ui, "scale", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("scale"))
 	}
@@ -59,6 +72,8 @@ func (cmd *Scale) SetDependency(deps command_registry.Dependency, pluginCall boo
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.runWaiter = newAppRunWaiter(deps)
 
 	//get command from registry for dependency
 	commandDep := command_registry.Commands.FindCommand("restart")
@@ -71,6 +86,14 @@ func (cmd *Scale) SetDependency(deps command_registry.Dependency, pluginCall boo
 var bytesInAMegabyte int64 = 1024 * 1024
 
 func (cmd *Scale) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		params, shouldRestart := cmd.scaleParams(c)
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("scale"), func(app models.Application) (string, error) {
+			return cmd.scaleApp(app, params, shouldRestart)
+		})
+		return
+	}
+
 	currentApp := cmd.appReq.GetApplication()
 	if !anyFlagsSet(c) {
 		cmd.ui.Say(T("Showing current scale of app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
@@ -90,9 +113,36 @@ func (cmd *Scale) Execute(c flags.FlagContext) {
 		return
 	}
 
-	params := models.AppParams{}
-	shouldRestart := false
+	params, shouldRestart := cmd.scaleParams(c)
 
+	if shouldRestart && !cmd.confirmRestart(c, currentApp.Name) {
+		return
+	}
+
+	cmd.ui.Say(T("Scaling app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"AppName":     terminal.EntityNameColor(currentApp.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	updatedApp, apiErr := cmd.appRepo.Update(currentApp.Guid, params)
+	if apiErr != nil {
+		cmd.ui.Failed(apiErr.Error())
+		return
+	}
+
+	cmd.ui.Ok()
+
+	if shouldRestart {
+		cmd.restarter.ApplicationRestart(updatedApp, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
+	}
+}
+
+// scaleParams returns the changes requested by the flags, and whether they
+// require the app to restart.
+func (cmd *Scale) scaleParams(c flags.FlagContext) (params models.AppParams, shouldRestart bool) {
 	if c.String("m") != "" {
 		memory, err := formatters.ToMegabytes(c.String("m"))
 		if err != nil {
@@ -124,29 +174,23 @@ func (cmd *Scale) Execute(c flags.FlagContext) {
 		params.InstanceCount = &instances
 	}
 
-	if shouldRestart && !cmd.confirmRestart(c, currentApp.Name) {
-		return
-	}
-
-	cmd.ui.Say(T("Scaling app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
-		map[string]interface{}{
-			"AppName":     terminal.EntityNameColor(currentApp.Name),
-			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
-			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
-			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
-		}))
+	return
+}
 
-	updatedApp, apiErr := cmd.appRepo.Update(currentApp.Guid, params)
-	if apiErr != nil {
-		cmd.ui.Failed(apiErr.Error())
-		return
+func (cmd *Scale) scaleApp(app models.Application, params models.AppParams, shouldRestart bool) (string, error) {
+	_, err := cmd.appRepo.Update(app.Guid, params)
+	if err != nil {
+		return "", err
 	}
 
-	cmd.ui.Ok()
-
-	if shouldRestart {
-		cmd.restarter.ApplicationRestart(updatedApp, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
+	if shouldRestart && app.State == "started" {
+		err = cmd.runWaiter.restart(app)
+		if err != nil {
+			return "", err
+		}
 	}
+
+	return T("scaled"), nil
 }
 
 func (cmd *Scale) confirmRestart(context flags.FlagContext, appName string) bool {
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/models"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
 	"github.com/simonleung8/flags"
 )
 
@@ -25,16 +26,29 @@ func init() {
 
 func (cmd *SetEnv) MetaData() command_registry.CommandMetadata {
 	return command_registry.CommandMetadata{
-		Name:            "set-env",
-		ShortName:       "se",
-		Description:     T("Set an env variable for an app"),
-		Usage:           T("CF_NAME set-env APP_NAME ENV_VAR_NAME ENV_VAR_VALUE"),
+		Name:        "set-env",
+		ShortName:   "se",
+		Description: T("Set an env variable for an app"),
+		Usage: T(`CF_NAME set-env APP_NAME ENV_VAR_NAME ENV_VAR_VALUE
+   CF_NAME set-env APP_NAME --from-file FILE [--replace]
+
+   Files contain a JSON object or dotenv lines (NAME=value). With --replace, variables not in the file are removed.
+   All variables are updated at once.
+
+EXAMPLE:
+   CF_NAME set-env my-app LOG_LEVEL debug
+   CF_NAME set-env my-app --from-file .env
+   CF_NAME set-env my-app --from-file env.json --replace`),
 		SkipFlagParsing: true,
 	}
 }
 
 func (cmd *SetEnv) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
-	if len(fc.Args()) != 3 {
+	if isSetEnvFromFile(fc.Args()) {
+		if _, _, ok := parseSetEnvFromFileArgs(fc.Args()); !ok {
+			cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name --from-file file' as arguments\n\n") + command_registry.Commands.CommandUsage("set-env"))
+		}
+	} else if len(fc.Args()) != 3 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name env-name env-value' as arguments\n\n") + command_registry.Commands.CommandUsage("set-env"))
 	}
 
@@ -56,6 +70,11 @@ func (cmd *SetEnv) SetDependency(deps command_registry.Dependency, pluginCall bo
 }
 
 func (cmd *SetEnv) Execute(c flags.FlagContext) {
+	if isSetEnvFromFile(c.Args()) {
+		cmd.setEnvFromFile(c.Args())
+		return
+	}
+
 	varName := c.Args()[1]
 	varValue := c.Args()[2]
 	app := cmd.appReq.GetApplication()
@@ -86,3 +105,70 @@ func (cmd *SetEnv) Execute(c flags.FlagContext) {
 	cmd.ui.Say(T("TIP: Use '{{.Command}}' to ensure your env variable changes take effect",
 		map[string]interface{}{"Command": terminal.CommandColor(cf.Name() + " restage")}))
 }
+
+func (cmd *SetEnv) setEnvFromFile(args []string) {
+	path, replace, _ := parseSetEnvFromFileArgs(args)
+	app := cmd.appReq.GetApplication()
+
+	cmd.ui.Say(T("Setting env variables from {{.Path}} for app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"Path":        terminal.EntityNameColor(path),
+			"AppName":     terminal.EntityNameColor(app.Name),
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username())}))
+
+	fileVars, err := ui_helpers.ReadEnvVarsFile(path)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+
+	envParams := map[string]interface{}{}
+	if !replace {
+		for name, value := range app.EnvironmentVars {
+			envParams[name] = value
+		}
+	}
+	for name, value := range fileVars {
+		envParams[name] = value
+	}
+
+	if len(ui_helpers.EnvVarsDiff(app.EnvironmentVars, envParams)) == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("Env variables of app {{.AppName}} are already up to date",
+			map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+		return
+	}
+
+	_, apiErr := cmd.appRepo.Update(app.Guid, models.AppParams{EnvironmentVars: &envParams})
+	if apiErr != nil {
+		cmd.ui.Failed(apiErr.Error())
+		return
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("TIP: Use '{{.Command}}' to ensure your env variable changes take effect",
+		map[string]interface{}{"Command": terminal.CommandColor(cf.Name() + " restage")}))
+}
+
+// set-env skips flag parsing so that values may start with a hyphen, so the
+// --from-file form is recognized here
+func isSetEnvFromFile(args []string) bool {
+	return len(args) > 1 && (args[1] == "--from-file" || args[1] == "--replace")
+}
+
+func parseSetEnvFromFileArgs(args []string) (path string, replace bool, ok bool) {
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--from-file" && path == "" && i+1 < len(args):
+			path = args[i+1]
+			i++
+		case args[i] == "--replace" && !replace:
+			replace = true
+		default:
+			return "", false, false
+		}
+	}
+	return path, replace, path != ""
+}
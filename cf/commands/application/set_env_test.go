@@ -1,6 +1,10 @@
 package application_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
@@ -166,4 +170,91 @@ var _ = Describe("set-env command", func() {
 			})
 		})
 	})
+
+	Context("when setting env variables from a file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			app.EnvironmentVars = map[string]interface{}{"foo": "bar", "LOG_LEVEL": "info"}
+			requirementsFactory.Application = app
+			requirementsFactory.LoginSuccess = true
+			requirementsFactory.TargetedSpaceSuccess = true
+
+			var err error
+			dir, err = ioutil.TempDir("", "set-env")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		writeFile := func(name string, contents string) string {
+			path := filepath.Join(dir, name)
+			Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			return path
+		}
+
+		It("merges the variables of a dotenv file into the env in a single update", func() {
+			path := writeFile(".env", "LOG_LEVEL=debug\nDATABASE_URL=\"mysql://example.com/my-db\"\n")
+
+			runCommand("my-app", "--from-file", path)
+
+			Expect(appRepo.UpdateAppGuid).To(Equal("my-app-guid"))
+			Expect(*appRepo.UpdateParams.EnvironmentVars).To(Equal(map[string]interface{}{
+				"foo":          "bar",
+				"LOG_LEVEL":    "debug",
+				"DATABASE_URL": "mysql://example.com/my-db",
+			}))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Setting env variables from", path, "my-app", "my-org", "my-space", "my-user"},
+				[]string{"OK"},
+				[]string{"TIP"},
+			))
+		})
+
+		It("replaces the whole env with --replace", func() {
+			path := writeFile("env.json", `{"LOG_LEVEL": "debug", "WORKERS": 4}`)
+
+			runCommand("my-app", "--from-file", path, "--replace")
+
+			Expect(*appRepo.UpdateParams.EnvironmentVars).To(Equal(map[string]interface{}{
+				"LOG_LEVEL": "debug",
+				"WORKERS":   float64(4),
+			}))
+		})
+
+		It("does not update the app or suggest a restage when nothing changed", func() {
+			path := writeFile(".env", "LOG_LEVEL=info\n")
+
+			runCommand("my-app", "--from-file", path)
+
+			Expect(appRepo.UpdateAppGuid).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"OK"},
+				[]string{"Env variables of app", "my-app", "already up to date"},
+			))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"TIP"}))
+		})
+
+		It("fails when the file is invalid", func() {
+			path := writeFile(".env", "LOG_LEVEL\n")
+
+			runCommand("my-app", "--from-file", path)
+
+			Expect(appRepo.UpdateAppGuid).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Invalid env file", "line 1: expected NAME=value"},
+			))
+		})
+
+		It("fails with usage when the file is missing", func() {
+			runCommand("my-app", "--replace", "--from-file")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--from-file"},
+			))
+		})
+	})
 })
@@ -2,10 +2,8 @@ package application
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -49,6 +47,7 @@ type Start struct {
 	appReq           requirements.ApplicationRequirement
 	appRepo          applications.ApplicationRepository
 	appInstancesRepo app_instances.AppInstancesRepository
+	appSummaryRepo   api.AppSummaryRepository
 	oldLogsRepo      api.OldLogsRepository
 	logRepo          api.LogsNoaaRepository
 
@@ -63,15 +62,24 @@ func init() {
 }
 
 func (cmd *Start) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	addBulkFlags(fs)
+
 	return command_registry.CommandMetadata{
 		Name:        "start",
 		ShortName:   "st",
 		Description: T("Start an app"),
-		Usage:       T("CF_NAME start APP_NAME"),
+		Usage:       T("CF_NAME start APP_NAME\n   CF_NAME start (--match PATTERN | --all) [-f]"),
+		Flags:       fs,
 	}
 }
 
 func (cmd *Start) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		reqs = bulkRequirements(cmd.ui, "start", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("start"))
 	}
@@ -87,32 +95,14 @@ func (cmd *Start) SetDependency(deps command_registry.Dependency, pluginCall boo
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
 	cmd.appInstancesRepo = deps.RepoLocator.GetAppInstancesRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	cmd.logRepo = deps.RepoLocator.GetLogsNoaaRepository()
 	cmd.oldLogsRepo = deps.RepoLocator.GetOldLogsRepository()
 	cmd.LogServerConnectionTimeout = 20 * time.Second
 	cmd.PingerThrottle = DefaultPingerThrottle
 
-	if os.Getenv("CF_STAGING_TIMEOUT") != "" {
-		duration, err := strconv.ParseInt(os.Getenv("CF_STAGING_TIMEOUT"), 10, 64)
-		if err != nil {
-			cmd.ui.Failed(T("invalid value for env var CF_STAGING_TIMEOUT\n{{.Err}}",
-				map[string]interface{}{"Err": err}))
-		}
-		cmd.StagingTimeout = time.Duration(duration) * time.Minute
-	} else {
-		cmd.StagingTimeout = DefaultStagingTimeout
-	}
-
-	if os.Getenv("CF_STARTUP_TIMEOUT") != "" {
-		duration, err := strconv.ParseInt(os.Getenv("CF_STARTUP_TIMEOUT"), 10, 64)
-		if err != nil {
-			cmd.ui.Failed(T("invalid value for env var CF_STARTUP_TIMEOUT\n{{.Err}}",
-				map[string]interface{}{"Err": err}))
-		}
-		cmd.StartupTimeout = time.Duration(duration) * time.Minute
-	} else {
-		cmd.StartupTimeout = DefaultStartupTimeout
-	}
+	cmd.StagingTimeout = stagingTimeoutFromEnv(cmd.ui)
+	cmd.StartupTimeout = startupTimeoutFromEnv(cmd.ui)
 
 	appCommand := command_registry.Commands.FindCommand("app")
 	appCommand = appCommand.SetDependency(deps, false)
@@ -122,6 +112,11 @@ func (cmd *Start) SetDependency(deps command_registry.Dependency, pluginCall boo
 }
 
 func (cmd *Start) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("start"), cmd.startApp)
+		return
+	}
+
 	cmd.ApplicationStart(cmd.appReq.GetApplication(), cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
 }
 
@@ -144,6 +139,19 @@ func (cmd *Start) ApplicationStart(app models.Application, orgName, spaceName st
 	})
 }
 
+func (cmd *Start) startApp(app models.Application) (string, error) {
+	if app.State == "started" {
+		return T("already started"), nil
+	}
+
+	err := cmd.runWaiter().start(app)
+	if err != nil {
+		return "", err
+	}
+
+	return T("started"), nil
+}
+
 func (cmd *Start) ApplicationWatchStaging(app models.Application, orgName, spaceName string, start func(app models.Application) (models.Application, error)) (updatedApp models.Application, err error) {
 	var isConnected bool
 	loggingStartedChan := make(chan bool)
@@ -249,30 +257,30 @@ func (cmd *Start) tailStagingLogs(app models.Application, startChan, doneChan ch
 	close(doneChan)
 }
 
-func (cmd *Start) waitForInstancesToStage(app models.Application) bool {
-	stagingStartTime := time.Now()
-
-	var err error
-
-	if cmd.StagingTimeout == 0 {
-		app, err = cmd.appRepo.GetApp(app.Guid)
-	} else {
-		for app.PackageState != "STAGED" && app.PackageState != "FAILED" && time.Since(stagingStartTime) < cmd.StagingTimeout {
-			app, err = cmd.appRepo.GetApp(app.Guid)
-			if err != nil {
-				break
-			}
-			cmd.ui.Wait(cmd.PingerThrottle)
-		}
+// runWaiter waits with the timeouts of this command, which push and restart
+// can change.
+func (cmd *Start) runWaiter() appRunWaiter {
+	return appRunWaiter{
+		ui:               cmd.ui,
+		appRepo:          cmd.appRepo,
+		appInstancesRepo: cmd.appInstancesRepo,
+		stagingTimeout:   cmd.StagingTimeout,
+		startupTimeout:   cmd.StartupTimeout,
+		pingerThrottle:   cmd.PingerThrottle,
 	}
+}
 
-	if err != nil {
-		cmd.ui.Failed(err.Error())
-	}
+func (cmd *Start) waitForInstancesToStage(app models.Application) bool {
+	_, err := cmd.runWaiter().waitForStaging(app, cmd.StagingTimeout)
 
-	if app.PackageState == "FAILED" {
+	switch err := err.(type) {
+	case nil:
+		return true
+	case *waitTimeoutError:
+		return false
+	case *stagingFailedError:
 		cmd.ui.Say("")
-		if app.StagingFailedReason == "NoAppDetectedError" {
+		if err.reason == "NoAppDetectedError" {
 			cmd.ui.Failed(T(`{{.Err}}
 			
 TIP: Buildpacks are detected when the "{{.PushCommand}}" is executed from within the directory that contains the app source code.
@@ -281,100 +289,39 @@ Use '{{.BuildpackCommand}}' to see a list of supported buildpacks.
 
 Use '{{.Command}}' for more in depth log information.`,
 				map[string]interface{}{
-					"Err":              app.StagingFailedReason,
+					"Err":              err.reason,
 					"PushCommand":      terminal.CommandColor(fmt.Sprintf("%s push", cf.Name())),
 					"BuildpackCommand": terminal.CommandColor(fmt.Sprintf("%s buildpacks", cf.Name())),
 					"Command":          terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))}))
 		} else {
 			cmd.ui.Failed(T("{{.Err}}\n\nTIP: use '{{.Command}}' for more information",
 				map[string]interface{}{
-					"Err":     app.StagingFailedReason,
+					"Err":     err.reason,
 					"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))}))
 		}
-	}
-
-	if time.Since(stagingStartTime) >= cmd.StagingTimeout {
-		return false
+	default:
+		cmd.ui.Failed(err.Error())
 	}
 
 	return true
 }
 
 func (cmd *Start) waitForOneRunningInstance(app models.Application) {
-	startupStartTime := time.Now()
-
-	for {
-		if time.Since(startupStartTime) > cmd.StartupTimeout {
-			tipMsg := T("Start app timeout\n\nTIP: Application must be listening on the right port. Instead of hard coding the port, use the $PORT environment variable.") + "\n\n"
-			tipMsg += T("Use '{{.Command}}' for more information", map[string]interface{}{"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))})
-
-			cmd.ui.Failed(tipMsg)
-			return
-		}
-
-		count, err := cmd.fetchInstanceCount(app.Guid)
-		if err != nil {
-			cmd.ui.Wait(cmd.PingerThrottle)
-			continue
-		}
-
+	err := cmd.runWaiter().waitForRunningInstances(app.Guid, 1, cmd.StartupTimeout, func(count instanceCount) {
 		cmd.ui.Say(instancesDetails(count))
+	})
 
-		if count.running > 0 {
-			return
-		}
-
-		if count.flapping > 0 || count.crashed > 0 {
-			cmd.ui.Failed(fmt.Sprintf(T("Start unsuccessful\n\nTIP: use '{{.Command}}' for more information",
-				map[string]interface{}{"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))})))
-			return
-		}
-
-		cmd.ui.Wait(cmd.PingerThrottle)
-	}
-}
-
-type instanceCount struct {
-	running         int
-	starting        int
-	startingDetails map[string]struct{}
-	flapping        int
-	down            int
-	crashed         int
-	total           int
-}
-
-func (cmd Start) fetchInstanceCount(appGuid string) (instanceCount, error) {
-	count := instanceCount{
-		startingDetails: make(map[string]struct{}),
-	}
-
-	instances, apiErr := cmd.appInstancesRepo.GetInstances(appGuid)
-	if apiErr != nil {
-		return instanceCount{}, apiErr
-	}
-
-	count.total = len(instances)
+	switch err.(type) {
+	case nil:
+	case *waitTimeoutError:
+		tipMsg := T("Start app timeout\n\nTIP: Application must be listening on the right port. Instead of hard coding the port, use the $PORT environment variable.") + "\n\n"
+		tipMsg += T("Use '{{.Command}}' for more information", map[string]interface{}{"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))})
 
-	for _, inst := range instances {
-		switch inst.State {
-		case models.InstanceRunning:
-			count.running++
-		case models.InstanceStarting:
-			count.starting++
-			if inst.Details != "" {
-				count.startingDetails[inst.Details] = struct{}{}
-			}
-		case models.InstanceFlapping:
-			count.flapping++
-		case models.InstanceDown:
-			count.down++
-		case models.InstanceCrashed:
-			count.crashed++
-		}
+		cmd.ui.Failed(tipMsg)
+	default:
+		cmd.ui.Failed(fmt.Sprintf(T("Start unsuccessful\n\nTIP: use '{{.Command}}' for more information",
+			map[string]interface{}{"Command": terminal.CommandColor(fmt.Sprintf("%s logs %s --recent", cf.Name(), app.Name))})))
 	}
-
-	return count, nil
 }
 
 func instancesDetails(count instanceCount) string {
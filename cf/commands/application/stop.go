@@ -6,6 +6,7 @@ import (
 	. "github.com/cloudfoundry/cli/cf/i18n"
 	"github.com/simonleung8/flags"
 
+	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
@@ -21,10 +22,11 @@ type ApplicationStopper interface {
 }
 
 type Stop struct {
-	ui      terminal.UI
-	config  core_config.Reader
-	appRepo applications.ApplicationRepository
-	appReq  requirements.ApplicationRequirement
+	ui             terminal.UI
+	config         core_config.Reader
+	appRepo        applications.ApplicationRepository
+	appSummaryRepo api.AppSummaryRepository
+	appReq         requirements.ApplicationRequirement
 }
 
 func init() {
@@ -32,15 +34,24 @@ func init() {
 }
 
 func (cmd *Stop) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	addBulkFlags(fs)
+
 	return command_registry.CommandMetadata{
 		Name:        "stop",
 		ShortName:   "sp",
 		Description: T("Stop an app"),
-		Usage:       T("CF_NAME stop APP_NAME"),
+		Usage:       T("CF_NAME stop APP_NAME\n   CF_NAME stop (--match PATTERN | --all) [-f]"),
+		Flags:       fs,
 	}
 }
 
 func (cmd *Stop) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if bulkSelected(fc) {
+		reqs = bulkRequirements(cmd.ui, "stop", requirementsFactory, fc)
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("stop"))
 	}
@@ -57,6 +68,7 @@ func (cmd *Stop) SetDependency(deps command_registry.Dependency, pluginCall bool
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
 	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
 	return cmd
 }
 
@@ -86,6 +98,11 @@ func (cmd *Stop) ApplicationStop(app models.Application, orgName, spaceName stri
 }
 
 func (cmd *Stop) Execute(c flags.FlagContext) {
+	if bulkSelected(c) {
+		bulkAppRunner{ui: cmd.ui, config: cmd.config, appSummaryRepo: cmd.appSummaryRepo}.run(c, T("stop"), cmd.stopApp)
+		return
+	}
+
 	app := cmd.appReq.GetApplication()
 	if app.State == "stopped" {
 		cmd.ui.Say(terminal.WarningColor(T("App ") + app.Name + T(" is already stopped")))
@@ -93,3 +110,17 @@ func (cmd *Stop) Execute(c flags.FlagContext) {
 		cmd.ApplicationStop(app, cmd.config.OrganizationFields().Name, cmd.config.SpaceFields().Name)
 	}
 }
+
+func (cmd *Stop) stopApp(app models.Application) (string, error) {
+	if app.State == "stopped" {
+		return T("already stopped"), nil
+	}
+
+	state := "STOPPED"
+	_, err := cmd.appRepo.Update(app.Guid, models.AppParams{State: &state})
+	if err != nil {
+		return "", err
+	}
+
+	return T("stopped"), nil
+}
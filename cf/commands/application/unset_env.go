@@ -1,6 +1,8 @@
 package application
 
 import (
+	"strings"
+
 	"github.com/cloudfoundry/cli/cf"
 	"github.com/cloudfoundry/cli/cf/api/applications"
 	"github.com/cloudfoundry/cli/cf/command_registry"
@@ -33,13 +35,13 @@ func (cmd *UnsetEnv) SetDependency(deps command_registry.Dependency, pluginCall
 func (cmd *UnsetEnv) MetaData() command_registry.CommandMetadata {
 	return command_registry.CommandMetadata{
 		Name:        "unset-env",
-		Description: T("Remove an env variable"),
-		Usage:       T("CF_NAME unset-env APP_NAME ENV_VAR_NAME"),
+		Description: T("Remove env variables"),
+		Usage:       T("CF_NAME unset-env APP_NAME ENV_VAR_NAME [ENV_VAR_NAME...]"),
 	}
 }
 
 func (cmd *UnsetEnv) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
-	if len(fc.Args()) != 2 {
+	if len(fc.Args()) < 2 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires 'app-name env-name' as arguments\n\n") + command_registry.Commands.CommandUsage("unset-env"))
 	}
 
@@ -54,12 +56,12 @@ func (cmd *UnsetEnv) Requirements(requirementsFactory requirements.Factory, fc f
 }
 
 func (cmd *UnsetEnv) Execute(c flags.FlagContext) {
-	varName := c.Args()[1]
+	varNames := c.Args()[1:]
 	app := cmd.appReq.GetApplication()
 
 	cmd.ui.Say(T("Removing env variable {{.VarName}} from app {{.AppName}} in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
 		map[string]interface{}{
-			"VarName":     terminal.EntityNameColor(varName),
+			"VarName":     terminal.EntityNameColor(strings.Join(varNames, ", ")),
 			"AppName":     terminal.EntityNameColor(app.Name),
 			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
 			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
@@ -67,14 +69,24 @@ func (cmd *UnsetEnv) Execute(c flags.FlagContext) {
 
 	envParams := app.EnvironmentVars
 
-	if _, ok := envParams[varName]; !ok {
+	removed := false
+	notSet := []string{}
+	for _, varName := range varNames {
+		if _, ok := envParams[varName]; !ok {
+			notSet = append(notSet, varName)
+			continue
+		}
+
+		delete(envParams, varName)
+		removed = true
+	}
+
+	if !removed {
 		cmd.ui.Ok()
-		cmd.ui.Warn(T("Env variable {{.VarName}} was not set.", map[string]interface{}{"VarName": varName}))
+		cmd.warnNotSet(notSet)
 		return
 	}
 
-	delete(envParams, varName)
-
 	_, apiErr := cmd.appRepo.Update(app.Guid, models.AppParams{EnvironmentVars: &envParams})
 	if apiErr != nil {
 		cmd.ui.Failed(apiErr.Error())
@@ -82,6 +94,13 @@ func (cmd *UnsetEnv) Execute(c flags.FlagContext) {
 	}
 
 	cmd.ui.Ok()
+	cmd.warnNotSet(notSet)
 	cmd.ui.Say(T("TIP: Use '{{.Command}}' to ensure your env variable changes take effect",
 		map[string]interface{}{"Command": terminal.CommandColor(cf.Name() + " restage")}))
 }
+
+func (cmd *UnsetEnv) warnNotSet(varNames []string) {
+	for _, varName := range varNames {
+		cmd.ui.Warn(T("Env variable {{.VarName}} was not set.", map[string]interface{}{"VarName": varName}))
+	}
+}
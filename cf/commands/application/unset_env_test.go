@@ -61,12 +61,12 @@ var _ = Describe("unset-env command", func() {
 			Expect(runCommand("foo", "bar")).To(BeFalse())
 		})
 
-		It("fails with usage when not provided with exactly 2 args", func() {
+		It("fails with usage when not provided with an env variable name", func() {
 			requirementsFactory.LoginSuccess = true
 			requirementsFactory.TargetedSpaceSuccess = true
 			requirementsFactory.Application = app
 
-			Expect(runCommand("too", "many", "args")).To(BeFalse())
+			Expect(runCommand("my-app")).To(BeFalse())
 		})
 	})
 
@@ -111,6 +111,18 @@ var _ = Describe("unset-env command", func() {
 			})
 		})
 
+		It("removes several variables in a single update", func() {
+			runCommand("my-app", "DATABASE_URL", "foo", "NOT_SET")
+
+			Expect(*appRepo.UpdateParams.EnvironmentVars).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Removing env variable", "DATABASE_URL, foo, NOT_SET", "my-app"},
+				[]string{"OK"},
+				[]string{"TIP"},
+			))
+			Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"NOT_SET", "was not set."}))
+		})
+
 		It("tells the user if the specified env var was not set", func() {
 			runCommand("my-app", "CANT_STOP_WONT_STOP_UNSETTIN_THIS_ENV")
 
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/commands/service"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+// WaitTimeoutExitCode is the exit status of wait when the timeout is reached;
+// failures exit with 1 like every other command.
+const WaitTimeoutExitCode = 2
+
+type Wait struct {
+	ui          terminal.UI
+	config      core_config.Reader
+	appReq      requirements.ApplicationRequirement
+	serviceRepo api.ServiceRepository
+	runWaiter   appRunWaiter
+}
+
+func init() {
+	command_registry.Register(&Wait{})
+}
+
+func (cmd *Wait) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["instances"] = &cliFlags.IntFlag{Name: "instances", Usage: T("Number of instances that must be running (Default: 1)")}
+	fs["timeout"] = &cliFlags.IntFlag{Name: "timeout", Usage: T("Maximum time to wait in seconds")}
+
+	baseUsage := T(`CF_NAME wait app-running APP_NAME [--instances N] [--timeout SECONDS]
+   CF_NAME wait app-staged APP_NAME [--timeout SECONDS]
+   CF_NAME wait service-ready SERVICE_INSTANCE [--timeout SECONDS]
+
+   Exits with 1 when the app or service instance fails and with 2 when the timeout is reached.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME wait app-running my-app --instances 3 --timeout 300
+   CF_NAME wait service-ready my-db`)
+
+	return command_registry.CommandMetadata{
+		Name:        "wait",
+		Description: T("Wait for an app or service instance to reach a state"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *Wait) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires a state and a name as arguments\n\n") + command_registry.Commands.CommandUsage("wait"))
+	}
+
+	state := fc.Args()[0]
+	if state != "app-running" && state != "app-staged" && state != "service-ready" {
+		cmd.ui.Failed(T("Incorrect Usage. State must be app-running, app-staged or service-ready\n\n") + command_registry.Commands.CommandUsage("wait"))
+	}
+
+	if fc.IsSet("instances") && state != "app-running" {
+		cmd.ui.Failed(T("Incorrect Usage. --instances can only be used with app-running\n\n") + command_registry.Commands.CommandUsage("wait"))
+	}
+
+	if fc.IsSet("instances") && fc.Int("instances") < 1 {
+		cmd.ui.Failed(T("Incorrect Usage. --instances must be at least 1\n\n") + command_registry.Commands.CommandUsage("wait"))
+	}
+
+	if fc.Int("timeout") < 0 {
+		cmd.ui.Failed(T("Incorrect Usage. --timeout cannot be negative\n\n") + command_registry.Commands.CommandUsage("wait"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+
+	if state != "service-ready" {
+		cmd.appReq = requirementsFactory.NewApplicationRequirement(fc.Args()[1])
+		reqs = append(reqs, cmd.appReq)
+	}
+
+	return
+}
+
+func (cmd *Wait) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.runWaiter = newAppRunWaiter(deps)
+	return cmd
+}
+
+func (cmd *Wait) Execute(c flags.FlagContext) {
+	timeout := time.Duration(-1)
+	if c.IsSet("timeout") {
+		timeout = time.Duration(c.Int("timeout")) * time.Second
+	}
+
+	var err error
+	switch c.Args()[0] {
+	case "app-running":
+		instances := 1
+		if c.IsSet("instances") {
+			instances = c.Int("instances")
+		}
+		err = cmd.waitForAppRunning(cmd.appReq.GetApplication(), instances, timeout)
+	case "app-staged":
+		err = cmd.waitForAppStaged(cmd.appReq.GetApplication(), timeout)
+	case "service-ready":
+		err = cmd.waitForServiceReady(c.Args()[1], timeout)
+	}
+
+	if err == nil {
+		cmd.ui.Ok()
+		return
+	}
+
+	switch err.(type) {
+	case *waitTimeoutError, *service.LastOperationTimeoutError:
+		cmd.ui.Say(terminal.FailureColor(T("FAILED")))
+		cmd.ui.Say(T("Timed out: {{.Err}}", map[string]interface{}{"Err": err.Error()}))
+		panic(terminal.ExitCode(WaitTimeoutExitCode))
+	default:
+		cmd.ui.Failed(err.Error())
+	}
+}
+
+// waitForAppRunning waits for the app to be staged and for instances of it to
+// be running. A negative timeout selects the usual staging and startup
+// timeouts; otherwise timeout bounds the whole wait.
+func (cmd *Wait) waitForAppRunning(app models.Application, instances int, timeout time.Duration) error {
+	cmd.ui.Say(T("Waiting for {{.Count}} instances of app {{.AppName}} to be running in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		cmd.targetArgs(map[string]interface{}{"Count": instances, "AppName": terminal.EntityNameColor(app.Name)})))
+
+	if app.State == "stopped" {
+		return errors.New(T("App {{.AppName}} is stopped", map[string]interface{}{"AppName": app.Name}))
+	}
+
+	if instances > app.InstanceCount {
+		return errors.New(T("App {{.AppName}} has only {{.InstanceCount}} instances", map[string]interface{}{"AppName": app.Name, "InstanceCount": app.InstanceCount}))
+	}
+
+	stagingTimeout, startupTimeout := cmd.runWaiter.stagingTimeout, cmd.runWaiter.startupTimeout
+	if timeout >= 0 {
+		stagingTimeout, startupTimeout = timeout, timeout
+	}
+
+	startTime := time.Now()
+	_, err := cmd.runWaiter.waitForStaging(cmd.unstagedApp(app), stagingTimeout)
+	if err != nil {
+		return err
+	}
+
+	if timeout >= 0 {
+		startupTimeout = timeout - time.Since(startTime)
+	}
+
+	return cmd.runWaiter.waitForRunningInstances(app.Guid, instances, startupTimeout, nil)
+}
+
+func (cmd *Wait) waitForAppStaged(app models.Application, timeout time.Duration) error {
+	cmd.ui.Say(T("Waiting for app {{.AppName}} to be staged in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		cmd.targetArgs(map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)})))
+
+	if timeout < 0 {
+		timeout = cmd.runWaiter.stagingTimeout
+	}
+
+	_, err := cmd.runWaiter.waitForStaging(cmd.unstagedApp(app), timeout)
+	return err
+}
+
+// unstagedApp makes the waiter read the package state, because the state
+// the app was found with may belong to an earlier package.
+func (cmd *Wait) unstagedApp(app models.Application) models.Application {
+	app.PackageState = ""
+	return app
+}
+
+// waitForServiceReady waits for the last operation on a service instance to
+// finish. Instances without a last operation, like user-provided ones, are
+// ready right away.
+func (cmd *Wait) waitForServiceReady(name string, timeout time.Duration) error {
+	cmd.ui.Say(T("Waiting for service instance {{.ServiceName}} to be ready in org {{.OrgName}} / space {{.SpaceName}} as {{.Username}}...",
+		cmd.targetArgs(map[string]interface{}{"ServiceName": terminal.EntityNameColor(name)})))
+
+	if timeout < 0 {
+		timeout = service.DefaultLastOperationTimeout
+	}
+
+	return service.WaitForLastOperation(cmd.ui, cmd.serviceRepo, name, timeout, cmd.runWaiter.pingerThrottle)
+}
+
+// targetArgs adds the targeted org, space and user to the arguments of a
+// message.
+func (cmd *Wait) targetArgs(args map[string]interface{}) map[string]interface{} {
+	args["OrgName"] = terminal.EntityNameColor(cmd.config.OrganizationFields().Name)
+	args["SpaceName"] = terminal.EntityNameColor(cmd.config.SpaceFields().Name)
+	args["Username"] = terminal.EntityNameColor(cmd.config.Username())
+	return args
+}
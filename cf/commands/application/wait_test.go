@@ -0,0 +1,272 @@
+package application_test
+
+import (
+	testAppInstanaces "github.com/cloudfoundry/cli/cf/api/app_instances/fakes"
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	. "github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wait command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appRepo             *testApplication.FakeApplicationRepository
+		appInstancesRepo    *testAppInstanaces.FakeAppInstancesRepository
+		serviceRepo         *testapi.FakeServiceRepo
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("wait").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		app := models.Application{}
+		app.Name = "my-app"
+		app.Guid = "my-app-guid"
+		app.State = "started"
+		app.InstanceCount = 2
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true, Application: app}
+
+		appRepo = &testApplication.FakeApplicationRepository{}
+		appRepo.GetAppReturns(models.Application{ApplicationFields: models.ApplicationFields{PackageState: "STAGED"}}, nil)
+		appInstancesRepo = &testAppInstanaces.FakeAppInstancesRepository{}
+		appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceRunning}}, nil)
+		serviceRepo = &testapi.FakeServiceRepo{}
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("wait", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	runCommandForExitCode := func(args ...string) (exitCode interface{}) {
+		defer func() {
+			exitCode = recover()
+		}()
+		runCommand(args...)
+		return
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("app-running", "my-app")).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("app-running", "my-app")).To(BeFalse())
+		})
+
+		It("requires the app for app states", func() {
+			Expect(runCommand("app-staged", "my-app")).To(BeTrue())
+			Expect(requirementsFactory.ApplicationName).To(Equal("my-app"))
+		})
+
+		It("fails with usage when not given a state and a name", func() {
+			runCommand("app-running")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires a state and a name"},
+			))
+		})
+
+		It("fails with usage when given an unknown state", func() {
+			runCommand("app-stopped", "my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "app-running, app-staged or service-ready"},
+			))
+		})
+
+		It("fails with usage when given --instances for another state", func() {
+			runCommand("app-staged", "my-app", "--instances", "2")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--instances can only be used with app-running"},
+			))
+		})
+	})
+
+	Describe("app-running", func() {
+		It("waits for the app to be staged and an instance to be running", func() {
+			runCommand("app-running", "my-app")
+
+			Expect(appRepo.GetAppArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(appInstancesRepo.GetInstancesArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Waiting for 1 instances of app", "my-app", "to be running", "my-org", "my-space", "my-user"},
+				[]string{"OK"},
+			))
+		})
+
+		It("waits for the given number of instances", func() {
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+				{State: models.InstanceRunning},
+				{State: models.InstanceStarting},
+			}, nil)
+
+			exitCode := runCommandForExitCode("app-running", "my-app", "--instances", "2", "--timeout", "0")
+
+			Expect(exitCode).To(Equal(terminal.ExitCode(WaitTimeoutExitCode)))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Timed out", "Start app timeout"},
+			))
+		})
+
+		It("fails when the app has fewer instances than requested", func() {
+			runCommand("app-running", "my-app", "--instances", "3")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"App my-app has only 2 instances"},
+			))
+		})
+
+		It("fails when the app is stopped", func() {
+			requirementsFactory.Application.State = "stopped"
+
+			runCommand("app-running", "my-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"App my-app is stopped"},
+			))
+		})
+
+		It("fails when the instances crash", func() {
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{{State: models.InstanceCrashed}}, nil)
+
+			Expect(runCommandForExitCode("app-running", "my-app")).To(BeNil())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Start unsuccessful"},
+			))
+		})
+
+		It("fails like start when an instance crashes before enough instances are running", func() {
+			appInstancesRepo.GetInstancesReturns([]models.AppInstanceFields{
+				{State: models.InstanceRunning},
+				{State: models.InstanceCrashed},
+			}, nil)
+
+			Expect(runCommandForExitCode("app-running", "my-app", "--instances", "2")).To(BeNil())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Start unsuccessful"},
+			))
+		})
+	})
+
+	Describe("app-staged", func() {
+		It("waits for the package of the app to be staged", func() {
+			runCommand("app-staged", "my-app")
+
+			Expect(appRepo.GetAppArgsForCall(0)).To(Equal("my-app-guid"))
+			Expect(appInstancesRepo.GetInstancesCallCount()).To(Equal(0))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Waiting for app", "my-app", "to be staged"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails with the reason when staging fails", func() {
+			appRepo.GetAppReturns(models.Application{ApplicationFields: models.ApplicationFields{PackageState: "FAILED", StagingFailedReason: "NoAppDetectedError"}}, nil)
+
+			runCommand("app-staged", "my-app")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Staging failed", "NoAppDetectedError"},
+			))
+		})
+
+		It("exits with the timeout exit code when staging does not finish in time", func() {
+			appRepo.GetAppReturns(models.Application{ApplicationFields: models.ApplicationFields{PackageState: "PENDING"}}, nil)
+
+			exitCode := runCommandForExitCode("app-staged", "my-app", "--timeout", "0")
+
+			Expect(exitCode).To(Equal(terminal.ExitCode(WaitTimeoutExitCode)))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Timed out", "Staging did not finish"},
+			))
+		})
+	})
+
+	Describe("service-ready", func() {
+		var instance models.ServiceInstance
+
+		BeforeEach(func() {
+			instance = models.ServiceInstance{}
+			instance.Name = "my-db"
+			instance.LastOperation = models.LastOperationFields{Type: "create", State: "succeeded"}
+		})
+
+		It("succeeds when the last operation succeeded", func() {
+			serviceRepo.FindInstanceByNameServiceInstance = instance
+
+			runCommand("service-ready", "my-db")
+
+			Expect(serviceRepo.FindInstanceByNameName).To(Equal("my-db"))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Waiting for service instance", "my-db", "to be ready"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails when the last operation failed", func() {
+			instance.LastOperation.State = "failed"
+			instance.LastOperation.Description = "out of capacity"
+			serviceRepo.FindInstanceByNameServiceInstance = instance
+
+			Expect(runCommandForExitCode("service-ready", "my-db")).To(BeNil())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"create operation", "my-db", "failed", "out of capacity"},
+			))
+		})
+
+		It("exits with the timeout exit code when the operation does not finish in time", func() {
+			instance.LastOperation.State = "in progress"
+			serviceRepo.FindInstanceByNameServiceInstance = instance
+
+			exitCode := runCommandForExitCode("service-ready", "my-db", "--timeout", "0")
+
+			Expect(exitCode).To(Equal(terminal.ExitCode(WaitTimeoutExitCode)))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Timed out", "create operation", "my-db", "did not finish"},
+			))
+		})
+
+		It("fails when the service instance does not exist", func() {
+			serviceRepo.FindInstanceByNameNotFound = true
+
+			runCommand("service-ready", "my-db")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"my-db", "not found"},
+			))
+		})
+	})
+})
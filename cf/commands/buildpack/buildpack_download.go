@@ -0,0 +1,162 @@
+package buildpack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/configuration/config_helpers"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+// verified downloads are kept in CF_HOME/.cf/buildpacks/SHA256/FILENAME, so
+// uploading a release that was already downloaded does not fetch it again
+var buildpackCacheDir = func() string {
+	return filepath.Join(filepath.Dir(config_helpers.DefaultFilePath()), "buildpacks")
+}
+
+func validateSha256Flag(buildpackPath string, expectedSha256 string) error {
+	if expectedSha256 == "" {
+		return nil
+	}
+
+	if !isWebURL(buildpackPath) {
+		return errors.New(T("--sha256 can only be used when the path is a URL"))
+	}
+
+	if decoded, err := hex.DecodeString(expectedSha256); err != nil || len(decoded) != sha256.Size {
+		return errors.New(T("--sha256 must be a 64 character hex encoded checksum"))
+	}
+
+	return nil
+}
+
+// fetchBuildpack returns a local path to upload for the buildpack at
+// buildpackPath, and a func that removes what was only downloaded for the
+// upload. URLs are downloaded through bitsRepo; a download verified against
+// expectedSha256 is cached, since the checksum identifies it, while an
+// unverified one cannot be reused and is removed after the upload. Other
+// paths are made absolute.
+func fetchBuildpack(ui terminal.UI, bitsRepo api.BuildpackBitsRepository, buildpackPath string, expectedSha256 string) (string, func(), error) {
+	noCleanup := func() {}
+	if !isWebURL(buildpackPath) {
+		path, err := filepath.Abs(buildpackPath)
+		return path, noCleanup, err
+	}
+
+	expectedSha256 = strings.ToLower(expectedSha256)
+	cacheDir := buildpackCacheDir()
+
+	if expectedSha256 != "" {
+		if cached, ok := cachedBuildpack(filepath.Join(cacheDir, expectedSha256), expectedSha256); ok {
+			ui.Say(T("Using cached buildpack {{.Path}}", map[string]interface{}{"Path": terminal.EntityNameColor(cached)}))
+			return cached, noCleanup, nil
+		}
+	}
+
+	ui.Say(T("Downloading buildpack from {{.URL}}...", map[string]interface{}{"URL": terminal.EntityNameColor(buildpackPath)}))
+
+	var downloadDir string
+	var err error
+	if expectedSha256 == "" {
+		downloadDir, err = ioutil.TempDir("", "buildpack-download")
+	} else {
+		err = os.MkdirAll(cacheDir, 0700)
+		if err == nil {
+			downloadDir, err = ioutil.TempDir(cacheDir, "download")
+		}
+	}
+	if err != nil {
+		return "", noCleanup, errors.NewWithError(T("Couldn't create buildpack download directory"), err)
+	}
+	cleanup := func() { os.RemoveAll(downloadDir) }
+
+	size, fileName, err := bitsRepo.DownloadBuildpack(buildpackPath, downloadDir)
+	if err != nil {
+		cleanup()
+		return "", noCleanup, errors.NewWithError(T("Error downloading buildpack from {{.URL}}", map[string]interface{}{"URL": buildpackPath}), err)
+	}
+
+	downloadedPath := filepath.Join(downloadDir, fileName)
+	actualSha256, err := sha256File(downloadedPath)
+	if err != nil {
+		cleanup()
+		return "", noCleanup, errors.NewWithError(T("Couldn't read downloaded buildpack"), err)
+	}
+
+	ui.Say(T("Downloaded {{.FileName}} ({{.Size}}, sha256 {{.Sha256}})", map[string]interface{}{
+		"FileName": terminal.EntityNameColor(fileName),
+		"Size":     formatters.ByteSize(size),
+		"Sha256":   actualSha256,
+	}))
+
+	if expectedSha256 == "" {
+		ui.Warn(T("The downloaded buildpack was not verified, use --sha256 to verify it"))
+		return downloadedPath, cleanup, nil
+	}
+
+	defer cleanup()
+	if actualSha256 != expectedSha256 {
+		return "", noCleanup, errors.New(T("Checksum mismatch for {{.URL}}: expected sha256 {{.Expected}} but got {{.Actual}}", map[string]interface{}{
+			"URL":      buildpackPath,
+			"Expected": expectedSha256,
+			"Actual":   actualSha256,
+		}))
+	}
+	ui.Say(T("Verified sha256 checksum"))
+
+	cachedDir := filepath.Join(cacheDir, actualSha256)
+	os.RemoveAll(cachedDir)
+	err = os.MkdirAll(cachedDir, 0700)
+	if err != nil {
+		return "", noCleanup, errors.NewWithError(T("Couldn't create buildpack cache directory"), err)
+	}
+
+	cachedPath := filepath.Join(cachedDir, fileName)
+	err = os.Rename(downloadedPath, cachedPath)
+	if err != nil {
+		return "", noCleanup, errors.NewWithError(T("Couldn't cache downloaded buildpack"), err)
+	}
+
+	return cachedPath, noCleanup, nil
+}
+
+func cachedBuildpack(dir string, expectedSha256 string) (string, bool) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil || len(files) != 1 || files[0].IsDir() {
+		return "", false
+	}
+
+	cachedPath := filepath.Join(dir, files[0].Name())
+	actualSha256, err := sha256File(cachedPath)
+	if err != nil || actualSha256 != expectedSha256 {
+		return "", false
+	}
+
+	return cachedPath, true
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
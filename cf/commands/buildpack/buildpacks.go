@@ -50,7 +50,7 @@ func (cmd *ListBuildpacks) SetDependency(deps command_registry.Dependency, plugi
 func (cmd *ListBuildpacks) Execute(c flags.FlagContext) {
 	cmd.ui.Say(T("Getting buildpacks...\n"))
 
-	table := cmd.ui.Table([]string{"buildpack", T("position"), T("enabled"), T("locked"), T("filename")})
+	table := cmd.ui.Table([]string{"buildpack", T("position"), T("enabled"), T("locked"), T("filename"), T("checksum")})
 	noBuildpacks := true
 
 	apiErr := cmd.buildpackRepo.ListBuildpacks(func(buildpack models.Buildpack) bool {
@@ -72,6 +72,7 @@ func (cmd *ListBuildpacks) Execute(c flags.FlagContext) {
 			enabled,
 			locked,
 			buildpack.Filename,
+			buildpackChecksum(buildpack),
 		)
 		noBuildpacks = false
 		return true
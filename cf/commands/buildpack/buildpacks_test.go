@@ -78,6 +78,19 @@ var _ = Describe("ListBuildpacks", func() {
 			))
 		})
 
+		It("shows the file name and checksum of the uploaded bits", func() {
+			buildpackRepo.Buildpacks = []models.Buildpack{
+				models.Buildpack{Guid: "bp-guid", Name: "Buildpack-1", Filename: "bp-v1.zip", Key: "bp-guid_0123abcd"},
+			}
+
+			runCommand()
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"buildpack", "filename", "checksum"},
+				[]string{"Buildpack-1", "bp-v1.zip", "0123abcd"},
+			))
+		})
+
 		It("tells the user if no build packs exist", func() {
 			runCommand()
 			Expect(ui.Outputs).To(ContainSubstrings(
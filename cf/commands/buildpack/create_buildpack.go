@@ -1,7 +1,6 @@
 package buildpack
 
 import (
-	"path/filepath"
 	"strconv"
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -31,12 +30,14 @@ func (cmd *CreateBuildpack) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["enable"] = &cliFlags.BoolFlag{Name: "enable", Usage: T("Enable the buildpack to be used for staging")}
 	fs["disable"] = &cliFlags.BoolFlag{Name: "disable", Usage: T("Disable the buildpack from being used for staging")}
+	fs["sha256"] = &cliFlags.StringFlag{Name: "sha256", Usage: T("Verify the buildpack downloaded from a url against this sha256 checksum")}
 
 	return command_registry.CommandMetadata{
 		Name:        "create-buildpack",
 		Description: T("Create a buildpack"),
-		Usage: T("CF_NAME create-buildpack BUILDPACK PATH POSITION [--enable|--disable]") +
-			T("\n\nTIP:\n") + T("   Path should be a zip file, a url to a zip file, or a local directory. Position is a positive integer, sets priority, and is sorted from lowest to highest."),
+		Usage: T("CF_NAME create-buildpack BUILDPACK PATH POSITION [--enable|--disable] [--sha256 CHECKSUM]") +
+			T("\n\nTIP:\n") + T("   Path should be a zip file, a url to a zip file, or a local directory. Position is a positive integer, sets priority, and is sorted from lowest to highest.") +
+			T("\n   Buildpacks downloaded from a url and verified with --sha256 are cached by checksum in CF_HOME and not downloaded again."),
 		Flags:     fs,
 		TotalArgs: 3,
 	}
@@ -47,6 +48,10 @@ func (cmd *CreateBuildpack) Requirements(requirementsFactory requirements.Factor
 		cmd.ui.Failed(T("Incorrect Usage. Requires buildpack_name, path and position as arguments\n\n") + command_registry.Commands.CommandUsage("create-buildpack"))
 	}
 
+	if err := validateSha256Flag(fc.Args()[1], fc.String("sha256")); err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
 	reqs = []requirements.Requirement{
 		requirementsFactory.NewLoginRequirement(),
 	}
@@ -64,6 +69,13 @@ func (cmd *CreateBuildpack) SetDependency(deps command_registry.Dependency, plug
 func (cmd *CreateBuildpack) Execute(c flags.FlagContext) {
 	buildpackName := c.Args()[0]
 
+	dir, cleanup, err := fetchBuildpack(cmd.ui, cmd.buildpackBitsRepo, c.Args()[1], c.String("sha256"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+		return
+	}
+	defer cleanup()
+
 	cmd.ui.Say(T("Creating buildpack {{.BuildpackName}}...", map[string]interface{}{"BuildpackName": terminal.EntityNameColor(buildpackName)}))
 
 	buildpack, err := cmd.createBuildpack(buildpackName, c)
@@ -83,12 +95,6 @@ func (cmd *CreateBuildpack) Execute(c flags.FlagContext) {
 
 	cmd.ui.Say(T("Uploading buildpack {{.BuildpackName}}...", map[string]interface{}{"BuildpackName": terminal.EntityNameColor(buildpackName)}))
 
-	dir, err := filepath.Abs(c.Args()[1])
-	if err != nil {
-		cmd.ui.Failed(err.Error())
-		return
-	}
-
 	err = cmd.buildpackBitsRepo.UploadBuildpack(buildpack, dir)
 	if err != nil {
 		cmd.ui.Failed(err.Error())
@@ -1,10 +1,16 @@
 package buildpack_test
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/cloudfoundry/cli/cf"
 	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/errors"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
 	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
@@ -100,4 +106,110 @@ var _ = Describe("create-buildpack command", func() {
 			[]string{"FAILED"},
 		))
 	})
+
+	Context("when the path is a url", func() {
+		var (
+			cfHome        string
+			oldCfHome     string
+			bitsSha256    string
+			cachedZipPath string
+		)
+
+		BeforeEach(func() {
+			bitsRepo.DownloadBuildpackContents = "buildpack bits"
+			bitsSha256 = fmt.Sprintf("%x", sha256.Sum256([]byte("buildpack bits")))
+
+			var err error
+			cfHome, err = ioutil.TempDir("", "cf-home")
+			Expect(err).NotTo(HaveOccurred())
+			oldCfHome = os.Getenv("CF_HOME")
+			os.Setenv("CF_HOME", cfHome)
+
+			cachedZipPath = filepath.Join(cfHome, ".cf", "buildpacks", bitsSha256, "my-buildpack.zip")
+		})
+
+		AfterEach(func() {
+			os.Setenv("CF_HOME", oldCfHome)
+			os.RemoveAll(cfHome)
+		})
+
+		It("downloads the buildpack, verifies it and uploads the download", func() {
+			testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "https://example.com/my-buildpack.zip", "5", "--sha256", bitsSha256}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(bitsRepo.UploadBuildpackPath).To(Equal(cachedZipPath))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Downloading buildpack from", "https://example.com/my-buildpack.zip"},
+				[]string{"Downloaded", "my-buildpack.zip", bitsSha256},
+				[]string{"Verified sha256 checksum"},
+				[]string{"Creating buildpack", "my-buildpack"},
+				[]string{"Uploading buildpack", "my-buildpack"},
+				[]string{"OK"},
+			))
+		})
+
+		It("uses the cached download when the checksum matches", func() {
+			args := []string{"my-buildpack", "https://example.com/my-buildpack.zip", "5", "--sha256", bitsSha256}
+			testcmd.RunCliCommand("create-buildpack", args, requirementsFactory, updateCommandDependency, false)
+			ui = &testterm.FakeUI{}
+			testcmd.RunCliCommand("create-buildpack", args, requirementsFactory, updateCommandDependency, false)
+
+			Expect(bitsRepo.DownloadBuildpackUrls).To(Equal([]string{"https://example.com/my-buildpack.zip"}))
+			Expect(bitsRepo.UploadBuildpackPath).To(Equal(cachedZipPath))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Using cached buildpack", cachedZipPath}))
+		})
+
+		It("warns when the download is not verified and does not cache it", func() {
+			testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "https://example.com/my-buildpack.zip", "5"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(bitsRepo.UploadBuildpackPath).To(HaveSuffix("my-buildpack.zip"))
+			Expect(bitsRepo.UploadBuildpackPath).NotTo(HavePrefix(cfHome))
+			Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"not verified", "--sha256"}))
+
+			_, err := os.Stat(bitsRepo.UploadBuildpackPath)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(filepath.Join(cfHome, ".cf", "buildpacks"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("fails without creating the buildpack when the checksum does not match", func() {
+			wrongSha256 := strings.Repeat("0", 64)
+			testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "https://example.com/my-buildpack.zip", "5", "--sha256", wrongSha256}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(repo.CreateBuildpack.Name).To(BeEmpty())
+			Expect(bitsRepo.UploadBuildpackPath).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Checksum mismatch", "expected sha256 " + wrongSha256, "got " + bitsSha256},
+			))
+		})
+
+		It("fails when the download fails", func() {
+			bitsRepo.DownloadBuildpackErr = errors.New("connection refused")
+			testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "https://example.com/my-buildpack.zip", "5"}, requirementsFactory, updateCommandDependency, false)
+
+			Expect(repo.CreateBuildpack.Name).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error downloading buildpack from", "https://example.com/my-buildpack.zip"},
+			))
+		})
+	})
+
+	It("fails when --sha256 is given with a local path", func() {
+		testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "my.zip", "5", "--sha256", strings.Repeat("0", 64)}, requirementsFactory, updateCommandDependency, false)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"--sha256 can only be used when the path is a URL"},
+		))
+	})
+
+	It("fails when --sha256 is not a sha256 checksum", func() {
+		testcmd.RunCliCommand("create-buildpack", []string{"my-buildpack", "https://example.com/my.zip", "5", "--sha256", "abc"}, requirementsFactory, updateCommandDependency, false)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"--sha256 must be a 64 character hex encoded checksum"},
+		))
+	})
 })
@@ -0,0 +1,571 @@
+package buildpack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/config_helpers"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+	"gopkg.in/yaml.v2"
+)
+
+// buildpacksFile is the document read by sync-buildpacks.
+type buildpacksFile struct {
+	Buildpacks []buildpackDeclaration `yaml:"buildpacks"`
+}
+
+type buildpackDeclaration struct {
+	Name     string `yaml:"name"`
+	Path     string `yaml:"path"`
+	Position *int   `yaml:"position"`
+	Enabled  *bool  `yaml:"enabled"`
+	Locked   *bool  `yaml:"locked"`
+}
+
+// buildpackSync is the change planned for a declared buildpack.
+type buildpackSync struct {
+	declaration  buildpackDeclaration
+	existing     *models.Buildpack
+	location     string
+	upload       bool
+	uploadReason string
+}
+
+// buildpackSource is the url the bits of a buildpack were last uploaded from.
+// The Cloud Controller only keeps the file name, so sync-buildpacks records
+// the urls it uploads, keyed by buildpack guid, in its sources file.
+type buildpackSource struct {
+	Url      string `json:"url"`
+	Filename string `json:"filename"`
+}
+
+type SyncBuildpacks struct {
+	ui                terminal.UI
+	buildpackRepo     api.BuildpackRepository
+	buildpackBitsRepo api.BuildpackBitsRepository
+	sourcesPath       string
+	sources           map[string]buildpackSource
+}
+
+func init() {
+	command_registry.Register(&SyncBuildpacks{})
+}
+
+func (cmd *SyncBuildpacks) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &cliFlags.StringFlag{Name: "f", Usage: T("Path to the buildpacks file")}
+
+	baseUsage := T(`CF_NAME sync-buildpacks -f BUILDPACKS_FILE
+
+   Creates and updates the buildpacks declared in the file. The bits of a buildpack are only
+   uploaded when the checksum of its zip file differs from the uploaded one, or its url differs
+   from the one it was last synced from on this machine; directories are always uploaded.
+   Positions are applied last, in ascending order, once every buildpack is up to date; the
+   Cloud Controller moves one buildpack at a time, so if a move fails the buildpacks already
+   moved are moved back to their original positions. Buildpacks that are not declared are
+   left alone. The whole file, including the positions, is checked before anything is
+   changed.`)
+	exampleUsage := T(`EXAMPLE BUILDPACKS FILE:
+   buildpacks:
+   - name: ruby_buildpack
+     path: ./ruby_buildpack-cached-v1.6.0.zip
+     position: 1
+   - name: go_buildpack
+     path: https://example.com/go_buildpack-cached-v1.5.0.zip
+     position: 2
+     enabled: true
+     locked: false`)
+
+	return command_registry.CommandMetadata{
+		Name:        "sync-buildpacks",
+		Description: T("Create, update and order buildpacks as declared in a file"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *SyncBuildpacks) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 0 || fc.String("f") == "" {
+		cmd.ui.Failed(T("Incorrect Usage. Requires the -f flag and no argument\n\n") + command_registry.Commands.CommandUsage("sync-buildpacks"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return
+}
+
+func (cmd *SyncBuildpacks) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.buildpackRepo = deps.RepoLocator.GetBuildpackRepository()
+	cmd.buildpackBitsRepo = deps.RepoLocator.GetBuildpackBitsRepository()
+	cmd.sourcesPath = filepath.Join(filepath.Dir(config_helpers.DefaultFilePath()), "buildpack_sources.json")
+	return cmd
+}
+
+func (cmd *SyncBuildpacks) Execute(c flags.FlagContext) {
+	filePath := c.String("f")
+
+	cmd.ui.Say(T("Syncing buildpacks from {{.Path}}...", map[string]interface{}{"Path": terminal.EntityNameColor(filePath)}))
+
+	declarations, err := readBuildpacksFile(filePath)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	current, err := cmd.listBuildpacks()
+	if err != nil {
+		cmd.ui.Failed(T("Failed fetching buildpacks.\n{{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	cmd.sources = readBuildpackSources(cmd.sourcesPath)
+
+	plan, err := planBuildpackSync(declarations, current, cmd.sources, filepath.Dir(filePath))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	table := terminal.NewTable(cmd.ui, []string{T("buildpack"), T("changes")})
+	for _, sync := range plan {
+		changes, err := cmd.apply(sync)
+		if err != nil {
+			cmd.ui.Failed(T("Failed syncing buildpack {{.BuildpackName}}: {{.Error}}",
+				map[string]interface{}{"BuildpackName": sync.declaration.Name, "Error": err.Error()}))
+		}
+		if len(changes) == 0 {
+			changes = []string{T("unchanged")}
+		}
+		table.Add(sync.declaration.Name, strings.Join(changes, ", "))
+	}
+
+	moved, err := cmd.reorder(declarations)
+	if err != nil {
+		cmd.ui.Failed(T("Failed ordering buildpacks: {{.Error}}", map[string]interface{}{"Error": err.Error()}))
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+	table.Print()
+
+	if len(moved) > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Say(T("Moved {{.Buildpacks}}", map[string]interface{}{"Buildpacks": strings.Join(moved, ", ")}))
+	}
+}
+
+func readBuildpacksFile(filePath string) ([]buildpackDeclaration, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file := buildpacksFile{}
+	err = yaml.Unmarshal(contents, &file)
+	if err != nil {
+		return nil, errors.New(T("Error reading {{.Path}}: {{.Err}}", map[string]interface{}{"Path": filePath, "Err": err.Error()}))
+	}
+
+	return file.Buildpacks, nil
+}
+
+func readBuildpackSources(sourcesPath string) map[string]buildpackSource {
+	sources := map[string]buildpackSource{}
+	contents, err := ioutil.ReadFile(sourcesPath)
+	if err != nil || json.Unmarshal(contents, &sources) != nil {
+		return map[string]buildpackSource{}
+	}
+	return sources
+}
+
+// saveBuildpackSources is best effort; without the sources file the buildpacks
+// synced from urls are only uploaded again.
+func saveBuildpackSources(sourcesPath string, sources map[string]buildpackSource) {
+	contents, err := json.Marshal(sources)
+	if err != nil {
+		return
+	}
+
+	if os.MkdirAll(filepath.Dir(sourcesPath), 0700) != nil {
+		return
+	}
+
+	ioutil.WriteFile(sourcesPath, contents, 0600)
+}
+
+func (cmd *SyncBuildpacks) listBuildpacks() (map[string]models.Buildpack, error) {
+	buildpacks := map[string]models.Buildpack{}
+	err := cmd.buildpackRepo.ListBuildpacks(func(buildpack models.Buildpack) bool {
+		buildpacks[buildpack.Name] = buildpack
+		return true
+	})
+	return buildpacks, err
+}
+
+// planBuildpackSync checks every declaration against the current buildpacks
+// and decides which bits to upload, so that nothing is changed when any of
+// the declarations is invalid.
+func planBuildpackSync(declarations []buildpackDeclaration, current map[string]models.Buildpack, sources map[string]buildpackSource, baseDir string) ([]buildpackSync, error) {
+	problems := []string{}
+	names := map[string]bool{}
+	positions := map[int]string{}
+	plan := []buildpackSync{}
+
+	for i, declaration := range declarations {
+		if declaration.Name == "" {
+			problems = append(problems, T("buildpack {{.Index}} has no name", map[string]interface{}{"Index": i + 1}))
+			continue
+		}
+		if names[declaration.Name] {
+			problems = append(problems, T("buildpack {{.BuildpackName}} is declared more than once", map[string]interface{}{"BuildpackName": declaration.Name}))
+			continue
+		}
+		names[declaration.Name] = true
+
+		if declaration.Position != nil {
+			position := *declaration.Position
+			if position < 1 {
+				problems = append(problems, T("buildpack {{.BuildpackName}} has position {{.Position}}, positions start at 1",
+					map[string]interface{}{"BuildpackName": declaration.Name, "Position": position}))
+			} else if other, ok := positions[position]; ok {
+				problems = append(problems, T("buildpacks {{.Other}} and {{.BuildpackName}} both have position {{.Position}}",
+					map[string]interface{}{"Other": other, "BuildpackName": declaration.Name, "Position": position}))
+			} else {
+				positions[position] = declaration.Name
+			}
+		}
+
+		sync := buildpackSync{declaration: declaration}
+		if existing, ok := current[declaration.Name]; ok {
+			sync.existing = &existing
+		}
+
+		if declaration.Path == "" {
+			if sync.existing == nil {
+				problems = append(problems, T("buildpack {{.BuildpackName}} does not exist and has no path", map[string]interface{}{"BuildpackName": declaration.Name}))
+			}
+			plan = append(plan, sync)
+			continue
+		}
+
+		upload, reason, location, err := needsUpload(declaration.Path, baseDir, sync.existing, sources)
+		if err != nil {
+			problems = append(problems, T("buildpack {{.BuildpackName}}: {{.Error}}", map[string]interface{}{"BuildpackName": declaration.Name, "Error": err.Error()}))
+			continue
+		}
+		sync.upload, sync.uploadReason, sync.location = upload, reason, location
+
+		if upload && sync.existing != nil && isTrue(sync.existing.Locked) && (declaration.Locked == nil || *declaration.Locked) {
+			problems = append(problems, T("buildpack {{.BuildpackName}} is locked, set 'locked: false' to update its bits", map[string]interface{}{"BuildpackName": declaration.Name}))
+		}
+
+		plan = append(plan, sync)
+	}
+
+	count := len(current)
+	for _, sync := range plan {
+		if sync.existing == nil {
+			count++
+		}
+	}
+	sortedPositions := []int{}
+	for position := range positions {
+		sortedPositions = append(sortedPositions, position)
+	}
+	sort.Ints(sortedPositions)
+	for _, position := range sortedPositions {
+		if position > count {
+			problems = append(problems, T("buildpack {{.BuildpackName}} has position {{.Position}}, but there will only be {{.Count}} buildpacks",
+				map[string]interface{}{"BuildpackName": positions[position], "Position": position, "Count": count}))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(T("Invalid buildpacks file:\n{{.Problems}}", map[string]interface{}{"Problems": "  " + strings.Join(problems, "\n  ")}))
+	}
+	return plan, nil
+}
+
+// needsUpload compares the bits at buildpackPath with the uploaded ones. Zip
+// files are compared by the checksum of the archive UploadBuildpack uploads,
+// urls with the recorded source of the uploaded bits, and directories are
+// always uploaded since zipping them is not reproducible.
+func needsUpload(buildpackPath, baseDir string, existing *models.Buildpack, sources map[string]buildpackSource) (upload bool, reason string, location string, err error) {
+	if isWebURL(buildpackPath) {
+		location = buildpackPath
+		if existing == nil || existing.Filename == "" {
+			return true, T("new"), location, nil
+		}
+
+		source, ok := sources[existing.Guid]
+		switch {
+		case !ok || source.Filename != existing.Filename:
+			return true, T("source unknown"), location, nil
+		case source.Url != buildpackPath:
+			return true, T("url changed"), location, nil
+		}
+		return false, "", location, nil
+	}
+
+	location = buildpackPath
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(baseDir, location)
+	}
+
+	stat, err := os.Stat(location)
+	if err != nil {
+		return false, "", "", err
+	}
+
+	switch {
+	case existing == nil || existing.Key == "":
+		return true, T("new"), location, nil
+	case stat.IsDir():
+		return true, T("directory"), location, nil
+	}
+
+	checksum, err := api.BuildpackArchiveChecksum(location)
+	if err != nil {
+		return false, "", "", err
+	}
+	if checksum != buildpackChecksum(*existing) {
+		return true, T("checksum changed"), location, nil
+	}
+	return false, "", location, nil
+}
+
+// apply creates or updates a buildpack, uploading its bits when planned, and
+// returns the changes made. The plan only uploads the bits of a locked
+// buildpack that is declared unlocked, so it is unlocked before the upload.
+func (cmd *SyncBuildpacks) apply(sync buildpackSync) ([]string, error) {
+	declaration := sync.declaration
+	changes := []string{}
+
+	buildpack := models.Buildpack{}
+	if sync.existing == nil {
+		cmd.ui.Say(T("Creating buildpack {{.BuildpackName}}...", map[string]interface{}{"BuildpackName": terminal.EntityNameColor(declaration.Name)}))
+		created, err := cmd.buildpackRepo.Create(declaration.Name, nil, declaration.Enabled, nil)
+		if err != nil {
+			return changes, err
+		}
+		buildpack = created
+		changes = append(changes, T("created"))
+	} else {
+		buildpack = *sync.existing
+		update := models.Buildpack{Guid: buildpack.Guid, Name: buildpack.Name}
+		if declaration.Enabled != nil && isTrue(buildpack.Enabled) != *declaration.Enabled {
+			update.Enabled = declaration.Enabled
+			changes = append(changes, enabledChange(*declaration.Enabled))
+		}
+		if sync.upload && isTrue(buildpack.Locked) {
+			unlocked := false
+			update.Locked = &unlocked
+		}
+		if update.Enabled != nil || update.Locked != nil {
+			cmd.ui.Say(T("Updating buildpack {{.BuildpackName}}...", map[string]interface{}{"BuildpackName": terminal.EntityNameColor(declaration.Name)}))
+			_, err := cmd.buildpackRepo.Update(update)
+			if err != nil {
+				return changes, err
+			}
+			if update.Locked != nil {
+				buildpack.Locked = update.Locked
+				changes = append(changes, lockedChange(false))
+			}
+		}
+	}
+
+	if sync.upload {
+		cmd.ui.Say(T("Uploading buildpack {{.BuildpackName}} ({{.Reason}})...",
+			map[string]interface{}{"BuildpackName": terminal.EntityNameColor(declaration.Name), "Reason": sync.uploadReason}))
+		err := cmd.buildpackBitsRepo.UploadBuildpack(buildpack, sync.location)
+		if err != nil {
+			return changes, err
+		}
+		changes = append(changes, T("uploaded"))
+
+		if isWebURL(sync.location) {
+			cmd.sources[buildpack.Guid] = buildpackSource{Url: sync.location, Filename: path.Base(sync.location)}
+			saveBuildpackSources(cmd.sourcesPath, cmd.sources)
+		}
+	}
+
+	if declaration.Locked != nil && isTrue(buildpack.Locked) != *declaration.Locked {
+		cmd.ui.Say(T("Updating buildpack {{.BuildpackName}}...", map[string]interface{}{"BuildpackName": terminal.EntityNameColor(declaration.Name)}))
+		_, err := cmd.buildpackRepo.Update(models.Buildpack{Guid: buildpack.Guid, Name: buildpack.Name, Locked: declaration.Locked})
+		if err != nil {
+			return changes, err
+		}
+		changes = append(changes, lockedChange(*declaration.Locked))
+	}
+
+	return changes, nil
+}
+
+// buildpackMove puts a buildpack at a position. Applied moves record the
+// position the buildpack was moved from, so that they can be undone.
+type buildpackMove struct {
+	name     string
+	position int
+	previous int
+}
+
+// reorder moves the buildpacks with a declared position in ascending order of
+// position. The Cloud Controller shifts the other buildpacks down when one is
+// moved, so moving them in this order leaves each at its declared position.
+// The Cloud Controller cannot move several buildpacks at once, so when a move
+// fails the buildpacks already moved are moved back to their original
+// positions, and the error tells which moves were applied otherwise.
+func (cmd *SyncBuildpacks) reorder(declarations []buildpackDeclaration) ([]string, error) {
+	positioned := []buildpackDeclaration{}
+	for _, declaration := range declarations {
+		if declaration.Position != nil {
+			positioned = append(positioned, declaration)
+		}
+	}
+	if len(positioned) == 0 {
+		return nil, nil
+	}
+	sort.Sort(declarationsByPosition(positioned))
+
+	buildpacks := []models.Buildpack{}
+	err := cmd.buildpackRepo.ListBuildpacks(func(buildpack models.Buildpack) bool {
+		buildpacks = append(buildpacks, buildpack)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(buildpacksByPosition(buildpacks))
+
+	moves := []buildpackMove{}
+	for _, declaration := range positioned {
+		moves = append(moves, buildpackMove{name: declaration.Name, position: *declaration.Position})
+	}
+
+	buildpacks, applied, err := cmd.moveBuildpacks(buildpacks, moves)
+	moved := []string{}
+	for _, move := range applied {
+		moved = append(moved, T("{{.BuildpackName}} to position {{.Position}}",
+			map[string]interface{}{"BuildpackName": move.name, "Position": move.position}))
+	}
+	if err == nil || len(applied) == 0 {
+		return moved, err
+	}
+
+	undo := []buildpackMove{}
+	for i := len(applied) - 1; i >= 0; i-- {
+		undo = append(undo, buildpackMove{name: applied[i].name, position: applied[i].previous})
+	}
+	_, _, restoreErr := cmd.moveBuildpacks(buildpacks, undo)
+	if restoreErr != nil {
+		return nil, errors.New(T("{{.Error}}\nMoved {{.Moved}} before failing, and could not move them back: {{.RestoreError}}",
+			map[string]interface{}{"Error": err.Error(), "Moved": strings.Join(moved, ", "), "RestoreError": restoreErr.Error()}))
+	}
+	return nil, errors.New(T("{{.Error}}\nMoved {{.Moved}} before failing, and moved them back to their original positions",
+		map[string]interface{}{"Error": err.Error(), "Moved": strings.Join(moved, ", ")}))
+}
+
+// moveBuildpacks applies the moves in order to buildpacks, which are sorted by
+// position, skipping buildpacks that are already in place. It returns the
+// resulting order and the moves that were applied.
+func (cmd *SyncBuildpacks) moveBuildpacks(buildpacks []models.Buildpack, moves []buildpackMove) ([]models.Buildpack, []buildpackMove, error) {
+	applied := []buildpackMove{}
+	for _, move := range moves {
+		index := -1
+		for i, buildpack := range buildpacks {
+			if buildpack.Name == move.name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return buildpacks, applied, errors.NewModelNotFoundError("Buildpack", move.name)
+		}
+
+		target := move.position - 1
+		if target >= len(buildpacks) {
+			target = len(buildpacks) - 1
+		}
+		if index == target {
+			continue
+		}
+
+		buildpack := buildpacks[index]
+		position := target + 1
+		_, err := cmd.buildpackRepo.Update(models.Buildpack{Guid: buildpack.Guid, Name: buildpack.Name, Position: &position})
+		if err != nil {
+			return buildpacks, applied, err
+		}
+		applied = append(applied, buildpackMove{name: buildpack.Name, position: position, previous: index + 1})
+
+		rest := append(append([]models.Buildpack{}, buildpacks[:index]...), buildpacks[index+1:]...)
+		buildpacks = append(append(append([]models.Buildpack{}, rest[:target]...), buildpack), rest[target:]...)
+	}
+
+	return buildpacks, applied, nil
+}
+
+// buildpackChecksum returns the sha1 of the uploaded bits of a buildpack,
+// which the Cloud Controller appends to its guid to form the key of the bits.
+func buildpackChecksum(buildpack models.Buildpack) string {
+	prefix := buildpack.Guid + "_"
+	if buildpack.Guid == "" || !strings.HasPrefix(buildpack.Key, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(buildpack.Key, prefix)
+}
+
+func isWebURL(buildpackPath string) bool {
+	return strings.HasPrefix(buildpackPath, "http://") || strings.HasPrefix(buildpackPath, "https://")
+}
+
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
+
+func enabledChange(enabled bool) string {
+	if enabled {
+		return T("enabled")
+	}
+	return T("disabled")
+}
+
+func lockedChange(locked bool) string {
+	if locked {
+		return T("locked")
+	}
+	return T("unlocked")
+}
+
+type declarationsByPosition []buildpackDeclaration
+
+func (d declarationsByPosition) Len() int           { return len(d) }
+func (d declarationsByPosition) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d declarationsByPosition) Less(i, j int) bool { return *d[i].Position < *d[j].Position }
+
+type buildpacksByPosition []models.Buildpack
+
+func (b buildpacksByPosition) Len() int      { return len(b) }
+func (b buildpacksByPosition) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b buildpacksByPosition) Less(i, j int) bool {
+	return positionOf(b[i]) < positionOf(b[j])
+}
+
+func positionOf(buildpack models.Buildpack) int {
+	if buildpack.Position == nil {
+		return int(^uint(0) >> 1)
+	}
+	return *buildpack.Position
+}
@@ -0,0 +1,315 @@
+package buildpack_test
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sync-buildpacks command", func() {
+	var (
+		requirementsFactory *testreq.FakeReqFactory
+		repo                *testapi.FakeBuildpackRepository
+		bitsRepo            *testapi.FakeBuildpackBitsRepository
+		ui                  *testterm.FakeUI
+		deps                command_registry.Dependency
+		dir                 string
+		buildpacksFile      string
+		zipSha1             string
+		oldCfHome           string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetBuildpackRepository(repo)
+		deps.RepoLocator = deps.RepoLocator.SetBuildpackBitsRepository(bitsRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("sync-buildpacks").SetDependency(deps, pluginCall))
+	}
+
+	intPtr := func(i int) *int { return &i }
+	boolPtr := func(b bool) *bool { return &b }
+
+	BeforeEach(func() {
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		repo = &testapi.FakeBuildpackRepository{}
+		bitsRepo = &testapi.FakeBuildpackBitsRepository{}
+		ui = &testterm.FakeUI{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "sync-buildpacks")
+		Expect(err).NotTo(HaveOccurred())
+
+		oldCfHome = os.Getenv("CF_HOME")
+		os.Setenv("CF_HOME", dir)
+
+		contents, err := ioutil.ReadFile("../../../fixtures/buildpacks/example-buildpack-in-dir.zip")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "ruby.zip"), contents, 0644)).To(Succeed())
+		zipSha1, err = api.BuildpackArchiveChecksum(filepath.Join(dir, "ruby.zip"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.Setenv("CF_HOME", oldCfHome)
+		os.RemoveAll(dir)
+	})
+
+	writeBuildpacksFile := func(contents string) string {
+		path := filepath.Join(dir, "buildpacks.yml")
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+		return path
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("sync-buildpacks", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when the user is not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("-f", "buildpacks.yml")).To(BeFalse())
+		})
+
+		It("fails with usage when not given a file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires the -f flag"},
+			))
+		})
+	})
+
+	It("does not upload bits whose checksum is unchanged", func() {
+		repo.Buildpacks = []models.Buildpack{
+			{Guid: "ruby-guid", Name: "ruby", Position: intPtr(1), Key: "ruby-guid_" + zipSha1, Filename: "ruby.zip"},
+		}
+
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n"))
+
+		Expect(bitsRepo.UploadBuildpackPath).To(BeEmpty())
+		Expect(repo.UpdatedBuildpacks).To(BeEmpty())
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Syncing buildpacks from", "buildpacks.yml"},
+			[]string{"OK"},
+			[]string{"ruby", "unchanged"},
+		))
+	})
+
+	It("compares the checksum of the archive as it is uploaded rather than of the zip file", func() {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "ruby.zip"))
+		Expect(err).NotTo(HaveOccurred())
+		repo.Buildpacks = []models.Buildpack{
+			{Guid: "ruby-guid", Name: "ruby", Key: fmt.Sprintf("ruby-guid_%x", sha1.Sum(contents)), Filename: "ruby.zip"},
+		}
+
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n"))
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"Uploading buildpack", "ruby", "checksum changed"}))
+	})
+
+	It("uploads bits whose checksum changed, relative to the file", func() {
+		repo.Buildpacks = []models.Buildpack{
+			{Guid: "ruby-guid", Name: "ruby", Position: intPtr(1), Key: "ruby-guid_0000", Filename: "ruby.zip"},
+		}
+
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n"))
+
+		Expect(bitsRepo.UploadBuildpackPath).To(Equal(filepath.Join(dir, "ruby.zip")))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Uploading buildpack", "ruby", "checksum changed"},
+			[]string{"ruby", "uploaded"},
+		))
+	})
+
+	It("creates and uploads new buildpacks", func() {
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n  enabled: false\n  locked: true\n"))
+
+		Expect(repo.CreateBuildpack.Name).To(Equal("ruby"))
+		Expect(*repo.CreateBuildpack.Enabled).To(BeFalse())
+		Expect(bitsRepo.UploadBuildpackPath).To(Equal(filepath.Join(dir, "ruby.zip")))
+		Expect(repo.UpdatedBuildpacks).To(HaveLen(1))
+		Expect(*repo.UpdatedBuildpacks[0].Locked).To(BeTrue())
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"ruby", "created, uploaded, locked"}))
+	})
+
+	Describe("buildpacks with a url", func() {
+		BeforeEach(func() {
+			repo.Buildpacks = []models.Buildpack{{Guid: "go-guid", Name: "go", Key: "go-guid_1111", Filename: "go.zip"}}
+		})
+
+		It("uploads the bits when the url they were uploaded from is unknown", func() {
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: go\n  path: https://example.com/v1/go.zip\n"))
+
+			Expect(bitsRepo.UploadBuildpackPath).To(Equal("https://example.com/v1/go.zip"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Uploading buildpack", "go", "source unknown"}))
+		})
+
+		It("compares the full url with the one the bits were last synced from", func() {
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: go\n  path: https://example.com/v1/go.zip\n"))
+
+			bitsRepo.UploadBuildpackPath = ""
+			ui = &testterm.FakeUI{}
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: go\n  path: https://example.com/v1/go.zip\n"))
+
+			Expect(bitsRepo.UploadBuildpackPath).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"go", "unchanged"}))
+
+			ui = &testterm.FakeUI{}
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: go\n  path: https://example.com/v2/go.zip\n"))
+
+			Expect(bitsRepo.UploadBuildpackPath).To(Equal("https://example.com/v2/go.zip"))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"Uploading buildpack", "go", "url changed"}))
+		})
+	})
+
+	It("updates the enabled state", func() {
+		repo.Buildpacks = []models.Buildpack{{Guid: "ruby-guid", Name: "ruby", Enabled: boolPtr(true)}}
+
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  enabled: false\n"))
+
+		Expect(repo.UpdatedBuildpacks).To(HaveLen(1))
+		Expect(repo.UpdatedBuildpacks[0].Guid).To(Equal("ruby-guid"))
+		Expect(*repo.UpdatedBuildpacks[0].Enabled).To(BeFalse())
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"ruby", "disabled"}))
+	})
+
+	It("unlocks a locked buildpack declared unlocked before uploading its bits", func() {
+		repo.Buildpacks = []models.Buildpack{{Guid: "ruby-guid", Name: "ruby", Locked: boolPtr(true), Key: "ruby-guid_0000"}}
+
+		runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n  locked: false\n"))
+
+		Expect(repo.UpdatedBuildpacks).To(HaveLen(1))
+		Expect(*repo.UpdatedBuildpacks[0].Locked).To(BeFalse())
+		Expect(bitsRepo.UploadBuildpackPath).To(Equal(filepath.Join(dir, "ruby.zip")))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"ruby", "unlocked, uploaded"}))
+	})
+
+	It("moves the buildpacks to their positions in ascending order", func() {
+		repo.Buildpacks = []models.Buildpack{
+			{Guid: "a-guid", Name: "a", Position: intPtr(1)},
+			{Guid: "b-guid", Name: "b", Position: intPtr(2)},
+			{Guid: "c-guid", Name: "c", Position: intPtr(3)},
+		}
+
+		runCommand("-f", writeBuildpacksFile(`buildpacks:
+- name: a
+  position: 2
+- name: c
+  position: 1
+`))
+
+		Expect(repo.UpdatedBuildpacks).To(HaveLen(1))
+		Expect(repo.UpdatedBuildpacks[0].Name).To(Equal("c"))
+		Expect(*repo.UpdatedBuildpacks[0].Position).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"Moved c to position 1"}))
+	})
+
+	Describe("when a move fails", func() {
+		BeforeEach(func() {
+			repo.Buildpacks = []models.Buildpack{
+				{Guid: "a-guid", Name: "a", Position: intPtr(1)},
+				{Guid: "b-guid", Name: "b", Position: intPtr(2)},
+				{Guid: "c-guid", Name: "c", Position: intPtr(3)},
+				{Guid: "d-guid", Name: "d", Position: intPtr(4)},
+			}
+			buildpacksFile = writeBuildpacksFile("buildpacks:\n- name: d\n  position: 1\n- name: c\n  position: 2\n")
+		})
+
+		It("moves the buildpacks already moved back to their original positions", func() {
+			repo.UpdateBuildpackErrors = []error{nil, errors.New("server error")}
+
+			runCommand("-f", buildpacksFile)
+
+			Expect(repo.UpdatedBuildpacks).To(HaveLen(3))
+			Expect(repo.UpdatedBuildpacks[2].Name).To(Equal("d"))
+			Expect(*repo.UpdatedBuildpacks[2].Position).To(Equal(4))
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Failed ordering buildpacks", "server error"},
+				[]string{"Moved d to position 1 before failing, and moved them back to their original positions"},
+			))
+		})
+
+		It("reports the moves that were applied when they cannot be moved back", func() {
+			repo.UpdateBuildpackErrors = []error{nil, errors.New("server error"), errors.New("still failing")}
+
+			runCommand("-f", buildpacksFile)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Moved d to position 1 before failing, and could not move them back", "still failing"},
+			))
+		})
+	})
+
+	Describe("when the file is invalid", func() {
+		It("fails without changing anything when positions collide", func() {
+			repo.Buildpacks = []models.Buildpack{{Guid: "ruby-guid", Name: "ruby"}, {Guid: "go-guid", Name: "go"}}
+
+			runCommand("-f", writeBuildpacksFile(`buildpacks:
+- name: ruby
+  position: 1
+  enabled: false
+- name: go
+  position: 1
+`))
+
+			Expect(repo.UpdatedBuildpacks).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Invalid buildpacks file"},
+				[]string{"buildpacks ruby and go both have position 1"},
+			))
+		})
+
+		It("fails without changing anything when a position is beyond the last buildpack", func() {
+			repo.Buildpacks = []models.Buildpack{{Guid: "go-guid", Name: "go"}}
+
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n- name: go\n  position: 3\n"))
+
+			Expect(repo.CreateBuildpack.Name).To(BeEmpty())
+			Expect(repo.UpdatedBuildpacks).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"buildpack go has position 3, but there will only be 2 buildpacks"},
+			))
+		})
+
+		It("fails when the bits of a locked buildpack would change", func() {
+			repo.Buildpacks = []models.Buildpack{{Guid: "ruby-guid", Name: "ruby", Locked: boolPtr(true), Key: "ruby-guid_0000"}}
+
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n  path: ruby.zip\n"))
+
+			Expect(bitsRepo.UploadBuildpackPath).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"buildpack ruby is locked, set 'locked: false' to update its bits"},
+			))
+		})
+
+		It("fails when a new buildpack has no path or a path does not exist", func() {
+			runCommand("-f", writeBuildpacksFile("buildpacks:\n- name: ruby\n- name: go\n  path: missing.zip\n"))
+
+			Expect(repo.CreateBuildpack.Name).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"buildpack ruby does not exist and has no path"},
+				[]string{"buildpack go", "missing.zip"},
+			))
+		})
+	})
+})
@@ -1,8 +1,6 @@
 package buildpack
 
 import (
-	"path/filepath"
-
 	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	. "github.com/cloudfoundry/cli/cf/i18n"
@@ -27,6 +25,7 @@ func (cmd *UpdateBuildpack) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["i"] = &cliFlags.IntFlag{Name: "i", Usage: T("The order in which the buildpacks are checked during buildpack auto-detection")}
 	fs["p"] = &cliFlags.StringFlag{Name: "p", Usage: T("Path to directory or zip file")}
+	fs["sha256"] = &cliFlags.StringFlag{Name: "sha256", Usage: T("Verify the buildpack downloaded from a url against this sha256 checksum")}
 	fs["enable"] = &cliFlags.BoolFlag{Name: "enable", Usage: T("Enable the buildpack to be used for staging")}
 	fs["disable"] = &cliFlags.BoolFlag{Name: "disable", Usage: T("Disable the buildpack from being used for staging")}
 	fs["lock"] = &cliFlags.BoolFlag{Name: "lock", Usage: T("Lock the buildpack to prevent updates")}
@@ -35,8 +34,9 @@ func (cmd *UpdateBuildpack) MetaData() command_registry.CommandMetadata {
 	return command_registry.CommandMetadata{
 		Name:        "update-buildpack",
 		Description: T("Update a buildpack"),
-		Usage: T("CF_NAME update-buildpack BUILDPACK [-p PATH] [-i POSITION] [--enable|--disable] [--lock|--unlock]") +
-			T("\n\nTIP:\n") + T("   Path should be a zip file, a url to a zip file, or a local directory. Position is a positive integer, sets priority, and is sorted from lowest to highest."),
+		Usage: T("CF_NAME update-buildpack BUILDPACK [-p PATH [--sha256 CHECKSUM]] [-i POSITION] [--enable|--disable] [--lock|--unlock]") +
+			T("\n\nTIP:\n") + T("   Path should be a zip file, a url to a zip file, or a local directory. Position is a positive integer, sets priority, and is sorted from lowest to highest.") +
+			T("\n   Buildpacks downloaded from a url and verified with --sha256 are cached by checksum in CF_HOME and not downloaded again."),
 		Flags: fs,
 	}
 }
@@ -46,6 +46,14 @@ func (cmd *UpdateBuildpack) Requirements(requirementsFactory requirements.Factor
 		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("update-buildpack"))
 	}
 
+	if fc.String("sha256") != "" && fc.String("p") == "" {
+		cmd.ui.Failed(T("--sha256 requires -p with a url"))
+	}
+
+	if err := validateSha256Flag(fc.String("p"), fc.String("sha256")); err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
 	loginReq := requirementsFactory.NewLoginRequirement()
 	cmd.buildpackReq = requirementsFactory.NewBuildpackRequirement(fc.Args()[0])
 
@@ -104,18 +112,20 @@ func (cmd *UpdateBuildpack) Execute(c flags.FlagContext) {
 	}
 
 	path := c.String("p")
+	if path != "" && (lock || unlock) {
+		cmd.ui.Failed(T("Cannot specify buildpack bits and lock/unlock."))
+	}
+
 	var dir string
 	var err error
 	if path != "" {
-		dir, err = filepath.Abs(path)
+		var cleanup func()
+		dir, cleanup, err = fetchBuildpack(cmd.ui, cmd.buildpackBitsRepo, path, c.String("sha256"))
 		if err != nil {
 			cmd.ui.Failed(err.Error())
 			return
 		}
-	}
-
-	if dir != "" && (lock || unlock) {
-		cmd.ui.Failed(T("Cannot specify buildpack bits and lock/unlock."))
+		defer cleanup()
 	}
 
 	if lock {
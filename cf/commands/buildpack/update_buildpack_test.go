@@ -1,6 +1,11 @@
 package buildpack_test
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
@@ -130,6 +135,33 @@ var _ = Describe("Updating buildpack command", func() {
 				successfulUpdate(ui, "my-buildpack")
 			})
 
+			It("downloads and verifies the buildpack when passed a url", func() {
+				bitsRepo.DownloadBuildpackContents = "buildpack bits"
+
+				cfHome, err := ioutil.TempDir("", "cf-home")
+				Expect(err).NotTo(HaveOccurred())
+				defer os.RemoveAll(cfHome)
+				oldCfHome := os.Getenv("CF_HOME")
+				os.Setenv("CF_HOME", cfHome)
+				defer os.Setenv("CF_HOME", oldCfHome)
+
+				bitsSha256 := fmt.Sprintf("%x", sha256.Sum256([]byte("buildpack bits")))
+				runCommand("-p", "https://example.com/my-buildpack.zip", "--sha256", bitsSha256, "my-buildpack")
+
+				Expect(bitsRepo.UploadBuildpackPath).To(Equal(filepath.Join(cfHome, ".cf", "buildpacks", bitsSha256, "my-buildpack.zip")))
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Downloading buildpack from", "https://example.com/my-buildpack.zip"},
+					[]string{"Verified sha256 checksum"},
+				))
+				successfulUpdate(ui, "my-buildpack")
+			})
+
+			It("fails when passed --sha256 without a path", func() {
+				runCommand("--sha256", strings.Repeat("0", 64), "my-buildpack")
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"FAILED"}, []string{"--sha256 requires -p with a url"}))
+			})
+
 			It("errors when passed invalid path", func() {
 				bitsRepo.UploadBuildpackErr = true
 
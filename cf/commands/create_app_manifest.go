@@ -10,6 +10,7 @@ import (
 
 	"github.com/cloudfoundry/cli/cf/api"
 	"github.com/cloudfoundry/cli/cf/api/app_instances"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
 	"github.com/cloudfoundry/cli/cf/manifest"
@@ -23,6 +24,7 @@ type CreateAppManifest struct {
 	config           core_config.Reader
 	appSummaryRepo   api.AppSummaryRepository
 	appInstancesRepo app_instances.AppInstancesRepository
+	stackRepo        stacks.StackRepository
 	appReq           requirements.ApplicationRequirement
 	manifest         manifest.AppManifest
 }
@@ -34,16 +36,29 @@ func init() {
 func (cmd *CreateAppManifest) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["p"] = &cliFlags.StringFlag{Name: "p", Usage: T("Specify a path for file creation. If path not specified, manifest file is created in current working directory.")}
+	fs["all"] = &cliFlags.BoolFlag{Name: "all", Usage: T("Create a single manifest for all apps in the targeted space")}
 
 	return command_registry.CommandMetadata{
 		Name:        "create-app-manifest",
 		Description: T("Create an app manifest for an app that has been pushed successfully."),
-		Usage:       T("CF_NAME create-app-manifest APP_NAME [-p /path/to/<app-name>-manifest.yml ]"),
+		Usage:       T("CF_NAME create-app-manifest APP_NAME [-p /path/to/<app-name>-manifest.yml ]") + "\n   " + T("CF_NAME create-app-manifest --all [-p /path/to/<space-name>-manifest.yml ]"),
 		Flags:       fs,
 	}
 }
 
 func (cmd *CreateAppManifest) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if fc.Bool("all") {
+		if len(fc.Args()) != 0 {
+			cmd.ui.Failed(T("Incorrect Usage. APP_NAME cannot be combined with --all\n\n") + command_registry.Commands.CommandUsage("create-app-manifest"))
+		}
+
+		reqs = []requirements.Requirement{
+			requirementsFactory.NewLoginRequirement(),
+			requirementsFactory.NewTargetedSpaceRequirement(),
+		}
+		return
+	}
+
 	if len(fc.Args()) != 1 {
 		cmd.ui.Failed(T("Incorrect Usage. Requires APP_NAME as argument\n\n") + command_registry.Commands.CommandUsage("create-app-manifest"))
 	}
@@ -62,35 +77,101 @@ func (cmd *CreateAppManifest) SetDependency(deps command_registry.Dependency, pl
 	cmd.ui = deps.Ui
 	cmd.config = deps.Config
 	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
 	cmd.manifest = deps.AppManifest
 	return cmd
 }
 
 func (cmd *CreateAppManifest) Execute(c flags.FlagContext) {
-	app := cmd.appReq.GetApplication()
+	var (
+		apps     []models.Application
+		savePath string
+	)
 
-	application, apiErr := cmd.appSummaryRepo.GetSummary(app.Guid)
-	if apiErr != nil {
-		cmd.ui.Failed(T("Error getting application summary: ") + apiErr.Error())
-	}
+	if c.Bool("all") {
+		apps = cmd.getSpaceSummaries()
 
-	cmd.ui.Say(T("Creating an app manifest from current settings of app ") + application.Name + " ...")
-	cmd.ui.Say("")
+		cmd.ui.Say(T("Creating an app manifest from current settings of all apps in space ") + cmd.config.SpaceFields().Name + " ...")
+		cmd.ui.Say("")
 
-	savePath := "./" + application.Name + "_manifest.yml"
+		savePath = "./" + cmd.config.SpaceFields().Name + "_manifest.yml"
+	} else {
+		app := cmd.appReq.GetApplication()
+
+		application, apiErr := cmd.appSummaryRepo.GetSummary(app.Guid)
+		if apiErr != nil {
+			cmd.ui.Failed(T("Error getting application summary: ") + apiErr.Error())
+		}
+		apps = []models.Application{application}
+
+		cmd.ui.Say(T("Creating an app manifest from current settings of app ") + application.Name + " ...")
+		cmd.ui.Say("")
+
+		savePath = "./" + application.Name + "_manifest.yml"
+	}
 
 	if c.String("p") != "" {
 		savePath = c.String("p")
 	}
 
-	cmd.createManifest(application, savePath)
+	cmd.createManifest(apps, savePath)
 }
 
-func (cmd *CreateAppManifest) createManifest(app models.Application, savePath string) error {
+func (cmd *CreateAppManifest) getSpaceSummaries() []models.Application {
+	spaceApps, apiErr := cmd.appSummaryRepo.GetSummariesInCurrentSpace()
+	if apiErr != nil {
+		cmd.ui.Failed(T("Error getting application summary: ") + apiErr.Error())
+	}
+
+	if len(spaceApps) == 0 {
+		cmd.ui.Failed(T("No apps found in space ") + cmd.config.SpaceFields().Name)
+	}
+
+	apps := []models.Application{}
+	for _, spaceApp := range spaceApps {
+		application, apiErr := cmd.appSummaryRepo.GetSummary(spaceApp.Guid)
+		if apiErr != nil {
+			cmd.ui.Failed(T("Error getting application summary: ") + apiErr.Error())
+		}
+		apps = append(apps, application)
+	}
+
+	return apps
+}
+
+func (cmd *CreateAppManifest) createManifest(apps []models.Application, savePath string) error {
 	cmd.manifest.FileSavePath(savePath)
+
+	stackNames := cmd.getStackNames(apps)
+
+	for _, app := range apps {
+		cmd.addApplication(app, stackNames)
+	}
+
+	err := cmd.manifest.Save()
+	if err != nil {
+		cmd.ui.Failed(T("Error creating manifest file: ") + err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Manifest file created successfully at ") + savePath)
+	cmd.ui.Say("")
+
+	return nil
+}
+
+func (cmd *CreateAppManifest) addApplication(app models.Application, stackNames map[string]string) {
 	cmd.manifest.Memory(app.Name, app.Memory)
 	cmd.manifest.Instances(app.Name, app.InstanceCount)
 
+	if app.DiskQuota > 0 {
+		cmd.manifest.DiskQuota(app.Name, app.DiskQuota)
+	}
+
+	if app.Stack != nil && stackNames[app.Stack.Guid] != "" {
+		cmd.manifest.Stack(app.Name, stackNames[app.Stack.Guid])
+	}
+
 	if app.Command != "" {
 		cmd.manifest.StartCommand(app.Name, app.Command)
 	}
@@ -99,12 +180,20 @@ func (cmd *CreateAppManifest) createManifest(app models.Application, savePath st
 		cmd.manifest.BuildpackUrl(app.Name, app.BuildpackUrl)
 	}
 
+	if app.DockerImage != "" {
+		cmd.manifest.DockerImage(app.Name, app.DockerImage)
+	}
+
 	if len(app.Services) > 0 {
 		for _, service := range app.Services {
 			cmd.manifest.Service(app.Name, service.Name)
 		}
 	}
 
+	if app.HealthCheckType != "" {
+		cmd.manifest.HealthCheckType(app.Name, app.HealthCheckType)
+	}
+
 	if app.HealthCheckTimeout > 0 {
 		cmd.manifest.HealthCheckTimeout(app.Name, app.HealthCheckTimeout)
 	}
@@ -132,17 +221,32 @@ func (cmd *CreateAppManifest) createManifest(app models.Application, savePath st
 			cmd.manifest.Domain(app.Name, app.Routes[i].Host, app.Routes[i].Domain.Name)
 		}
 	}
+}
 
-	err := cmd.manifest.Save()
-	if err != nil {
-		cmd.ui.Failed(T("Error creating manifest file: ") + err.Error())
+func (cmd *CreateAppManifest) getStackNames(apps []models.Application) map[string]string {
+	stackNames := map[string]string{}
+
+	hasStack := false
+	for _, app := range apps {
+		if app.Stack != nil && app.Stack.Guid != "" {
+			hasStack = true
+		}
 	}
 
-	cmd.ui.Ok()
-	cmd.ui.Say(T("Manifest file created successfully at ") + savePath)
-	cmd.ui.Say("")
+	if !hasStack {
+		return stackNames
+	}
 
-	return nil
+	allStacks, apiErr := cmd.stackRepo.FindAll()
+	if apiErr != nil {
+		cmd.ui.Failed(T("Error getting stacks: ") + apiErr.Error())
+	}
+
+	for _, stack := range allStacks {
+		stackNames[stack.Guid] = stack.Name
+	}
+
+	return stackNames
 }
 
 func sortEnvVar(vars map[string]interface{}) []string {
@@ -5,6 +5,7 @@ import (
 
 	testAppInstanaces "github.com/cloudfoundry/cli/cf/api/app_instances/fakes"
 	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	testStacks "github.com/cloudfoundry/cli/cf/api/stacks/fakes"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
 	"github.com/cloudfoundry/cli/cf/formatters"
@@ -27,6 +28,7 @@ var _ = Describe("create-app-manifest Command", func() {
 		configRepo          core_config.Repository
 		appSummaryRepo      *testapi.FakeAppSummaryRepo
 		appInstancesRepo    *testAppInstanaces.FakeAppInstancesRepository
+		stackRepo           *testStacks.FakeStackRepository
 		requirementsFactory *testreq.FakeReqFactory
 		fakeManifest        *testManifest.FakeAppManifest
 		deps                command_registry.Dependency
@@ -36,6 +38,7 @@ var _ = Describe("create-app-manifest Command", func() {
 		deps.Ui = ui
 		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
 		deps.RepoLocator = deps.RepoLocator.SetAppInstancesRepository(appInstancesRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
 		deps.Config = configRepo
 		deps.AppManifest = fakeManifest
 		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("create-app-manifest").SetDependency(deps, pluginCall))
@@ -46,6 +49,7 @@ var _ = Describe("create-app-manifest Command", func() {
 		ui = &testterm.FakeUI{}
 		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
 		appInstancesRepo = &testAppInstanaces.FakeAppInstancesRepository{}
+		stackRepo = &testStacks.FakeStackRepository{}
 		configRepo = testconfig.NewRepositoryWithDefaults()
 		requirementsFactory = &testreq.FakeReqFactory{
 			LoginSuccess:         true,
@@ -77,6 +81,18 @@ var _ = Describe("create-app-manifest Command", func() {
 			Expect(passed).To(BeFalse())
 		})
 
+		It("fails with usage when provided an app name with --all", func() {
+			passed := runCommand("--all", "my-app")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "cannot be combined with --all"},
+			))
+			Expect(passed).To(BeFalse())
+		})
+
+		It("does not require an app name with --all", func() {
+			appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{makeAppWithoutOptions("my-app")}
+			Expect(runCommand("--all")).To(BeTrue())
+		})
 	})
 
 	Describe("creating app manifest", func() {
@@ -125,6 +141,50 @@ var _ = Describe("create-app-manifest Command", func() {
 			})
 		})
 
+		Context("app with disk quota, stack, health check type and docker image", func() {
+			BeforeEach(func() {
+				app := makeAppWithoutOptions("my-app")
+				app.DiskQuota = 1024
+				app.Stack = &models.Stack{Guid: "stack-guid"}
+				app.HealthCheckType = "none"
+				app.DockerImage = "user/my-image"
+				appSummaryRepo.GetSummarySummary = app
+				requirementsFactory.Application = app
+
+				stackRepo.FindAllReturns([]models.Stack{
+					{Guid: "other-stack-guid", Name: "other-stack"},
+					{Guid: "stack-guid", Name: "cflinuxfs2"},
+				}, nil)
+			})
+
+			It("includes them in the manifest", func() {
+				runCommand("my-app")
+
+				_, diskQuota := fakeManifest.DiskQuotaArgsForCall(0)
+				Ω(diskQuota).To(Equal(int64(1024)))
+				_, stackName := fakeManifest.StackArgsForCall(0)
+				Ω(stackName).To(Equal("cflinuxfs2"))
+				_, healthCheckType := fakeManifest.HealthCheckTypeArgsForCall(0)
+				Ω(healthCheckType).To(Equal("none"))
+				_, dockerImage := fakeManifest.DockerImageArgsForCall(0)
+				Ω(dockerImage).To(Equal("user/my-image"))
+			})
+		})
+
+		Context("app without a stack", func() {
+			BeforeEach(func() {
+				app := makeAppWithoutOptions("my-app")
+				appSummaryRepo.GetSummarySummary = app
+				requirementsFactory.Application = app
+			})
+
+			It("does not look up stacks", func() {
+				runCommand("my-app")
+				Ω(stackRepo.FindAllCallCount()).To(Equal(0))
+				Ω(fakeManifest.StackCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("app with buildpack", func() {
 			BeforeEach(func() {
 				app := makeAppWithOptions("my-app")
@@ -225,6 +285,38 @@ var _ = Describe("create-app-manifest Command", func() {
 			})
 		})
 
+		Context("when the flag --all is supplied", func() {
+			BeforeEach(func() {
+				app1 := makeAppWithoutOptions("my-app1")
+				app1.Guid = "app1-guid"
+				app2 := makeAppWithoutOptions("my-app2")
+				app2.Guid = "app2-guid"
+				appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{app1, app2}
+				appSummaryRepo.GetSummarySummary = makeAppWithOptions("my-app")
+			})
+
+			It("fetches the summary of every app in the space", func() {
+				runCommand("--all")
+				Ω(appSummaryRepo.GetSummaryAppGuid).To(Equal("app2-guid"))
+				Ω(fakeManifest.MemoryCallCount()).To(Equal(2))
+				Ω(fakeManifest.SaveCallCount()).To(Equal(1))
+			})
+
+			It("creates a manifest named <space-name>_manifest.yml", func() {
+				runCommand("--all")
+				Ω(fakeManifest.FileSavePathArgsForCall(0)).To(Equal("./my-space_manifest.yml"))
+			})
+
+			It("fails when there are no apps in the space", func() {
+				appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{}
+				runCommand("--all")
+				Ω(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"No apps found in space", "my-space"},
+				))
+			})
+		})
+
 	})
 })
 
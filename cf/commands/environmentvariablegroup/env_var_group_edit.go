@@ -0,0 +1,220 @@
+package environmentvariablegroup
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/environment_variable_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+// envVarGroup lets the set-*-env-var and unset-*-env-var commands edit the
+// running and staging groups the same way.
+type envVarGroup struct {
+	name  string
+	read  func() (map[string]interface{}, error)
+	write func(string) error
+}
+
+func runningEnvVarGroup(repo environment_variable_groups.EnvironmentVariableGroupsRepository) envVarGroup {
+	return envVarGroup{name: "running", read: repo.ReadRunning, write: repo.SetRunning}
+}
+
+func stagingEnvVarGroup(repo environment_variable_groups.EnvironmentVariableGroupsRepository) envVarGroup {
+	return envVarGroup{name: "staging", read: repo.ReadStaging, write: repo.SetStaging}
+}
+
+func setEnvVarFlags() map[string]flags.FlagSet {
+	fs := make(map[string]flags.FlagSet)
+	fs["from-file"] = &cliFlags.StringFlag{Name: "from-file", Usage: T("Set the variables of a JSON or dotenv (NAME=value) file")}
+	fs["replace"] = &cliFlags.BoolFlag{Name: "replace", Usage: T("Remove the variables that are not in the file")}
+	fs["dry-run"] = &cliFlags.BoolFlag{Name: "dry-run", Usage: T("Only show the changes, without saving them")}
+	return fs
+}
+
+func unsetEnvVarFlags() map[string]flags.FlagSet {
+	fs := make(map[string]flags.FlagSet)
+	fs["dry-run"] = &cliFlags.BoolFlag{Name: "dry-run", Usage: T("Only show the changes, without saving them")}
+	return fs
+}
+
+func setEnvVarUsage(commandName string) string {
+	return T(`CF_NAME {{.Command}} ENV_VAR_NAME ENV_VAR_VALUE [--dry-run]
+   CF_NAME {{.Command}} --from-file FILE [--replace] [--dry-run]
+
+EXAMPLE:
+   CF_NAME {{.Command}} HTTP_PROXY http://proxy.example.com:8080
+   CF_NAME {{.Command}} JAVA_OPTS -Xmx512m
+   CF_NAME {{.Command}} --from-file proxy.env --dry-run`, map[string]interface{}{"Command": commandName})
+}
+
+func unsetEnvVarUsage(commandName string) string {
+	return T("CF_NAME {{.Command}} ENV_VAR_NAME [ENV_VAR_NAME...] [--dry-run]", map[string]interface{}{"Command": commandName})
+}
+
+// setEnvVarArgs holds the parsed arguments of set-running-env-var and
+// set-staging-env-var. Those commands skip flag parsing so that a value such
+// as -Xmx512m is not taken for a flag.
+type setEnvVarArgs struct {
+	name     string
+	value    string
+	fromFile string
+	replace  bool
+	dryRun   bool
+}
+
+func parseSetEnvVarArgs(args []string) (parsed setEnvVarArgs, ok bool) {
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case len(positional) == 1:
+			positional = append(positional, args[i])
+		case args[i] == "--dry-run":
+			parsed.dryRun = true
+		case args[i] == "--replace":
+			parsed.replace = true
+		case args[i] == "--from-file" && i+1 < len(args):
+			parsed.fromFile = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "-"):
+			return setEnvVarArgs{}, false
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if parsed.fromFile != "" {
+		return parsed, len(positional) == 0
+	}
+	if len(positional) != 2 {
+		return parsed, false
+	}
+	parsed.name, parsed.value = positional[0], positional[1]
+	return parsed, true
+}
+
+func checkSetEnvVarArgs(ui terminal.UI, commandName string, fc flags.FlagContext) {
+	parsed, ok := parseSetEnvVarArgs(fc.Args())
+	if ok {
+		if parsed.replace && parsed.fromFile == "" {
+			ui.Failed(T("Incorrect Usage. --replace requires --from-file\n\n") + command_registry.Commands.CommandUsage(commandName))
+		}
+		return
+	}
+
+	switch {
+	case parsed.fromFile != "":
+		ui.Failed(T("Incorrect Usage. --from-file cannot be combined with a name and value\n\n") + command_registry.Commands.CommandUsage(commandName))
+	case parsed.replace:
+		ui.Failed(T("Incorrect Usage. --replace requires --from-file\n\n") + command_registry.Commands.CommandUsage(commandName))
+	default:
+		ui.Failed(T("Incorrect Usage. Requires 'env-name env-value' as arguments\n\n") + command_registry.Commands.CommandUsage(commandName))
+	}
+}
+
+func checkUnsetEnvVarArgs(ui terminal.UI, commandName string, fc flags.FlagContext) {
+	if len(fc.Args()) == 0 {
+		ui.Failed(T("Incorrect Usage. Requires 'env-name' as argument\n\n") + command_registry.Commands.CommandUsage(commandName))
+	}
+}
+
+func setEnvVars(ui terminal.UI, username string, group envVarGroup, c flags.FlagContext) {
+	ui.Say(T("Setting variables of the {{.Group}} environment variable group as {{.Username}}...", map[string]interface{}{
+		"Group":    group.name,
+		"Username": terminal.EntityNameColor(username)}))
+
+	args, _ := parseSetEnvVarArgs(c.Args())
+	newVars := map[string]interface{}{}
+	if path := args.fromFile; path != "" {
+		fileVars, err := ui_helpers.ReadEnvVarsFile(path)
+		if err != nil {
+			ui.Failed(err.Error())
+			return
+		}
+		newVars = fileVars
+	} else {
+		newVars[args.name] = args.value
+	}
+
+	editEnvVarGroup(ui, group, args.dryRun, func(vars map[string]interface{}) map[string]interface{} {
+		if args.replace {
+			vars = map[string]interface{}{}
+		}
+		for name, value := range newVars {
+			vars[name] = value
+		}
+		return vars
+	})
+}
+
+func unsetEnvVars(ui terminal.UI, username string, group envVarGroup, c flags.FlagContext) {
+	ui.Say(T("Removing variables from the {{.Group}} environment variable group as {{.Username}}...", map[string]interface{}{
+		"Group":    group.name,
+		"Username": terminal.EntityNameColor(username)}))
+
+	editEnvVarGroup(ui, group, c.Bool("dry-run"), func(vars map[string]interface{}) map[string]interface{} {
+		for _, name := range c.Args() {
+			if _, ok := vars[name]; !ok {
+				ui.Warn(T("Env variable {{.VarName}} was not set.", map[string]interface{}{"VarName": name}))
+				continue
+			}
+			delete(vars, name)
+		}
+		return vars
+	})
+}
+
+// editEnvVarGroup reads the whole group, applies edit to a copy, shows the
+// differences and writes the group back in a single request, unless nothing
+// changed or this is a dry run.
+func editEnvVarGroup(ui terminal.UI, group envVarGroup, dryRun bool, edit func(map[string]interface{}) map[string]interface{}) {
+	current, err := group.read()
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+
+	desired := map[string]interface{}{}
+	for name, value := range current {
+		desired[name] = value
+	}
+	desired = edit(desired)
+
+	lines := ui_helpers.EnvVarsDiff(current, desired)
+	if len(lines) == 0 {
+		ui.Ok()
+		ui.Say(T("The {{.Group}} environment variable group is already up to date", map[string]interface{}{"Group": group.name}))
+		return
+	}
+
+	ui.Say("")
+	for _, line := range lines {
+		ui.Say("%s", line)
+	}
+	ui.Say("")
+
+	if dryRun {
+		ui.Ok()
+		ui.Say(T("Dry run, the {{.Group}} environment variable group was not changed", map[string]interface{}{"Group": group.name}))
+		return
+	}
+
+	jsonBytes, err := json.Marshal(desired)
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+
+	err = group.write(string(jsonBytes))
+	if err != nil {
+		ui.Failed(err.Error())
+		return
+	}
+
+	ui.Ok()
+}
@@ -0,0 +1,51 @@
+package environmentvariablegroup
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/environment_variable_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+)
+
+type SetRunningEnvVar struct {
+	ui                           terminal.UI
+	config                       core_config.ReadWriter
+	environmentVariableGroupRepo environment_variable_groups.EnvironmentVariableGroupsRepository
+}
+
+func init() {
+	command_registry.Register(&SetRunningEnvVar{})
+}
+
+func (cmd *SetRunningEnvVar) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:            "set-running-env-var",
+		Description:     T("Set a variable in the running environment variable group"),
+		Usage:           setEnvVarUsage("set-running-env-var"),
+		Flags:           setEnvVarFlags(),
+		SkipFlagParsing: true,
+	}
+}
+
+func (cmd *SetRunningEnvVar) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	checkSetEnvVarArgs(cmd.ui, "set-running-env-var", fc)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs, nil
+}
+
+func (cmd *SetRunningEnvVar) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.environmentVariableGroupRepo = deps.RepoLocator.GetEnvironmentVariableGroupsRepository()
+	return cmd
+}
+
+func (cmd *SetRunningEnvVar) Execute(c flags.FlagContext) {
+	setEnvVars(cmd.ui, cmd.config.Username(), runningEnvVarGroup(cmd.environmentVariableGroupRepo), c)
+}
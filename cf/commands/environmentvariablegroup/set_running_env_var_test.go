@@ -0,0 +1,236 @@
+package environmentvariablegroup_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	test_environmentVariableGroups "github.com/cloudfoundry/cli/cf/api/environment_variable_groups/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("set-running-env-var command", func() {
+	var (
+		ui                           *testterm.FakeUI
+		requirementsFactory          *testreq.FakeReqFactory
+		configRepo                   core_config.Repository
+		environmentVariableGroupRepo *test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository
+		deps                         command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetEnvironmentVariableGroupsRepository(environmentVariableGroupRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("set-running-env-var").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		environmentVariableGroupRepo = &test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository{}
+		environmentVariableGroupRepo.ReadRunningReturns(map[string]interface{}{
+			"HTTP_PROXY": "http://old-proxy.example.com",
+			"LOG_LEVEL":  "info",
+		}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("set-running-env-var", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	savedVars := func() map[string]interface{} {
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(1))
+		vars := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(environmentVariableGroupRepo.SetRunningArgsForCall(0)), &vars)).To(Succeed())
+		return vars
+	}
+
+	Describe("requirements", func() {
+		It("requires the user to be logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("NAME", "value")).ToNot(HavePassedRequirements())
+		})
+
+		It("fails with usage when not given a name and a value", func() {
+			runCommand("NAME")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires 'env-name env-value' as arguments"},
+			))
+		})
+
+		It("fails with usage when --from-file is given with a name and a value", func() {
+			runCommand("--from-file", "vars.env", "NAME", "value")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--from-file cannot be combined"},
+			))
+		})
+
+		It("fails with usage when given an unknown flag", func() {
+			runCommand("--force", "NAME", "value")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires 'env-name env-value' as arguments"},
+			))
+		})
+
+		It("fails with usage when --replace is given without --from-file", func() {
+			runCommand("--replace", "NAME", "value")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--replace requires --from-file"},
+			))
+		})
+	})
+
+	It("changes one variable, keeps the others and shows the change", func() {
+		runCommand("HTTP_PROXY", "http://proxy.example.com")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Setting variables of the running environment variable group as my-user..."},
+			[]string{"- HTTP_PROXY: http://old-proxy.example.com"},
+			[]string{"+ HTTP_PROXY: http://proxy.example.com"},
+			[]string{"OK"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"LOG_LEVEL"}))
+		Expect(savedVars()).To(Equal(map[string]interface{}{
+			"HTTP_PROXY": "http://proxy.example.com",
+			"LOG_LEVEL":  "info",
+		}))
+	})
+
+	It("does not save anything when the variable already has the value", func() {
+		runCommand("LOG_LEVEL", "info")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"OK"},
+			[]string{"The running environment variable group is already up to date"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+
+	It("only shows the change with --dry-run", func() {
+		runCommand("--dry-run", "NO_PROXY", "localhost")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"+ NO_PROXY: localhost"},
+			[]string{"OK"},
+			[]string{"Dry run, the running environment variable group was not changed"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+
+	It("sets a value that starts with a dash", func() {
+		runCommand("JAVA_OPTS", "-Xmx512m")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"+ JAVA_OPTS: -Xmx512m"},
+			[]string{"OK"},
+		))
+		Expect(savedVars()).To(Equal(map[string]interface{}{
+			"HTTP_PROXY": "http://old-proxy.example.com",
+			"LOG_LEVEL":  "info",
+			"JAVA_OPTS":  "-Xmx512m",
+		}))
+	})
+
+	It("accepts --dry-run after the name and value", func() {
+		runCommand("JAVA_OPTS", "-Xmx512m", "--dry-run")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"+ JAVA_OPTS: -Xmx512m"},
+			[]string{"Dry run, the running environment variable group was not changed"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+
+	It("fails when the group cannot be read", func() {
+		environmentVariableGroupRepo.ReadRunningReturns(nil, errors.New("read failed"))
+
+		runCommand("NAME", "value")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"read failed"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+
+	It("fails when the group cannot be saved", func() {
+		environmentVariableGroupRepo.SetRunningReturns(errors.New("save failed"))
+
+		runCommand("NAME", "value")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"save failed"},
+		))
+	})
+
+	Context("with --from-file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "set-running-env-var")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		writeFile := func(contents string) string {
+			path := filepath.Join(dir, "vars.env")
+			Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			return path
+		}
+
+		It("merges the variables of the file into the group", func() {
+			runCommand("--from-file", writeFile("LOG_LEVEL=debug\nNO_PROXY=localhost\n"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"- LOG_LEVEL: info"},
+				[]string{"+ LOG_LEVEL: debug"},
+				[]string{"+ NO_PROXY: localhost"},
+				[]string{"OK"},
+			))
+			Expect(savedVars()).To(Equal(map[string]interface{}{
+				"HTTP_PROXY": "http://old-proxy.example.com",
+				"LOG_LEVEL":  "debug",
+				"NO_PROXY":   "localhost",
+			}))
+		})
+
+		It("replaces the group with the variables of the file with --replace", func() {
+			runCommand("--from-file", writeFile(`{"LOG_LEVEL": "info"}`), "--replace")
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"- HTTP_PROXY: http://old-proxy.example.com"},
+				[]string{"OK"},
+			))
+			Expect(savedVars()).To(Equal(map[string]interface{}{
+				"LOG_LEVEL": "info",
+			}))
+		})
+
+		It("fails when the file cannot be read", func() {
+			runCommand("--from-file", filepath.Join(dir, "missing.env"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Error reading env file", "missing.env"},
+			))
+			Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package environmentvariablegroup
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/environment_variable_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+)
+
+type SetStagingEnvVar struct {
+	ui                           terminal.UI
+	config                       core_config.ReadWriter
+	environmentVariableGroupRepo environment_variable_groups.EnvironmentVariableGroupsRepository
+}
+
+func init() {
+	command_registry.Register(&SetStagingEnvVar{})
+}
+
+func (cmd *SetStagingEnvVar) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:            "set-staging-env-var",
+		Description:     T("Set a variable in the staging environment variable group"),
+		Usage:           setEnvVarUsage("set-staging-env-var"),
+		Flags:           setEnvVarFlags(),
+		SkipFlagParsing: true,
+	}
+}
+
+func (cmd *SetStagingEnvVar) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	checkSetEnvVarArgs(cmd.ui, "set-staging-env-var", fc)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs, nil
+}
+
+func (cmd *SetStagingEnvVar) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.environmentVariableGroupRepo = deps.RepoLocator.GetEnvironmentVariableGroupsRepository()
+	return cmd
+}
+
+func (cmd *SetStagingEnvVar) Execute(c flags.FlagContext) {
+	setEnvVars(cmd.ui, cmd.config.Username(), stagingEnvVarGroup(cmd.environmentVariableGroupRepo), c)
+}
@@ -0,0 +1,73 @@
+package environmentvariablegroup_test
+
+import (
+	test_environmentVariableGroups "github.com/cloudfoundry/cli/cf/api/environment_variable_groups/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("set-staging-env-var command", func() {
+	var (
+		ui                           *testterm.FakeUI
+		requirementsFactory          *testreq.FakeReqFactory
+		configRepo                   core_config.Repository
+		environmentVariableGroupRepo *test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository
+		deps                         command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetEnvironmentVariableGroupsRepository(environmentVariableGroupRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("set-staging-env-var").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		environmentVariableGroupRepo = &test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository{}
+		environmentVariableGroupRepo.ReadStagingReturns(map[string]interface{}{"LOG_LEVEL": "info"}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("set-staging-env-var", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	It("requires the user to be logged in", func() {
+		requirementsFactory.LoginSuccess = false
+		Expect(runCommand("NAME", "value")).ToNot(HavePassedRequirements())
+	})
+
+	It("sets the variable in the staging group", func() {
+		runCommand("BUNDLE_WITHOUT", "test")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Setting variables of the staging environment variable group as my-user..."},
+			[]string{"+ BUNDLE_WITHOUT: test"},
+			[]string{"OK"},
+		))
+		Expect(environmentVariableGroupRepo.ReadRunningCallCount()).To(Equal(0))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+		Expect(environmentVariableGroupRepo.SetStagingCallCount()).To(Equal(1))
+		Expect(environmentVariableGroupRepo.SetStagingArgsForCall(0)).To(MatchJSON(`{"LOG_LEVEL": "info", "BUNDLE_WITHOUT": "test"}`))
+	})
+
+	It("sets a value that starts with a dash", func() {
+		runCommand("JAVA_OPTS", "-Xmx512m")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"+ JAVA_OPTS: -Xmx512m"},
+			[]string{"OK"},
+		))
+		Expect(environmentVariableGroupRepo.SetStagingCallCount()).To(Equal(1))
+		Expect(environmentVariableGroupRepo.SetStagingArgsForCall(0)).To(MatchJSON(`{"LOG_LEVEL": "info", "JAVA_OPTS": "-Xmx512m"}`))
+	})
+})
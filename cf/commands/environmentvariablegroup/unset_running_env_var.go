@@ -0,0 +1,50 @@
+package environmentvariablegroup
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/environment_variable_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+)
+
+type UnsetRunningEnvVar struct {
+	ui                           terminal.UI
+	config                       core_config.ReadWriter
+	environmentVariableGroupRepo environment_variable_groups.EnvironmentVariableGroupsRepository
+}
+
+func init() {
+	command_registry.Register(&UnsetRunningEnvVar{})
+}
+
+func (cmd *UnsetRunningEnvVar) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:        "unset-running-env-var",
+		Description: T("Remove variables from the running environment variable group"),
+		Usage:       unsetEnvVarUsage("unset-running-env-var"),
+		Flags:       unsetEnvVarFlags(),
+	}
+}
+
+func (cmd *UnsetRunningEnvVar) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	checkUnsetEnvVarArgs(cmd.ui, "unset-running-env-var", fc)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs, nil
+}
+
+func (cmd *UnsetRunningEnvVar) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.environmentVariableGroupRepo = deps.RepoLocator.GetEnvironmentVariableGroupsRepository()
+	return cmd
+}
+
+func (cmd *UnsetRunningEnvVar) Execute(c flags.FlagContext) {
+	unsetEnvVars(cmd.ui, cmd.config.Username(), runningEnvVarGroup(cmd.environmentVariableGroupRepo), c)
+}
@@ -0,0 +1,101 @@
+package environmentvariablegroup_test
+
+import (
+	"encoding/json"
+
+	test_environmentVariableGroups "github.com/cloudfoundry/cli/cf/api/environment_variable_groups/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("unset-running-env-var command", func() {
+	var (
+		ui                           *testterm.FakeUI
+		requirementsFactory          *testreq.FakeReqFactory
+		configRepo                   core_config.Repository
+		environmentVariableGroupRepo *test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository
+		deps                         command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetEnvironmentVariableGroupsRepository(environmentVariableGroupRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("unset-running-env-var").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		environmentVariableGroupRepo = &test_environmentVariableGroups.FakeEnvironmentVariableGroupsRepository{}
+		environmentVariableGroupRepo.ReadRunningReturns(map[string]interface{}{
+			"HTTP_PROXY": "http://proxy.example.com",
+			"NO_PROXY":   "localhost",
+			"LOG_LEVEL":  "info",
+		}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("unset-running-env-var", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("requires the user to be logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("NAME")).ToNot(HavePassedRequirements())
+		})
+
+		It("fails with usage when not given a name", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires 'env-name' as argument"},
+			))
+		})
+	})
+
+	It("removes the variables in one update and warns about the ones that were not set", func() {
+		runCommand("HTTP_PROXY", "NO_PROXY", "MISSING")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Removing variables from the running environment variable group as my-user..."},
+			[]string{"Env variable MISSING was not set."},
+			[]string{"- HTTP_PROXY: http://proxy.example.com"},
+			[]string{"- NO_PROXY: localhost"},
+			[]string{"OK"},
+		))
+
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(1))
+		vars := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(environmentVariableGroupRepo.SetRunningArgsForCall(0)), &vars)).To(Succeed())
+		Expect(vars).To(Equal(map[string]interface{}{"LOG_LEVEL": "info"}))
+	})
+
+	It("does not save anything when none of the variables were set", func() {
+		runCommand("MISSING")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Env variable MISSING was not set."},
+			[]string{"OK"},
+			[]string{"The running environment variable group is already up to date"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+
+	It("only shows the change with --dry-run", func() {
+		runCommand("--dry-run", "LOG_LEVEL")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"- LOG_LEVEL: info"},
+			[]string{"Dry run, the running environment variable group was not changed"},
+		))
+		Expect(environmentVariableGroupRepo.SetRunningCallCount()).To(Equal(0))
+	})
+})
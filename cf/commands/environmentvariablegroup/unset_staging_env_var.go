@@ -0,0 +1,50 @@
+package environmentvariablegroup
+
+import (
+	"github.com/cloudfoundry/cli/cf/api/environment_variable_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+)
+
+type UnsetStagingEnvVar struct {
+	ui                           terminal.UI
+	config                       core_config.ReadWriter
+	environmentVariableGroupRepo environment_variable_groups.EnvironmentVariableGroupsRepository
+}
+
+func init() {
+	command_registry.Register(&UnsetStagingEnvVar{})
+}
+
+func (cmd *UnsetStagingEnvVar) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:        "unset-staging-env-var",
+		Description: T("Remove variables from the staging environment variable group"),
+		Usage:       unsetEnvVarUsage("unset-staging-env-var"),
+		Flags:       unsetEnvVarFlags(),
+	}
+}
+
+func (cmd *UnsetStagingEnvVar) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	checkUnsetEnvVarArgs(cmd.ui, "unset-staging-env-var", fc)
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs, nil
+}
+
+func (cmd *UnsetStagingEnvVar) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.environmentVariableGroupRepo = deps.RepoLocator.GetEnvironmentVariableGroupsRepository()
+	return cmd
+}
+
+func (cmd *UnsetStagingEnvVar) Execute(c flags.FlagContext) {
+	unsetEnvVars(cmd.ui, cmd.config.Username(), stagingEnvVarGroup(cmd.environmentVariableGroupRepo), c)
+}
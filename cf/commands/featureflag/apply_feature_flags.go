@@ -0,0 +1,117 @@
+package featureflag
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/feature_flags"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type ApplyFeatureFlags struct {
+	ui       terminal.UI
+	config   core_config.ReadWriter
+	flagRepo feature_flags.FeatureFlagRepository
+}
+
+func init() {
+	command_registry.Register(&ApplyFeatureFlags{})
+}
+
+func (cmd *ApplyFeatureFlags) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &cliFlags.StringFlag{Name: "f", Usage: T("Path to the feature flags file")}
+	fs["dry-run"] = &cliFlags.BoolFlag{Name: "dry-run", Usage: T("Only show the changes, without applying them")}
+
+	baseUsage := T(`CF_NAME apply-feature-flags -f FEATURE_FLAGS_FILE [--dry-run]
+
+   Enables and disables the feature flags declared in the file. Flags that are not declared
+   are left alone. Declared flags that this Cloud Controller does not support are reported
+   and make the command fail once the other flags have been applied.`)
+	exampleUsage := T(`EXAMPLE FEATURE FLAGS FILE:
+   feature_flags:
+     user_org_creation: false
+     private_domain_creation: true
+     app_scaling: true`)
+
+	return command_registry.CommandMetadata{
+		Name:        "apply-feature-flags",
+		Description: T("Enable and disable feature flags as declared in a file"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *ApplyFeatureFlags) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 0 || fc.String("f") == "" {
+		cmd.ui.Failed(T("Incorrect Usage. Requires the -f flag and no argument\n\n") + command_registry.Commands.CommandUsage("apply-feature-flags"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+	return reqs, err
+}
+
+func (cmd *ApplyFeatureFlags) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.flagRepo = deps.RepoLocator.GetFeatureFlagRepository()
+	return cmd
+}
+
+func (cmd *ApplyFeatureFlags) Execute(c flags.FlagContext) {
+	filePath := c.String("f")
+
+	cmd.ui.Say(T("Applying feature flags from {{.Path}} as {{.Username}}...", map[string]interface{}{
+		"Path":     terminal.EntityNameColor(filePath),
+		"Username": terminal.EntityNameColor(cmd.config.Username())}))
+
+	declared, err := readFeatureFlagsFile(filePath)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	current, err := cmd.flagRepo.List()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	drift, unknown := compareFeatureFlags(declared, current)
+
+	table := terminal.NewTable(cmd.ui, []string{T("Features"), T("State")})
+	for _, change := range drift {
+		if !c.Bool("dry-run") {
+			err = cmd.flagRepo.Update(change.Name, change.Enabled)
+			if err != nil {
+				cmd.ui.Failed(T("Failed setting status of {{.FeatureFlag}}: {{.Error}}",
+					map[string]interface{}{"FeatureFlag": change.Name, "Error": err.Error()}))
+			}
+		}
+		table.Add(change.Name, flagBoolToString(change.Enabled))
+	}
+
+	if len(drift) > 0 {
+		cmd.ui.Say("")
+		table.Print()
+		cmd.ui.Say("")
+	}
+
+	if len(unknown) > 0 {
+		cmd.ui.Failed(T("Feature flags not supported by this Cloud Controller: {{.FeatureFlags}}",
+			map[string]interface{}{"FeatureFlags": strings.Join(unknown, ", ")}))
+	}
+
+	cmd.ui.Ok()
+
+	if len(drift) == 0 {
+		cmd.ui.Say(T("Feature flags are already up to date"))
+	} else if c.Bool("dry-run") {
+		cmd.ui.Say(T("Dry run, no feature flag was changed"))
+	}
+}
@@ -0,0 +1,159 @@
+package featureflag_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	fakeflag "github.com/cloudfoundry/cli/cf/api/feature_flags/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("apply-feature-flags command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          core_config.Repository
+		flagRepo            *fakeflag.FakeFeatureFlagRepository
+		deps                command_registry.Dependency
+		dir                 string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetFeatureFlagRepository(flagRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("apply-feature-flags").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		flagRepo = &fakeflag.FakeFeatureFlagRepository{}
+		flagRepo.ListReturns([]models.FeatureFlag{
+			{Name: "user_org_creation", Enabled: true},
+			{Name: "private_domain_creation", Enabled: false},
+			{Name: "app_scaling", Enabled: true},
+		}, nil)
+
+		var err error
+		dir, err = ioutil.TempDir("", "apply-feature-flags")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(contents string) string {
+		path := filepath.Join(dir, "flags.yml")
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("apply-feature-flags", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("requires the user to be logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("-f", "flags.yml")).ToNot(HavePassedRequirements())
+		})
+
+		It("fails with usage when not given a file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires the -f flag"},
+			))
+		})
+	})
+
+	It("updates only the flags that differ from the file", func() {
+		path := writeFile("feature_flags:\n  user_org_creation: false\n  private_domain_creation: true\n  app_scaling: true\n")
+
+		runCommand("-f", path)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Applying feature flags from", path, "my-user"},
+			[]string{"private_domain_creation", "enabled"},
+			[]string{"user_org_creation", "disabled"},
+			[]string{"OK"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"app_scaling"}))
+
+		Expect(flagRepo.UpdateCallCount()).To(Equal(2))
+		name, enabled := flagRepo.UpdateArgsForCall(0)
+		Expect(name).To(Equal("private_domain_creation"))
+		Expect(enabled).To(BeTrue())
+		name, enabled = flagRepo.UpdateArgsForCall(1)
+		Expect(name).To(Equal("user_org_creation"))
+		Expect(enabled).To(BeFalse())
+	})
+
+	It("says when the flags are already up to date", func() {
+		runCommand("-f", writeFile("feature_flags:\n  app_scaling: true\n"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"OK"},
+			[]string{"Feature flags are already up to date"},
+		))
+		Expect(flagRepo.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("only shows the changes with --dry-run", func() {
+		runCommand("-f", writeFile("feature_flags:\n  app_scaling: false\n"), "--dry-run")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"app_scaling", "disabled"},
+			[]string{"OK"},
+			[]string{"Dry run, no feature flag was changed"},
+		))
+		Expect(flagRepo.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("applies the supported flags and fails listing the unsupported ones", func() {
+		runCommand("-f", writeFile("feature_flags:\n  app_scaling: false\n  service_instance_sharing: true\n"))
+
+		Expect(flagRepo.UpdateCallCount()).To(Equal(1))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"app_scaling", "disabled"},
+			[]string{"FAILED"},
+			[]string{"Feature flags not supported by this Cloud Controller: service_instance_sharing"},
+		))
+	})
+
+	It("fails when a flag cannot be updated", func() {
+		flagRepo.UpdateReturns(errors.New("update failed"))
+
+		runCommand("-f", writeFile("feature_flags:\n  app_scaling: false\n"))
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Failed setting status of app_scaling: update failed"},
+		))
+	})
+
+	It("fails when the file declares no flags", func() {
+		path := writeFile("buildpacks: []\n")
+
+		runCommand("-f", path)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"No feature flags declared in", path},
+		))
+		Expect(flagRepo.ListCallCount()).To(Equal(0))
+	})
+})
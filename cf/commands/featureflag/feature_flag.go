@@ -63,16 +63,8 @@ func (cmd *ShowFeatureFlag) Execute(c flags.FlagContext) {
 	cmd.ui.Say("")
 
 	table := terminal.NewTable(cmd.ui, []string{T("Features"), T("State")})
-	table.Add(flag.Name, cmd.flagBoolToString(flag.Enabled))
+	table.Add(flag.Name, flagBoolToString(flag.Enabled))
 
 	table.Print()
 	return
 }
-
-func (cmd ShowFeatureFlag) flagBoolToString(enabled bool) string {
-	if enabled {
-		return "enabled"
-	} else {
-		return "disabled"
-	}
-}
@@ -8,6 +8,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
 	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
 )
 
 type ListFeatureFlags struct {
@@ -21,10 +22,14 @@ func init() {
 }
 
 func (cmd *ListFeatureFlags) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["diff"] = &cliFlags.StringFlag{Name: "diff", Usage: T("Compare the feature flags with a file, as read by apply-feature-flags, and fail when they differ")}
+
 	return command_registry.CommandMetadata{
 		Name:        "feature-flags",
 		Description: T("Retrieve list of feature flags with status of each flag-able feature"),
-		Usage:       T("CF_NAME feature-flags"),
+		Usage:       T("CF_NAME feature-flags [--diff FEATURE_FLAGS_FILE]"),
+		Flags:       fs,
 	}
 }
 
@@ -47,6 +52,11 @@ func (cmd *ListFeatureFlags) SetDependency(deps command_registry.Dependency, plu
 }
 
 func (cmd *ListFeatureFlags) Execute(c flags.FlagContext) {
+	if c.String("diff") != "" {
+		cmd.diff(c.String("diff"))
+		return
+	}
+
 	cmd.ui.Say(T("Retrieving status of all flagged features as {{.Username}}...", map[string]interface{}{
 		"Username": terminal.EntityNameColor(cmd.config.Username())}))
 
@@ -64,7 +74,7 @@ func (cmd *ListFeatureFlags) Execute(c flags.FlagContext) {
 	for _, flag := range flags {
 		table.Add(
 			flag.Name,
-			cmd.flagBoolToString(flag.Enabled),
+			flagBoolToString(flag.Enabled),
 		)
 	}
 
@@ -72,10 +82,47 @@ func (cmd *ListFeatureFlags) Execute(c flags.FlagContext) {
 	return
 }
 
-func (cmd ListFeatureFlags) flagBoolToString(enabled bool) string {
+func flagBoolToString(enabled bool) string {
 	if enabled {
 		return "enabled"
 	} else {
 		return "disabled"
 	}
 }
+
+func (cmd ListFeatureFlags) diff(filePath string) {
+	cmd.ui.Say(T("Comparing feature flags with {{.Path}} as {{.Username}}...", map[string]interface{}{
+		"Path":     terminal.EntityNameColor(filePath),
+		"Username": terminal.EntityNameColor(cmd.config.Username())}))
+
+	declared, err := readFeatureFlagsFile(filePath)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	current, err := cmd.flagRepo.List()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	drift, unknown := compareFeatureFlags(declared, current)
+	if len(drift) == 0 && len(unknown) == 0 {
+		cmd.ui.Ok()
+		cmd.ui.Say(T("No differences found"))
+		return
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say(terminal.DiffRemovedColor("--- " + T("deployed")))
+	cmd.ui.Say(terminal.DiffAddedColor("+++ " + T("file: {{.Path}}", map[string]interface{}{"Path": filePath})))
+	for _, change := range drift {
+		cmd.ui.Say("%s", terminal.DiffRemovedColor("- "+change.Name+": "+flagBoolToString(!change.Enabled)))
+		cmd.ui.Say("%s", terminal.DiffAddedColor("+ "+change.Name+": "+flagBoolToString(change.Enabled)))
+	}
+	for _, name := range unknown {
+		cmd.ui.Say(terminal.DiffAddedColor("+ " + name + ": " + T("not supported by this Cloud Controller")))
+	}
+	cmd.ui.Say("")
+
+	cmd.ui.Failed(T("Feature flags differ from {{.Path}}", map[string]interface{}{"Path": filePath}))
+}
@@ -0,0 +1,69 @@
+package featureflag
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"gopkg.in/yaml.v2"
+)
+
+// featureFlagsFile is the document read by apply-feature-flags and
+// feature-flags --diff.
+type featureFlagsFile struct {
+	FeatureFlags map[string]bool `yaml:"feature_flags"`
+}
+
+// featureFlagDrift is a declared flag whose state differs from the
+// Cloud Controller.
+type featureFlagDrift struct {
+	Name    string
+	Enabled bool
+}
+
+func readFeatureFlagsFile(filePath string) (map[string]bool, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file := featureFlagsFile{}
+	err = yaml.Unmarshal(contents, &file)
+	if err != nil {
+		return nil, errors.New(T("Error reading {{.Path}}: {{.Err}}", map[string]interface{}{"Path": filePath, "Err": err.Error()}))
+	}
+
+	if len(file.FeatureFlags) == 0 {
+		return nil, errors.New(T("No feature flags declared in {{.Path}}", map[string]interface{}{"Path": filePath}))
+	}
+
+	return file.FeatureFlags, nil
+}
+
+// compareFeatureFlags returns the declared flags whose state differs from
+// current, with their declared state, and the declared flags the Cloud
+// Controller does not know about. Both are sorted by name.
+func compareFeatureFlags(declared map[string]bool, current []models.FeatureFlag) (drift []featureFlagDrift, unknown []string) {
+	currentByName := map[string]bool{}
+	for _, flag := range current {
+		currentByName[flag.Name] = flag.Enabled
+	}
+
+	names := []string{}
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		enabled, ok := currentByName[name]
+		if !ok {
+			unknown = append(unknown, name)
+		} else if enabled != declared[name] {
+			drift = append(drift, featureFlagDrift{Name: name, Enabled: declared[name]})
+		}
+	}
+	return
+}
@@ -2,6 +2,9 @@ package featureflag_test
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	fakeflag "github.com/cloudfoundry/cli/cf/api/feature_flags/fakes"
 	"github.com/cloudfoundry/cli/cf/command_registry"
@@ -98,6 +101,53 @@ var _ = Describe("feature-flags command", func() {
 			))
 		})
 
+		Context("with --diff", func() {
+			var dir string
+
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "feature-flags")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			writeFile := func(contents string) string {
+				path := filepath.Join(dir, "flags.yml")
+				Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+				return path
+			}
+
+			It("shows the drifted and unsupported flags and fails", func() {
+				path := writeFile("feature_flags:\n  user_org_creation: false\n  app_scaling: true\n  service_instance_sharing: true\n")
+
+				runCommand("--diff", path)
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"Comparing feature flags with", path, "my-user"},
+					[]string{"--- deployed"},
+					[]string{"+++ file:", path},
+					[]string{"- user_org_creation: enabled"},
+					[]string{"+ user_org_creation: disabled"},
+					[]string{"+ service_instance_sharing: not supported by this Cloud Controller"},
+					[]string{"FAILED"},
+					[]string{"Feature flags differ from", path},
+				))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"app_scaling"}))
+			})
+
+			It("says when there are no differences", func() {
+				runCommand("--diff", writeFile("feature_flags:\n  route_creation: false\n"))
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"OK"},
+					[]string{"No differences found"},
+				))
+			})
+		})
+
 		Context("when an error occurs", func() {
 			BeforeEach(func() {
 				flagRepo.ListReturns(nil, errors.New("An error occurred."))
@@ -0,0 +1,126 @@
+package organization
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type OrgUsage struct {
+	ui                 terminal.UI
+	config             core_config.Reader
+	orgReq             requirements.OrganizationRequirement
+	appSummaryRepo     api.AppSummaryRepository
+	serviceSummaryRepo api.ServiceSummaryRepository
+}
+
+func init() {
+	command_registry.Register(&OrgUsage{})
+}
+
+func (cmd *OrgUsage) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["warn-at"] = &cliFlags.IntFlag{Name: "warn-at", Usage: T("Warn about limits used at or above this percentage (Default: 80)")}
+
+	return command_registry.CommandMetadata{
+		Name:        "org-usage",
+		Description: T("Show how much of its quota an org uses, by space"),
+		Usage: T(`CF_NAME org-usage ORG [--warn-at PERCENT]
+
+   Memory is the memory of the running app instances and instance memory the memory of the
+   largest app instance. User-provided service instances do not count against the quota.`),
+		Flags: fs,
+	}
+}
+
+func (cmd *OrgUsage) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("org-usage"))
+	}
+
+	if fc.IsSet("warn-at") && (fc.Int("warn-at") < 1 || fc.Int("warn-at") > 100) {
+		cmd.ui.Failed(T("Incorrect Usage. --warn-at must be between 1 and 100\n\n") + command_registry.Commands.CommandUsage("org-usage"))
+	}
+
+	cmd.orgReq = requirementsFactory.NewOrganizationRequirement(fc.Args()[0])
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		cmd.orgReq,
+	}
+	return
+}
+
+func (cmd *OrgUsage) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.serviceSummaryRepo = deps.RepoLocator.GetServiceSummaryRepository()
+	return cmd
+}
+
+func (cmd *OrgUsage) Execute(c flags.FlagContext) {
+	org := cmd.orgReq.GetOrganization()
+
+	warnAt := ui_helpers.DefaultQuotaWarnAt
+	if c.IsSet("warn-at") {
+		warnAt = c.Int("warn-at")
+	}
+
+	cmd.ui.Say(T("Getting quota usage of org {{.OrgName}} as {{.Username}}...",
+		map[string]interface{}{
+			"OrgName":  terminal.EntityNameColor(org.Name),
+			"Username": terminal.EntityNameColor(cmd.config.Username())}))
+
+	total := models.QuotaUsage{}
+	spaceUsages := []models.QuotaUsage{}
+	for _, space := range org.Spaces {
+		apps, err := cmd.appSummaryRepo.GetSummariesInSpace(space.Guid)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+
+		serviceInstances, err := cmd.serviceSummaryRepo.GetSummariesInSpace(space.Guid)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+
+		usage := models.NewQuotaUsage(apps, serviceInstances)
+		spaceUsages = append(spaceUsages, usage)
+		total = total.Add(usage)
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.ui.Say(T("quota: {{.QuotaName}}", map[string]interface{}{"QuotaName": terminal.EntityNameColor(org.QuotaDefinition.Name)}))
+	ui_helpers.PrintQuotaUsage(cmd.ui, total, org.QuotaDefinition, warnAt, T("org {{.OrgName}}", map[string]interface{}{"OrgName": org.Name}))
+
+	if len(org.Spaces) == 0 {
+		return
+	}
+
+	cmd.ui.Say("")
+	table := terminal.NewTable(cmd.ui, []string{T("space"), T("memory"), T("share of org memory"), T("instance memory"), T("routes"), T("service instances")})
+	for i, space := range org.Spaces {
+		usage := spaceUsages[i]
+		table.Add(
+			space.Name,
+			formatters.ByteSize(usage.Memory*formatters.MEGABYTE),
+			fmt.Sprintf("%d%%", ui_helpers.QuotaPercent(usage.Memory, total.Memory)),
+			formatters.ByteSize(usage.InstanceMemory*formatters.MEGABYTE),
+			fmt.Sprintf("%d", usage.Routes),
+			fmt.Sprintf("%d", usage.ServiceInstances),
+		)
+	}
+	table.Print()
+}
@@ -0,0 +1,126 @@
+package organization_test
+
+import (
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("org-usage command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		serviceSummaryRepo  *testapi.FakeServiceSummaryRepo
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceSummaryRepository(serviceSummaryRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("org-usage").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		org := models.Organization{}
+		org.Name = "my-org"
+		org.Guid = "my-org-guid"
+		org.QuotaDefinition = models.NewQuotaFields("default", 4096, -1, 10, -1, true)
+		org.Spaces = []models.SpaceFields{
+			{Name: "development", Guid: "development-guid"},
+			{Name: "production", Guid: "production-guid"},
+		}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, Organization: org}
+
+		devApp := models.Application{}
+		devApp.Memory = 512
+		devApp.RunningInstances = 2
+		devApp.Routes = []models.RouteSummary{{Guid: "dev-route-guid"}}
+
+		prodApp := models.Application{}
+		prodApp.Memory = 1024
+		prodApp.RunningInstances = 2
+		prodApp.Routes = []models.RouteSummary{{Guid: "prod-route-1-guid"}, {Guid: "prod-route-2-guid"}}
+
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{
+			GetSummariesInSpaceApps: map[string][]models.Application{
+				"development-guid": {devApp},
+				"production-guid":  {prodApp},
+			},
+		}
+
+		managed := models.ServiceInstance{}
+		managed.ServicePlan.Guid = "plan-guid"
+		serviceSummaryRepo = &testapi.FakeServiceSummaryRepo{
+			GetSummariesInSpaceInstances: map[string][]models.ServiceInstance{
+				"production-guid": {managed, models.ServiceInstance{}},
+			},
+		}
+
+		deps = command_registry.NewDependency()
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("org-usage", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-org")).To(BeFalse())
+		})
+
+		It("fails with usage when not provided exactly one arg", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+
+		It("requires the org", func() {
+			Expect(runCommand("my-org")).To(BeTrue())
+			Expect(requirementsFactory.OrganizationName).To(Equal("my-org"))
+		})
+	})
+
+	It("compares the usage of all spaces with the org quota and breaks it down by space", func() {
+		runCommand("my-org")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting quota usage of org", "my-org", "my-user"},
+			[]string{"OK"},
+			[]string{"quota:", "default"},
+			[]string{"memory", "3G", "4G", "75%"},
+			[]string{"instance memory", "1G", "unlimited"},
+			[]string{"routes", "3", "10", "30%"},
+			[]string{"service instances", "1", "unlimited"},
+			[]string{"space", "memory", "share of org memory", "instance memory", "routes", "service instances"},
+			[]string{"development", "1G", "33%", "512M", "1", "0"},
+			[]string{"production", "2G", "66%", "1G", "2", "1"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"usage of org my-org is at"}))
+	})
+
+	It("warns about the limits used above the threshold", func() {
+		runCommand("--warn-at", "70", "my-org")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"memory usage of org my-org is at 75% of its quota"},
+		))
+	})
+})
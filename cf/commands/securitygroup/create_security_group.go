@@ -38,7 +38,9 @@ func (cmd *CreateSecurityGroup) MetaData() command_registry.CommandMetadata {
        "destination": "10.244.1.18",
        "ports": "3306"
      }
-   ]`)
+   ]
+
+   The rules are validated before the security group is created, and overly broad rules are warned about.`)
 
 	return command_registry.CommandMetadata{
 		Name:        "create-security-group",
@@ -80,6 +82,8 @@ Valid json file example:
 ]`, map[string]interface{}{"JSONFile": pathToJSONFile}))
 	}
 
+	checkRules(cmd.ui, pathToJSONFile, rules)
+
 	cmd.ui.Say(T("Creating security group {{.security_group}} as {{.username}}",
 		map[string]interface{}{
 			"security_group": terminal.EntityNameColor(name),
@@ -130,6 +130,35 @@ var _ = Describe("create-security-group command", func() {
 			})
 		})
 
+		Context("when the file specified has invalid rules", func() {
+			BeforeEach(func() {
+				tempFile.Write([]byte(`[{"protocol":"icmp","destination":"10.0.0.1","ports":"80"}]`))
+			})
+
+			It("fails listing the problems without creating the security group", func() {
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid security group rules"},
+					[]string{"rule 1", "ports are only allowed for protocols tcp and udp"},
+					[]string{"rule 1", "type is required for protocol icmp"},
+				))
+				Expect(securityGroupRepo.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the file specified has overly broad rules", func() {
+			BeforeEach(func() {
+				tempFile.Write([]byte(`[{"protocol":"all","destination":"0.0.0.0/0"}]`))
+			})
+
+			It("warns about them and creates the security group", func() {
+				Expect(ui.WarnOutputs).To(ContainSubstrings(
+					[]string{"rule 1 allows all traffic on all ports to any destination"},
+				))
+				Expect(securityGroupRepo.CreateCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when the file specified has invalid json", func() {
 			BeforeEach(func() {
 				tempFile.Write([]byte(`[{noquote: thiswontwork}]`))
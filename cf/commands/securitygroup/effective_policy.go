@@ -0,0 +1,93 @@
+package securitygroup
+
+import (
+	"net"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+// EffectiveSecurityGroup is a security group that applies to the apps of a
+// space, along with the ways it is bound.
+type EffectiveSecurityGroup struct {
+	models.SecurityGroupFields
+	BoundAs []string
+}
+
+// EffectiveSecurityGroups returns the union of the globally bound security
+// groups and the groups bound to the space. The Cloud Controller applies
+// space-bound groups to running apps only, so the staging set consists of the
+// staging security groups alone.
+func EffectiveSecurityGroups(runningRepo running.RunningSecurityGroupsRepo, stagingRepo staging.StagingSecurityGroupsRepo, space models.Space, forStaging bool) ([]EffectiveSecurityGroup, error) {
+	groups := []EffectiveSecurityGroup{}
+	indexByGuid := map[string]int{}
+
+	add := func(fields []models.SecurityGroupFields, boundAs string) {
+		for _, group := range fields {
+			index, ok := indexByGuid[group.Guid]
+			if !ok {
+				index = len(groups)
+				indexByGuid[group.Guid] = index
+				groups = append(groups, EffectiveSecurityGroup{SecurityGroupFields: group})
+			}
+			groups[index].BoundAs = append(groups[index].BoundAs, boundAs)
+		}
+	}
+
+	if forStaging {
+		stagingGroups, err := stagingRepo.List()
+		if err != nil {
+			return nil, err
+		}
+		add(stagingGroups, T("staging"))
+		return groups, nil
+	}
+
+	runningGroups, err := runningRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	add(runningGroups, T("running"))
+	add(space.SecurityGroups, T("space"))
+
+	return groups, nil
+}
+
+// RuleAllows reports whether a valid rule allows traffic over the protocol to
+// the IPv4 address and port. The port is ignored for icmp.
+func RuleAllows(rule map[string]interface{}, protocol string, ip net.IP, port int) bool {
+	ruleProtocol, _ := rule["protocol"].(string)
+	ruleProtocol = strings.ToLower(ruleProtocol)
+	if ruleProtocol != "all" && ruleProtocol != protocol {
+		return false
+	}
+
+	destination, _ := rule["destination"].(string)
+	first, last, err := parseDestination(destination)
+	if err != nil || ip.To4() == nil {
+		return false
+	}
+	address := ipToUint32(ip)
+	if address < first || address > last {
+		return false
+	}
+
+	if ruleProtocol != "tcp" && ruleProtocol != "udp" {
+		return true
+	}
+
+	ports, _ := rule["ports"].(string)
+	ranges, err := parsePorts(ports)
+	if err != nil {
+		return false
+	}
+	for _, r := range ranges {
+		if port >= r.first && port <= r.last {
+			return true
+		}
+	}
+	return false
+}
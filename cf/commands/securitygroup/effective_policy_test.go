@@ -0,0 +1,82 @@
+package securitygroup_test
+
+import (
+	"net"
+
+	runningFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running/fakes"
+	stagingFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging/fakes"
+	. "github.com/cloudfoundry/cli/cf/commands/securitygroup"
+	"github.com/cloudfoundry/cli/cf/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("effective security group policy", func() {
+	Describe("EffectiveSecurityGroups", func() {
+		var (
+			runningRepo *runningFakes.FakeRunningSecurityGroupsRepo
+			stagingRepo *stagingFakes.FakeStagingSecurityGroupsRepo
+			space       models.Space
+		)
+
+		BeforeEach(func() {
+			runningRepo = &runningFakes.FakeRunningSecurityGroupsRepo{}
+			runningRepo.ListReturns([]models.SecurityGroupFields{
+				{Name: "public", Guid: "public-guid"},
+				{Name: "dns", Guid: "dns-guid"},
+			}, nil)
+
+			stagingRepo = &stagingFakes.FakeStagingSecurityGroupsRepo{}
+			stagingRepo.ListReturns([]models.SecurityGroupFields{{Name: "mirror", Guid: "mirror-guid"}}, nil)
+
+			space = models.Space{}
+			space.SecurityGroups = []models.SecurityGroupFields{
+				{Name: "db", Guid: "db-guid"},
+				{Name: "dns", Guid: "dns-guid"},
+			}
+		})
+
+		It("unions the running security groups and the groups bound to the space", func() {
+			groups, err := EffectiveSecurityGroups(runningRepo, stagingRepo, space, false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(3))
+			Expect(groups[0].Name).To(Equal("public"))
+			Expect(groups[0].BoundAs).To(Equal([]string{"running"}))
+			Expect(groups[1].Name).To(Equal("dns"))
+			Expect(groups[1].BoundAs).To(Equal([]string{"running", "space"}))
+			Expect(groups[2].Name).To(Equal("db"))
+			Expect(groups[2].BoundAs).To(Equal([]string{"space"}))
+		})
+
+		It("returns only the staging security groups for staging", func() {
+			groups, err := EffectiveSecurityGroups(runningRepo, stagingRepo, space, true)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Name).To(Equal("mirror"))
+			Expect(groups[0].BoundAs).To(Equal([]string{"staging"}))
+		})
+	})
+
+	Describe("RuleAllows", func() {
+		ip := net.ParseIP("10.2.3.4")
+
+		It("matches the protocol, destination and ports", func() {
+			rule := map[string]interface{}{"protocol": "tcp", "destination": "10.2.0.0/16", "ports": "80,5000-6000"}
+
+			Expect(RuleAllows(rule, "tcp", ip, 5432)).To(BeTrue())
+			Expect(RuleAllows(rule, "tcp", ip, 443)).To(BeFalse())
+			Expect(RuleAllows(rule, "udp", ip, 5432)).To(BeFalse())
+			Expect(RuleAllows(rule, "tcp", net.ParseIP("10.3.0.1"), 80)).To(BeFalse())
+		})
+
+		It("matches any protocol and port for protocol all", func() {
+			rule := map[string]interface{}{"protocol": "all", "destination": "10.2.3.1-10.2.3.9"}
+
+			Expect(RuleAllows(rule, "udp", ip, 53)).To(BeTrue())
+			Expect(RuleAllows(rule, "icmp", ip, 0)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,322 @@
+package securitygroup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+var validRuleFields = map[string]bool{
+	"protocol":    true,
+	"destination": true,
+	"ports":       true,
+	"type":        true,
+	"code":        true,
+	"log":         true,
+	"description": true,
+}
+
+// ValidateRules checks every rule the way the Cloud Controller does and
+// returns one error per problem found, naming the rule by its position.
+func ValidateRules(rules []map[string]interface{}) []error {
+	errs := []error{}
+	for i, rule := range rules {
+		for _, problem := range validateRule(rule) {
+			errs = append(errs, errors.New(T("rule {{.Index}}: {{.Problem}}",
+				map[string]interface{}{"Index": i + 1, "Problem": problem})))
+		}
+	}
+	return errs
+}
+
+// checkRules fails listing every invalid rule and warns about the valid rules
+// that LintRules flags.
+func checkRules(ui terminal.UI, pathToJSONFile string, rules []map[string]interface{}) {
+	errs := ValidateRules(rules)
+	if len(errs) > 0 {
+		problems := make([]string, 0, len(errs))
+		for _, err := range errs {
+			problems = append(problems, "  "+err.Error())
+		}
+		ui.Failed(T("Invalid security group rules in {{.JSONFile}}:\n{{.Problems}}",
+			map[string]interface{}{"JSONFile": pathToJSONFile, "Problems": strings.Join(problems, "\n")}))
+	}
+
+	for _, warning := range LintRules(rules) {
+		ui.Warn(T("Warning: {{.Warning}}", map[string]interface{}{"Warning": warning}))
+	}
+}
+
+func validateRule(rule map[string]interface{}) []string {
+	problems := []string{}
+
+	fields := make([]string, 0, len(rule))
+	for field := range rule {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !validRuleFields[field] {
+			problems = append(problems, T("unknown field '{{.Field}}'", map[string]interface{}{"Field": field}))
+		}
+	}
+
+	protocol, _ := rule["protocol"].(string)
+	switch protocol {
+	case "tcp", "udp", "icmp", "all":
+	case "":
+		problems = append(problems, T("protocol is required"))
+	default:
+		problems = append(problems, T("protocol '{{.Protocol}}' must be tcp, udp, icmp or all", map[string]interface{}{"Protocol": protocol}))
+	}
+
+	destination, _ := rule["destination"].(string)
+	if destination == "" {
+		problems = append(problems, T("destination is required"))
+	} else if _, _, err := parseDestination(destination); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	ports, hasPorts := rule["ports"]
+	switch protocol {
+	case "tcp", "udp":
+		portsString, _ := ports.(string)
+		if !hasPorts {
+			problems = append(problems, T("ports are required for protocol {{.Protocol}}", map[string]interface{}{"Protocol": protocol}))
+		} else if _, err := parsePorts(portsString); err != nil {
+			problems = append(problems, err.Error())
+		}
+	default:
+		if hasPorts {
+			problems = append(problems, T("ports are only allowed for protocols tcp and udp"))
+		}
+	}
+
+	for _, field := range []string{"type", "code"} {
+		value, ok := rule[field]
+		if protocol != "icmp" {
+			if ok {
+				problems = append(problems, T("{{.Field}} is only allowed for protocol icmp", map[string]interface{}{"Field": field}))
+			}
+			continue
+		}
+
+		if !ok {
+			problems = append(problems, T("{{.Field}} is required for protocol icmp", map[string]interface{}{"Field": field}))
+			continue
+		}
+		number, isNumber := value.(float64)
+		if !isNumber || number != float64(int(number)) || number < -1 || number > 255 {
+			problems = append(problems, T("{{.Field}} must be an integer from -1 to 255, -1 meaning any", map[string]interface{}{"Field": field}))
+		}
+	}
+
+	if value, ok := rule["log"]; ok {
+		if _, isBool := value.(bool); !isBool {
+			problems = append(problems, T("log must be true or false"))
+		}
+	}
+
+	if value, ok := rule["description"]; ok {
+		if _, isString := value.(string); !isString {
+			problems = append(problems, T("description must be a string"))
+		}
+	}
+
+	return problems
+}
+
+// parseDestination parses a single IPv4 address, a CIDR or a range such as
+// 10.0.0.1-10.0.0.9, returning the first and last address it covers.
+func parseDestination(destination string) (first, last uint32, err error) {
+	invalid := errors.New(T("destination '{{.Destination}}' must be an IPv4 address, a CIDR such as 10.0.0.0/24 or a range such as 10.0.0.1-10.0.0.9",
+		map[string]interface{}{"Destination": destination}))
+
+	if strings.Contains(destination, "/") {
+		_, network, parseErr := net.ParseCIDR(destination)
+		if parseErr != nil || network.IP.To4() == nil {
+			return 0, 0, invalid
+		}
+		first = ipToUint32(network.IP)
+		last = first | ^ipToUint32(net.IP(network.Mask))
+		return
+	}
+
+	bounds := strings.Split(destination, "-")
+	if len(bounds) > 2 {
+		return 0, 0, invalid
+	}
+
+	ips := []uint32{}
+	for _, bound := range bounds {
+		ip := net.ParseIP(strings.TrimSpace(bound)).To4()
+		if ip == nil {
+			return 0, 0, invalid
+		}
+		ips = append(ips, ipToUint32(ip))
+	}
+
+	first, last = ips[0], ips[len(ips)-1]
+	if first > last {
+		return 0, 0, errors.New(T("destination range '{{.Destination}}' must start with the lower address", map[string]interface{}{"Destination": destination}))
+	}
+	return
+}
+
+func ipToUint32(ip net.IP) uint32 {
+	ip = ip.To4()
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+}
+
+type portRange struct {
+	first, last int
+}
+
+// parsePorts parses a port (443), a range (8080-8090) or a comma-delimited
+// list of them (80,443).
+func parsePorts(ports string) ([]portRange, error) {
+	invalid := errors.New(T("ports '{{.Ports}}' must be a port, a range such as 8080-8090 or a comma-delimited list of them, using ports 1 to 65535",
+		map[string]interface{}{"Ports": ports}))
+
+	ranges := []portRange{}
+	for _, part := range strings.Split(ports, ",") {
+		bounds := strings.Split(strings.TrimSpace(part), "-")
+		if len(bounds) > 2 {
+			return nil, invalid
+		}
+
+		numbers := []int{}
+		for _, bound := range bounds {
+			port, err := strconv.Atoi(strings.TrimSpace(bound))
+			if err != nil || port < 1 || port > 65535 {
+				return nil, invalid
+			}
+			numbers = append(numbers, port)
+		}
+
+		r := portRange{first: numbers[0], last: numbers[len(numbers)-1]}
+		if r.first > r.last {
+			return nil, invalid
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// LintRules returns warnings for valid rules that are likely broader or more
+// redundant than intended.
+func LintRules(rules []map[string]interface{}) []string {
+	warnings := []string{}
+	seen := map[string]int{}
+
+	for i, rule := range rules {
+		key := ruleKey(rule)
+		if previous, ok := seen[key]; ok {
+			warnings = append(warnings, T("rule {{.Index}} duplicates rule {{.PreviousIndex}}",
+				map[string]interface{}{"Index": i + 1, "PreviousIndex": previous + 1}))
+		} else {
+			seen[key] = i
+		}
+
+		destination, _ := rule["destination"].(string)
+		first, last, err := parseDestination(destination)
+		if err != nil || first != 0 || last != 0xffffffff {
+			continue
+		}
+
+		protocol, _ := rule["protocol"].(string)
+		if allPorts(protocol, rule) {
+			warnings = append(warnings, T("rule {{.Index}} allows {{.Protocol}} traffic on all ports to any destination ({{.Destination}})",
+				map[string]interface{}{"Index": i + 1, "Protocol": protocol, "Destination": destination}))
+		}
+	}
+
+	return warnings
+}
+
+func allPorts(protocol string, rule map[string]interface{}) bool {
+	switch protocol {
+	case "all":
+		return true
+	case "icmp":
+		return false
+	}
+
+	ports, _ := rule["ports"].(string)
+	ranges, err := parsePorts(ports)
+	if err != nil {
+		return false
+	}
+	for _, r := range ranges {
+		if r.first == 1 && r.last == 65535 {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffRules compares the current rules of a security group with the desired
+// ones, ignoring the order of the rules and of their fields.
+func DiffRules(current, desired []map[string]interface{}) (added, removed []map[string]interface{}, unchanged int) {
+	remaining := map[string]int{}
+	for _, rule := range current {
+		remaining[ruleKey(rule)]++
+	}
+
+	for _, rule := range desired {
+		key := ruleKey(rule)
+		if remaining[key] > 0 {
+			remaining[key]--
+			unchanged++
+		} else {
+			added = append(added, rule)
+		}
+	}
+
+	for _, rule := range current {
+		key := ruleKey(rule)
+		if remaining[key] > 0 {
+			remaining[key]--
+			removed = append(removed, rule)
+		}
+	}
+
+	return
+}
+
+// ruleKey returns a canonical form of a rule, so that rules differing only in
+// field order, spacing in ports or an explicit "log": false compare equal.
+func ruleKey(rule map[string]interface{}) string {
+	normalized := map[string]interface{}{}
+	for field, value := range rule {
+		normalized[field] = value
+	}
+
+	if protocol, ok := normalized["protocol"].(string); ok {
+		normalized["protocol"] = strings.ToLower(protocol)
+	}
+	if ports, ok := normalized["ports"].(string); ok {
+		normalized["ports"] = strings.Replace(ports, " ", "", -1)
+	}
+	if log, ok := normalized["log"].(bool); ok && !log {
+		delete(normalized, "log")
+	}
+
+	return formatRule(normalized)
+}
+
+// formatRule returns the rule as compact JSON with its fields sorted.
+func formatRule(rule map[string]interface{}) string {
+	jsonBytes, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Sprintf("%v", rule)
+	}
+	return string(jsonBytes)
+}
@@ -0,0 +1,105 @@
+package securitygroup_test
+
+import (
+	. "github.com/cloudfoundry/cli/cf/commands/securitygroup"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("security group rules", func() {
+	errorMessages := func(errs []error) []string {
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return messages
+	}
+
+	Describe("ValidateRules", func() {
+		It("accepts valid rules", func() {
+			rules := []map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.0.1", "ports": "443"},
+				{"protocol": "udp", "destination": "10.0.0.0/8", "ports": "53,8080-8090", "log": true},
+				{"protocol": "icmp", "destination": "10.0.0.1-10.0.0.9", "type": float64(-1), "code": float64(0)},
+				{"protocol": "all", "destination": "192.168.0.0/16", "description": "internal"},
+			}
+
+			Expect(ValidateRules(rules)).To(BeEmpty())
+		})
+
+		It("names every problem by the position of its rule", func() {
+			rules := []map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.0.1", "ports": "443"},
+				{"protocol": "sctp", "destination": "10.0.0.9-10.0.0.1", "port": "80"},
+				{"protocol": "tcp", "destination": "not-an-ip", "ports": "0-80"},
+				{"protocol": "icmp", "destination": "10.0.0.1", "type": float64(256), "code": 1.5, "log": "yes"},
+			}
+
+			Expect(errorMessages(ValidateRules(rules))).To(Equal([]string{
+				"rule 2: unknown field 'port'",
+				"rule 2: protocol 'sctp' must be tcp, udp, icmp or all",
+				"rule 2: destination range '10.0.0.9-10.0.0.1' must start with the lower address",
+				"rule 3: destination 'not-an-ip' must be an IPv4 address, a CIDR such as 10.0.0.0/24 or a range such as 10.0.0.1-10.0.0.9",
+				"rule 3: ports '0-80' must be a port, a range such as 8080-8090 or a comma-delimited list of them, using ports 1 to 65535",
+				"rule 4: type must be an integer from -1 to 255, -1 meaning any",
+				"rule 4: code must be an integer from -1 to 255, -1 meaning any",
+				"rule 4: log must be true or false",
+			}))
+		})
+
+		It("requires a protocol, a destination and ports for tcp and udp", func() {
+			Expect(errorMessages(ValidateRules([]map[string]interface{}{{"protocol": "udp"}}))).To(Equal([]string{
+				"rule 1: destination is required",
+				"rule 1: ports are required for protocol udp",
+			}))
+			Expect(errorMessages(ValidateRules([]map[string]interface{}{{"destination": "10.0.0.1"}}))).To(Equal([]string{
+				"rule 1: protocol is required",
+			}))
+		})
+	})
+
+	Describe("LintRules", func() {
+		It("warns about rules open to any destination on all ports", func() {
+			rules := []map[string]interface{}{
+				{"protocol": "all", "destination": "0.0.0.0/0"},
+				{"protocol": "tcp", "destination": "0.0.0.0-255.255.255.255", "ports": "1-65535"},
+				{"protocol": "tcp", "destination": "0.0.0.0/0", "ports": "443"},
+				{"protocol": "tcp", "destination": "10.0.0.0/8", "ports": "1-65535"},
+			}
+
+			Expect(LintRules(rules)).To(Equal([]string{
+				"rule 1 allows all traffic on all ports to any destination (0.0.0.0/0)",
+				"rule 2 allows tcp traffic on all ports to any destination (0.0.0.0-255.255.255.255)",
+			}))
+		})
+
+		It("warns about duplicated rules", func() {
+			rules := []map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.0.1", "ports": "80, 443"},
+				{"destination": "10.0.0.1", "protocol": "tcp", "ports": "80,443", "log": false},
+			}
+
+			Expect(LintRules(rules)).To(Equal([]string{"rule 2 duplicates rule 1"}))
+		})
+	})
+
+	Describe("DiffRules", func() {
+		It("returns the added and removed rules regardless of their order", func() {
+			current := []map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.0.1", "ports": "443"},
+				{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+			}
+			desired := []map[string]interface{}{
+				{"protocol": "tcp", "destination": "10.0.0.3", "ports": "5432"},
+				{"ports": "443", "destination": "10.0.0.1", "protocol": "tcp"},
+			}
+
+			added, removed, unchanged := DiffRules(current, desired)
+
+			Expect(added).To(Equal([]map[string]interface{}{desired[0]}))
+			Expect(removed).To(Equal([]map[string]interface{}{current[1]}))
+			Expect(unchanged).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,160 @@
+package securitygroup
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type SecurityGroupCheck struct {
+	ui                       terminal.UI
+	configRepo               core_config.Reader
+	runningSecurityGroupRepo running.RunningSecurityGroupsRepo
+	stagingSecurityGroupRepo staging.StagingSecurityGroupsRepo
+	spaceReq                 requirements.SpaceRequirement
+}
+
+func init() {
+	command_registry.Register(&SecurityGroupCheck{})
+}
+
+func (cmd *SecurityGroupCheck) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["staging"] = &cliFlags.BoolFlag{Name: "staging", Usage: T("Check the security groups applied while staging instead of those applied to running apps")}
+	fs["protocol"] = &cliFlags.StringFlag{Name: "protocol", Usage: T("Protocol of the traffic: tcp, udp or icmp (Default: tcp)")}
+
+	primaryUsage := T("CF_NAME security-group-check SPACE DESTINATION_IP[:PORT] [--protocol tcp|udp|icmp] [--staging]")
+	secondaryUsage := T(`   Shows which security groups allow the apps in the space to reach the destination.
+   Running apps get the running security groups and the groups bound to the space,
+   staging apps get the staging security groups. A port is required for tcp and udp.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME security-group-check my-space 10.2.3.4:5432
+   CF_NAME security-group-check my-space 10.2.3.4 --protocol icmp`)
+
+	return command_registry.CommandMetadata{
+		Name:        "security-group-check",
+		Description: T("Check whether the security groups of a space allow traffic to a destination"),
+		Usage:       strings.Join([]string{primaryUsage, secondaryUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *SecurityGroupCheck) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SPACE and DESTINATION_IP[:PORT] as arguments\n\n") + command_registry.Commands.CommandUsage("security-group-check"))
+	}
+
+	cmd.spaceReq = requirementsFactory.NewSpaceRequirement(fc.Args()[0])
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedOrgRequirement(),
+		cmd.spaceReq,
+	}
+	return reqs, nil
+}
+
+func (cmd *SecurityGroupCheck) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.configRepo = deps.Config
+	cmd.runningSecurityGroupRepo = deps.RepoLocator.GetRunningSecurityGroupsRepository()
+	cmd.stagingSecurityGroupRepo = deps.RepoLocator.GetStagingSecurityGroupsRepository()
+	return cmd
+}
+
+func (cmd *SecurityGroupCheck) Execute(context flags.FlagContext) {
+	protocol := strings.ToLower(context.String("protocol"))
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	if protocol != "tcp" && protocol != "udp" && protocol != "icmp" {
+		cmd.ui.Failed(T("Incorrect Usage. --protocol must be tcp, udp or icmp\n\n") + command_registry.Commands.CommandUsage("security-group-check"))
+	}
+
+	destination := context.Args()[1]
+	ip, port, err := parseCheckDestination(destination, protocol)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	space := cmd.spaceReq.GetSpace()
+	phase := T("running")
+	if context.Bool("staging") {
+		phase = T("staging")
+	}
+
+	cmd.ui.Say(T("Checking {{.Protocol}} traffic from {{.Phase}} apps in space {{.SpaceName}} to {{.Destination}} as {{.Username}}...",
+		map[string]interface{}{
+			"Protocol":    protocol,
+			"Phase":       phase,
+			"SpaceName":   terminal.EntityNameColor(space.Name),
+			"Destination": terminal.EntityNameColor(destination),
+			"Username":    terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+
+	groups, err := EffectiveSecurityGroups(cmd.runningSecurityGroupRepo, cmd.stagingSecurityGroupRepo, space, context.Bool("staging"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	table := terminal.NewTable(cmd.ui, []string{T("security group"), T("bound as"), T("rule")})
+	allowed := false
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if RuleAllows(rule, protocol, ip, port) {
+				allowed = true
+				table.Add(group.Name, strings.Join(group.BoundAs, ", "), formatRule(rule))
+			}
+		}
+	}
+
+	if !allowed {
+		cmd.ui.Say(terminal.FailureColor(T("Denied: no security group allows this traffic")))
+		return
+	}
+
+	cmd.ui.Say(terminal.SuccessColor(T("Allowed by:")))
+	table.Print()
+}
+
+func parseCheckDestination(destination, protocol string) (net.IP, int, error) {
+	host, portString := destination, ""
+	if i := strings.LastIndex(destination, ":"); i >= 0 {
+		host, portString = destination[:i], destination[i+1:]
+	}
+
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		return nil, 0, errors.New(T("Destination '{{.Destination}}' must be an IPv4 address, optionally followed by :PORT",
+			map[string]interface{}{"Destination": destination}))
+	}
+
+	if portString == "" {
+		if protocol == "icmp" {
+			return ip, 0, nil
+		}
+		return nil, 0, errors.New(T("A port is required to check {{.Protocol}} traffic, e.g. {{.Example}}",
+			map[string]interface{}{"Protocol": protocol, "Example": host + ":443"}))
+	}
+
+	port, err := strconv.Atoi(portString)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, 0, errors.New(T("Port '{{.Port}}' must be a number from 1 to 65535", map[string]interface{}{"Port": portString}))
+	}
+	return ip, port, nil
+}
@@ -0,0 +1,141 @@
+package securitygroup_test
+
+import (
+	runningFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running/fakes"
+	stagingFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("security-group-check command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		runningRepo         *runningFakes.FakeRunningSecurityGroupsRepo
+		stagingRepo         *stagingFakes.FakeStagingSecurityGroupsRepo
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          core_config.Repository
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetRunningSecurityGroupRepository(runningRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStagingSecurityGroupRepository(stagingRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("security-group-check").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		space := models.Space{}
+		space.Name = "my-space"
+		space.SecurityGroups = []models.SecurityGroupFields{{
+			Name:  "db",
+			Guid:  "db-guid",
+			Rules: []map[string]interface{}{{"protocol": "tcp", "destination": "10.2.0.0/16", "ports": "5432"}},
+		}}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedOrgSuccess: true, Space: space}
+
+		runningRepo = &runningFakes.FakeRunningSecurityGroupsRepo{}
+		runningRepo.ListReturns([]models.SecurityGroupFields{{
+			Name:  "private",
+			Guid:  "private-guid",
+			Rules: []map[string]interface{}{{"protocol": "all", "destination": "10.0.0.0-10.255.255.255"}},
+		}}, nil)
+
+		stagingRepo = &stagingFakes.FakeStagingSecurityGroupsRepo{}
+		stagingRepo.ListReturns([]models.SecurityGroupFields{{
+			Name:  "mirror",
+			Guid:  "mirror-guid",
+			Rules: []map[string]interface{}{{"protocol": "tcp", "destination": "10.9.9.9", "ports": "443"}},
+		}}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("security-group-check", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-space", "10.2.3.4:5432")).To(BeFalse())
+		})
+
+		It("requires the space", func() {
+			Expect(runCommand("my-space", "10.2.3.4:5432")).To(BeTrue())
+			Expect(requirementsFactory.SpaceName).To(Equal("my-space"))
+		})
+
+		It("fails with usage when not given a destination", func() {
+			runCommand("my-space")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires SPACE and DESTINATION_IP[:PORT]"},
+			))
+		})
+	})
+
+	It("shows every group and rule allowing the destination to running apps", func() {
+		runCommand("my-space", "10.2.3.4:5432")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Checking tcp traffic from running apps in space", "my-space", "10.2.3.4:5432", "my-user"},
+			[]string{"OK"},
+			[]string{"Allowed by"},
+			[]string{"security group", "bound as", "rule"},
+			[]string{"private", "running", `"protocol":"all"`},
+			[]string{"db", "space", `"ports":"5432"`},
+		))
+	})
+
+	It("says the traffic is denied when no rule allows it", func() {
+		runCommand("my-space", "192.168.0.1:5432")
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"Denied", "no security group allows this traffic"}))
+	})
+
+	It("checks the staging security groups with --staging", func() {
+		runCommand("my-space", "10.9.9.9:443", "--staging")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"from staging apps"},
+			[]string{"mirror", "staging"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"private"}))
+	})
+
+	It("checks icmp traffic without a port", func() {
+		runCommand("my-space", "10.2.3.4", "--protocol", "icmp")
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"private", "running"}))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"db", "space"}))
+	})
+
+	It("fails when a port is missing for tcp", func() {
+		runCommand("my-space", "10.2.3.4")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"A port is required to check tcp traffic"},
+		))
+	})
+
+	It("fails when the destination is not an IP address", func() {
+		runCommand("my-space", "db.example.com:5432")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"must be an IPv4 address"},
+		))
+	})
+})
@@ -0,0 +1,98 @@
+package securitygroup
+
+import (
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/simonleung8/flags"
+
+	"github.com/cloudfoundry/cli/cf/api/security_groups"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/json"
+)
+
+type SecurityGroupDiff struct {
+	ui                terminal.UI
+	securityGroupRepo security_groups.SecurityGroupRepo
+	configRepo        core_config.Reader
+}
+
+func init() {
+	command_registry.Register(&SecurityGroupDiff{})
+}
+
+func (cmd *SecurityGroupDiff) MetaData() command_registry.CommandMetadata {
+	primaryUsage := T("CF_NAME security-group-diff SECURITY_GROUP PATH_TO_JSON_RULES_FILE")
+	secondaryUsage := T(`   Shows the rules that update-security-group would add (+) to and remove (-) from the
+   security group, regardless of the order of the rules in the file.`)
+
+	return command_registry.CommandMetadata{
+		Name:        "security-group-diff",
+		Description: T("Compare the rules of a security group with a rules file"),
+		Usage:       strings.Join([]string{primaryUsage, secondaryUsage}, "\n\n"),
+	}
+}
+
+func (cmd *SecurityGroupDiff) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	if len(fc.Args()) != 2 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires SECURITY_GROUP and PATH_TO_JSON_RULES_FILE as arguments\n\n") + command_registry.Commands.CommandUsage("security-group-diff"))
+	}
+
+	requirements := []requirements.Requirement{requirementsFactory.NewLoginRequirement()}
+	return requirements, nil
+}
+
+func (cmd *SecurityGroupDiff) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.configRepo = deps.Config
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	return cmd
+}
+
+func (cmd *SecurityGroupDiff) Execute(context flags.FlagContext) {
+	name := context.Args()[0]
+	pathToJSONFile := context.Args()[1]
+
+	rules, err := json.ParseJsonArray(pathToJSONFile)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Say(T("Comparing security group {{.security_group}} with {{.file}} as {{.username}}",
+		map[string]interface{}{
+			"security_group": terminal.EntityNameColor(name),
+			"file":           terminal.EntityNameColor(pathToJSONFile),
+			"username":       terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+
+	securityGroup, err := cmd.securityGroupRepo.Read(name)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	checkRules(cmd.ui, pathToJSONFile, rules)
+
+	added, removed, unchanged := DiffRules(securityGroup.Rules, rules)
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if len(added) == 0 && len(removed) == 0 {
+		cmd.ui.Say(T("No differences"))
+		return
+	}
+
+	for _, rule := range removed {
+		cmd.ui.Say("%s", terminal.DiffRemovedColor("- "+formatRule(rule)))
+	}
+	for _, rule := range added {
+		cmd.ui.Say("%s", terminal.DiffAddedColor("+ "+formatRule(rule)))
+	}
+
+	cmd.ui.Say("")
+	cmd.ui.Say(T("{{.Added}} rules added, {{.Removed}} removed, {{.Unchanged}} unchanged",
+		map[string]interface{}{"Added": len(added), "Removed": len(removed), "Unchanged": unchanged}))
+}
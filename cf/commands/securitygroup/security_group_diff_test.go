@@ -0,0 +1,142 @@
+package securitygroup_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	fakeSecurityGroup "github.com/cloudfoundry/cli/cf/api/security_groups/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("security-group-diff command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		securityGroupRepo   *fakeSecurityGroup.FakeSecurityGroupRepo
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          core_config.Repository
+		deps                command_registry.Dependency
+		tempFile            *os.File
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(securityGroupRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("security-group-diff").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true}
+		securityGroupRepo = &fakeSecurityGroup.FakeSecurityGroupRepo{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		securityGroupRepo.ReadReturns(models.SecurityGroup{
+			SecurityGroupFields: models.SecurityGroupFields{
+				Name: "my-group",
+				Guid: "my-group-guid",
+				Rules: []map[string]interface{}{
+					{"protocol": "tcp", "destination": "10.0.0.1", "ports": "443"},
+					{"protocol": "udp", "destination": "10.0.0.2", "ports": "53"},
+				},
+			},
+		}, nil)
+
+		tempFile, _ = ioutil.TempFile("", "")
+	})
+
+	AfterEach(func() {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("security-group-diff", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when the user is not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-group", tempFile.Name())).To(BeFalse())
+		})
+
+		It("fails with usage when a rules file is not provided", func() {
+			runCommand("my-group")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires", "arguments"},
+			))
+		})
+	})
+
+	It("shows the added and removed rules", func() {
+		tempFile.Write([]byte(`[
+			{"ports":"443","destination":"10.0.0.1","protocol":"tcp"},
+			{"protocol":"tcp","destination":"10.0.0.3","ports":"5432"}
+		]`))
+
+		runCommand("my-group", tempFile.Name())
+
+		Expect(securityGroupRepo.ReadArgsForCall(0)).To(Equal("my-group"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Comparing security group", "my-group", tempFile.Name(), "my-user"},
+			[]string{"OK"},
+			[]string{`- {"destination":"10.0.0.2","ports":"53","protocol":"udp"}`},
+			[]string{`+ {"destination":"10.0.0.3","ports":"5432","protocol":"tcp"}`},
+			[]string{"1 rules added, 1 removed, 1 unchanged"},
+		))
+	})
+
+	It("says so when there are no differences", func() {
+		tempFile.Write([]byte(`[
+			{"protocol":"udp","destination":"10.0.0.2","ports":"53"},
+			{"protocol":"tcp","destination":"10.0.0.1","ports":"443"}
+		]`))
+
+		runCommand("my-group", tempFile.Name())
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"No differences"}))
+	})
+
+	It("warns about overly broad rules", func() {
+		tempFile.Write([]byte(`[{"protocol":"all","destination":"0.0.0.0/0"}]`))
+
+		runCommand("my-group", tempFile.Name())
+
+		Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"rule 1", "all ports to any destination"}))
+		Expect(ui.Outputs).To(ContainSubstrings([]string{`+ {"destination":"0.0.0.0/0","protocol":"all"}`}))
+	})
+
+	It("fails when the rules are invalid", func() {
+		tempFile.Write([]byte(`[{"protocol":"tcp","destination":"10.0.0.1"}]`))
+
+		runCommand("my-group", tempFile.Name())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"rule 1", "ports are required for protocol tcp"},
+		))
+	})
+
+	It("fails when the security group cannot be read", func() {
+		securityGroupRepo.ReadReturns(models.SecurityGroup{}, errors.NewModelNotFoundError("security group", "my-group"))
+		tempFile.Write([]byte(`[]`))
+
+		runCommand("my-group", tempFile.Name())
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"my-group", "not found"},
+		))
+	})
+})
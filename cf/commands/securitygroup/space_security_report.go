@@ -0,0 +1,162 @@
+package securitygroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/simonleung8/flags"
+
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running"
+	"github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type SpaceSecurityReport struct {
+	ui                       terminal.UI
+	configRepo               core_config.Reader
+	runningSecurityGroupRepo running.RunningSecurityGroupsRepo
+	stagingSecurityGroupRepo staging.StagingSecurityGroupsRepo
+	spaceReq                 requirements.SpaceRequirement
+}
+
+func init() {
+	command_registry.Register(&SpaceSecurityReport{})
+}
+
+func (cmd *SpaceSecurityReport) MetaData() command_registry.CommandMetadata {
+	primaryUsage := T("CF_NAME space-security-report [SPACE]")
+	secondaryUsage := T(`   Shows the security groups and rules that apply to the apps in the space (Default: targeted space)
+   while running and while staging, and warns about overly broad rules.`)
+
+	return command_registry.CommandMetadata{
+		Name:        "space-security-report",
+		Description: T("Show the effective security group rules of a space"),
+		Usage:       strings.Join([]string{primaryUsage, secondaryUsage}, "\n\n"),
+	}
+}
+
+func (cmd *SpaceSecurityReport) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	if len(fc.Args()) > 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires no argument or a SPACE\n\n") + command_registry.Commands.CommandUsage("space-security-report"))
+	}
+
+	reqs := []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+	}
+
+	if len(fc.Args()) == 1 {
+		cmd.spaceReq = requirementsFactory.NewSpaceRequirement(fc.Args()[0])
+		reqs = append(reqs, requirementsFactory.NewTargetedOrgRequirement())
+	} else {
+		cmd.spaceReq = requirementsFactory.NewSpaceRequirement(cmd.configRepo.SpaceFields().Name)
+		reqs = append(reqs, requirementsFactory.NewTargetedSpaceRequirement())
+	}
+
+	reqs = append(reqs, cmd.spaceReq)
+	return reqs, nil
+}
+
+func (cmd *SpaceSecurityReport) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.configRepo = deps.Config
+	cmd.runningSecurityGroupRepo = deps.RepoLocator.GetRunningSecurityGroupsRepository()
+	cmd.stagingSecurityGroupRepo = deps.RepoLocator.GetStagingSecurityGroupsRepository()
+	return cmd
+}
+
+func (cmd *SpaceSecurityReport) Execute(context flags.FlagContext) {
+	space := cmd.spaceReq.GetSpace()
+
+	cmd.ui.Say(T("Getting security report for space {{.SpaceName}} in org {{.OrgName}} as {{.Username}}...",
+		map[string]interface{}{
+			"SpaceName": terminal.EntityNameColor(space.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.configRepo.OrganizationFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.configRepo.Username()),
+		}))
+
+	runningGroups, err := EffectiveSecurityGroups(cmd.runningSecurityGroupRepo, cmd.stagingSecurityGroupRepo, space, false)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	stagingGroups, err := EffectiveSecurityGroups(cmd.runningSecurityGroupRepo, cmd.stagingSecurityGroupRepo, space, true)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	cmd.ui.Say(terminal.HeaderColor(T("Running apps:")))
+	cmd.printGroups(runningGroups)
+	cmd.ui.Say("")
+
+	cmd.ui.Say(terminal.HeaderColor(T("Staging apps:")))
+	cmd.printGroups(stagingGroups)
+
+	warnings := []string{}
+	seen := map[string]bool{}
+	for _, group := range append(runningGroups, stagingGroups...) {
+		if seen[group.Guid] {
+			continue
+		}
+		seen[group.Guid] = true
+
+		for _, warning := range LintRules(group.Rules) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", group.Name, warning))
+		}
+	}
+	sort.Strings(warnings)
+
+	if len(warnings) > 0 {
+		cmd.ui.Say("")
+		for _, warning := range warnings {
+			cmd.ui.Warn(T("Warning: {{.Warning}}", map[string]interface{}{"Warning": warning}))
+		}
+	}
+}
+
+func (cmd *SpaceSecurityReport) printGroups(groups []EffectiveSecurityGroup) {
+	if len(groups) == 0 {
+		cmd.ui.Say(T("No security groups apply, so all traffic is denied"))
+		return
+	}
+
+	table := terminal.NewTable(cmd.ui, []string{T("security group"), T("bound as"), T("protocol"), T("destination"), T("ports"), T("type/code")})
+	for _, group := range groups {
+		boundAs := strings.Join(group.BoundAs, ", ")
+		if len(group.Rules) == 0 {
+			table.Add(group.Name, boundAs, "", "", "", "")
+			continue
+		}
+
+		for _, rule := range group.Rules {
+			typeAndCode := ""
+			if _, ok := rule["type"]; ok {
+				typeAndCode = fmt.Sprintf("%v/%v", rule["type"], rule["code"])
+			}
+			table.Add(
+				group.Name,
+				boundAs,
+				ruleField(rule, "protocol"),
+				ruleField(rule, "destination"),
+				ruleField(rule, "ports"),
+				typeAndCode,
+			)
+		}
+	}
+	table.Print()
+}
+
+func ruleField(rule map[string]interface{}, field string) string {
+	value, ok := rule[field]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
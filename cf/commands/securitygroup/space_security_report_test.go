@@ -0,0 +1,107 @@
+package securitygroup_test
+
+import (
+	runningFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/running/fakes"
+	stagingFakes "github.com/cloudfoundry/cli/cf/api/security_groups/defaults/staging/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("space-security-report command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		runningRepo         *runningFakes.FakeRunningSecurityGroupsRepo
+		stagingRepo         *stagingFakes.FakeStagingSecurityGroupsRepo
+		requirementsFactory *testreq.FakeReqFactory
+		configRepo          core_config.Repository
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.RepoLocator = deps.RepoLocator.SetRunningSecurityGroupRepository(runningRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStagingSecurityGroupRepository(stagingRepo)
+		deps.Config = configRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("space-security-report").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+
+		space := models.Space{}
+		space.Name = "my-space"
+		space.SecurityGroups = []models.SecurityGroupFields{{
+			Name:  "db",
+			Guid:  "db-guid",
+			Rules: []map[string]interface{}{{"protocol": "tcp", "destination": "10.2.0.0/16", "ports": "5432"}},
+		}}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedOrgSuccess: true, TargetedSpaceSuccess: true, Space: space}
+
+		runningRepo = &runningFakes.FakeRunningSecurityGroupsRepo{}
+		runningRepo.ListReturns([]models.SecurityGroupFields{{
+			Name: "public",
+			Guid: "public-guid",
+			Rules: []map[string]interface{}{
+				{"protocol": "all", "destination": "0.0.0.0/0"},
+				{"protocol": "icmp", "destination": "10.0.0.1", "type": float64(0), "code": float64(1)},
+			},
+		}}, nil)
+
+		stagingRepo = &stagingFakes.FakeStagingSecurityGroupsRepo{}
+		stagingRepo.ListReturns([]models.SecurityGroupFields{}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("space-security-report", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("requires the targeted space when not given a space", func() {
+			Expect(runCommand()).To(BeTrue())
+			Expect(requirementsFactory.SpaceName).To(Equal("my-space"))
+
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("requires the given space", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand("other-space")).To(BeTrue())
+			Expect(requirementsFactory.SpaceName).To(Equal("other-space"))
+		})
+	})
+
+	It("shows the effective rules for running and staging apps", func() {
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting security report for space", "my-space", "my-org", "my-user"},
+			[]string{"OK"},
+			[]string{"Running apps:"},
+			[]string{"security group", "bound as", "protocol", "destination", "ports", "type/code"},
+			[]string{"public", "running", "all", "0.0.0.0/0"},
+			[]string{"public", "running", "icmp", "10.0.0.1", "0/1"},
+			[]string{"db", "space", "tcp", "10.2.0.0/16", "5432"},
+			[]string{"Staging apps:"},
+			[]string{"No security groups apply, so all traffic is denied"},
+		))
+	})
+
+	It("warns about overly broad rules", func() {
+		runCommand()
+
+		Expect(ui.WarnOutputs).To(ContainSubstrings(
+			[]string{"public: rule 1 allows all traffic on all ports to any destination"},
+		))
+	})
+})
@@ -26,7 +26,7 @@ func init() {
 
 func (cmd *UpdateSecurityGroup) MetaData() command_registry.CommandMetadata {
 	primaryUsage := T("CF_NAME update-security-group SECURITY_GROUP PATH_TO_JSON_RULES_FILE")
-	secondaryUsage := T("   The provided path can be an absolute or relative path to a file.\n   It should have a single array with JSON objects inside describing the rules.")
+	secondaryUsage := T("   The provided path can be an absolute or relative path to a file.\n   It should have a single array with JSON objects inside describing the rules.\n   The rules are validated before the security group is updated, and overly broad rules are warned about.\n   Use security-group-diff to preview the changes.")
 	tipUsage := T("TIP: Changes will not apply to existing running applications until they are restarted.")
 	return command_registry.CommandMetadata{
 		Name:        "update-security-group",
@@ -64,6 +64,8 @@ func (cmd *UpdateSecurityGroup) Execute(context flags.FlagContext) {
 		cmd.ui.Failed(err.Error())
 	}
 
+	checkRules(cmd.ui, pathToJSONFile, rules)
+
 	cmd.ui.Say(T("Updating security group {{.security_group}} as {{.username}}",
 		map[string]interface{}{
 			"security_group": terminal.EntityNameColor(name),
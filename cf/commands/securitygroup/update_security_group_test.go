@@ -91,9 +91,25 @@ var _ = Describe("update-security-group command", func() {
 			runCommand("my-group-name", tempFile.Name())
 		})
 
+		Context("when the file specified has invalid rules", func() {
+			BeforeEach(func() {
+				tempFile.Write([]byte(`[{"protocol":"tcp","destination":"10.0.0.0/33","ports":"70000"}]`))
+			})
+
+			It("fails listing the problems without updating the security group", func() {
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"Invalid security group rules"},
+					[]string{"rule 1", "destination '10.0.0.0/33'"},
+					[]string{"rule 1", "ports '70000'"},
+				))
+				Expect(securityGroupRepo.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the file specified has valid json", func() {
 			BeforeEach(func() {
-				tempFile.Write([]byte(`[{"protocol":"udp","port":"8080-9090","destination":"198.41.191.47/1"}]`))
+				tempFile.Write([]byte(`[{"protocol":"udp","ports":"8080-9090","destination":"198.41.191.47/1"}]`))
 			})
 
 			It("displays a message describing what its going to do", func() {
@@ -106,7 +122,7 @@ var _ = Describe("update-security-group command", func() {
 
 			It("updates the security group with those rules, obviously", func() {
 				jsonData := []map[string]interface{}{
-					{"protocol": "udp", "port": "8080-9090", "destination": "198.41.191.47/1"},
+					{"protocol": "udp", "ports": "8080-9090", "destination": "198.41.191.47/1"},
 				}
 
 				_, jsonArg := securityGroupRepo.UpdateArgsForCall(0)
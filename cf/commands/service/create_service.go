@@ -33,8 +33,9 @@ func (cmd *CreateService) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["c"] = &cliFlags.StringFlag{Name: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
 	fs["t"] = &cliFlags.StringFlag{Name: "t", Usage: T("User provided tags")}
+	fs["wait"] = &cliFlags.BoolFlag{Name: "wait", Usage: T("Wait for the service instance to be created by an asynchronous service broker")}
 
-	baseUsage := T("CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [-t TAGS]")
+	baseUsage := T("CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [-t TAGS] [--wait]")
 	paramsUsage := T(`   Optionally provide service-specific configuration parameters in a valid JSON object in-line:
 
    CF_NAME create-service SERVICE PLAN SERVICE_INSTANCE -c '{"name":"value","name":"value"}'
@@ -122,9 +123,13 @@ func (cmd *CreateService) Execute(c flags.FlagContext) {
 
 	switch err.(type) {
 	case nil:
-		err := printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
-		if err != nil {
-			cmd.ui.Failed(err.Error())
+		if c.Bool("wait") {
+			waitForLastOperationToFinish(cmd.ui, cmd.serviceRepo, serviceInstanceName)
+		} else {
+			err := printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
+			if err != nil {
+				cmd.ui.Failed(err.Error())
+			}
 		}
 
 		if !plan.Free {
@@ -228,6 +228,30 @@ var _ = Describe("create-service command", func() {
 			Expect(serviceRepo.CreateServiceInstanceArgs.PlanGuid).To(Equal("cleardb-spark-guid"))
 		})
 
+		Context("with --wait", func() {
+			It("succeeds once the creation has finished", func() {
+				serviceInstance.LastOperation.State = "succeeded"
+				serviceRepo.FindInstanceByNameMap.Set("my-cleardb-service", serviceInstance)
+
+				callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--wait"})
+
+				Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+				Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Create in progress"}))
+			})
+
+			It("fails when the creation fails", func() {
+				serviceInstance.LastOperation.State = "failed"
+				serviceRepo.FindInstanceByNameMap.Set("my-cleardb-service", serviceInstance)
+
+				callCreateService([]string{"cleardb", "spark", "my-cleardb-service", "--wait"})
+
+				Expect(ui.Outputs).To(ContainSubstrings(
+					[]string{"FAILED"},
+					[]string{"create operation", "my-cleardb-service", "failed", "fake service instance description"},
+				))
+			})
+		})
+
 		It("fails when service instance could is created but cannot be found", func() {
 			serviceRepo.FindInstanceByNameErr = true
 			callCreateService([]string{"cleardb", "spark", "fake-service-instance-name"})
@@ -26,12 +26,13 @@ func init() {
 func (cmd *DeleteService) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["f"] = &cliFlags.BoolFlag{Name: "f", Usage: T("Force deletion without confirmation")}
+	fs["wait"] = &cliFlags.BoolFlag{Name: "wait", Usage: T("Wait for the service instance to be deleted by an asynchronous service broker")}
 
 	return command_registry.CommandMetadata{
 		Name:        "delete-service",
 		ShortName:   "ds",
 		Description: T("Delete a service instance"),
-		Usage:       T("CF_NAME delete-service SERVICE_INSTANCE [-f]"),
+		Usage:       T("CF_NAME delete-service SERVICE_INSTANCE [-f] [--wait]"),
 		Flags:       fs,
 	}
 }
@@ -88,6 +89,15 @@ func (cmd *DeleteService) Execute(c flags.FlagContext) {
 		return
 	}
 
+	if c.Bool("wait") {
+		apiErr = WaitForLastOperation(cmd.ui, cmd.serviceRepo, serviceName, DefaultLastOperationTimeout, LastOperationPollInterval)
+		if _, deleted := apiErr.(*errors.ModelNotFoundError); apiErr != nil && !deleted {
+			cmd.ui.Failed(apiErr.Error())
+		}
+		cmd.ui.Ok()
+		return
+	}
+
 	apiErr = printSuccessMessageForServiceInstance(serviceName, cmd.serviceRepo, cmd.ui)
 	if apiErr != nil {
 		cmd.ui.Ok()
@@ -4,6 +4,7 @@ import (
 	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
 	"github.com/cloudfoundry/cli/cf/command_registry"
 	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/models"
 	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
 	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
@@ -108,6 +109,38 @@ var _ = Describe("delete-service command", func() {
 						[]string{"Delete in progress. Use 'cf services' or 'cf service foo.com' to check operation status."},
 					))
 				})
+
+				Context("with --wait", func() {
+					It("succeeds once the service instance is gone", func() {
+						calls := 0
+						serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+							calls++
+							if calls == 1 {
+								return serviceInstance, nil
+							}
+							return models.ServiceInstance{}, errors.NewModelNotFoundError("Service instance", name)
+						}
+
+						runCommand("-f", "--wait", "my-service")
+
+						Expect(calls).To(Equal(2))
+						Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+						Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"Delete in progress"}))
+					})
+
+					It("fails when the deletion fails", func() {
+						serviceInstance.LastOperation.State = "failed"
+						serviceInstance.LastOperation.Description = "still in use"
+						serviceRepo.FindInstanceByNameServiceInstance = serviceInstance
+
+						runCommand("-f", "--wait", "my-service")
+
+						Expect(ui.Outputs).To(ContainSubstrings(
+							[]string{"FAILED"},
+							[]string{"delete operation", "my-service", "failed", "still in use"},
+						))
+					})
+				})
 			})
 
 			Context("and the service deletion is synchronous", func() {
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+const (
+	DefaultLastOperationTimeout = 60 * time.Minute
+	LastOperationPollInterval   = 5 * time.Second
+)
+
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// LastOperationTimeoutError is returned by WaitForLastOperation when the
+// operation is still in progress once the timeout is reached.
+type LastOperationTimeoutError struct {
+	OperationType       string
+	ServiceInstanceName string
+	Timeout             time.Duration
+}
+
+func (err *LastOperationTimeoutError) Error() string {
+	return T("The {{.OperationType}} operation on service instance {{.ServiceName}} did not finish within {{.Timeout}}",
+		map[string]interface{}{
+			"OperationType": err.OperationType,
+			"ServiceName":   err.ServiceInstanceName,
+			"Timeout":       err.Timeout,
+		})
+}
+
+// WaitForLastOperation polls the last operation of a service instance until it
+// is no longer in progress, showing a spinner with the elapsed time meanwhile.
+// It returns an error when the operation fails or does not finish within
+// timeout. Once a delete operation succeeds the instance is not found any more,
+// so callers waiting for a delete should expect a *errors.ModelNotFoundError.
+func WaitForLastOperation(ui terminal.UI, serviceRepo api.ServiceRepository, name string, timeout, interval time.Duration) error {
+	startTime := time.Now()
+	progressWidth := 0
+	defer func() {
+		if progressWidth > 0 {
+			ui.PrintCapturingNoOutput("\n")
+		}
+	}()
+
+	for i := 0; ; i++ {
+		instance, err := serviceRepo.FindInstanceByName(name)
+		if err != nil {
+			return err
+		}
+
+		operation := instance.LastOperation
+		switch operation.State {
+		case "in progress":
+		case "failed":
+			return errors.New(T("The {{.OperationType}} operation on service instance {{.ServiceName}} failed: {{.Description}}",
+				map[string]interface{}{
+					"OperationType": operation.Type,
+					"ServiceName":   name,
+					"Description":   operation.Description,
+				}))
+		default:
+			return nil
+		}
+
+		elapsed := time.Since(startTime)
+		if elapsed >= timeout {
+			return &LastOperationTimeoutError{OperationType: operation.Type, ServiceInstanceName: name, Timeout: timeout}
+		}
+
+		progress := spinnerFrames[i%len(spinnerFrames)] + " " + ServiceInstanceStateToStatus(operation.Type, operation.State, false)
+		if operation.Description != "" {
+			progress += ": " + operation.Description
+		}
+		progress += " " + T("({{.Elapsed}} elapsed)", map[string]interface{}{"Elapsed": elapsed / time.Second * time.Second})
+
+		// Pad with spaces to overwrite the rest of a longer previous line.
+		padding := ""
+		if len(progress) < progressWidth {
+			padding = strings.Repeat(" ", progressWidth-len(progress))
+		}
+		ui.PrintCapturingNoOutput("\r%s%s", progress, padding)
+		if len(progress) > progressWidth {
+			progressWidth = len(progress)
+		}
+
+		ui.Wait(interval)
+	}
+}
+
+// waitForLastOperationToFinish waits for the last operation on a service
+// instance to finish and fails unless it succeeded.
+func waitForLastOperationToFinish(ui terminal.UI, serviceRepo api.ServiceRepository, name string) {
+	err := WaitForLastOperation(ui, serviceRepo, name, DefaultLastOperationTimeout, LastOperationPollInterval)
+	if err != nil {
+		ui.Failed(err.Error())
+	}
+
+	ui.Ok()
+}
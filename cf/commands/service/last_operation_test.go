@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"time"
+
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	. "github.com/cloudfoundry/cli/cf/commands/service"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WaitForLastOperation", func() {
+	var (
+		ui          *testterm.FakeUI
+		serviceRepo *testapi.FakeServiceRepo
+		states      []string
+	)
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		serviceRepo = &testapi.FakeServiceRepo{}
+		states = []string{"in progress", "in progress", "succeeded"}
+		serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+			instance := models.ServiceInstance{}
+			instance.LastOperation = models.LastOperationFields{Type: "create", State: states[0], Description: "working on it"}
+			if len(states) > 1 {
+				states = states[1:]
+			}
+			return instance, nil
+		}
+	})
+
+	It("polls until the operation has finished, showing its progress", func() {
+		err := WaitForLastOperation(ui, serviceRepo, "my-db", time.Minute, 0)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serviceRepo.FindInstanceByNameName).To(Equal("my-db"))
+		Expect(ui.UncapturedOutput).To(ContainElement(MatchRegexp(`create in progress: working on it \(\S+ elapsed\)`)))
+	})
+
+	It("returns an error when the operation fails", func() {
+		states = []string{"in progress", "failed"}
+
+		err := WaitForLastOperation(ui, serviceRepo, "my-db", time.Minute, 0)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("The create operation on service instance my-db failed: working on it"))
+	})
+
+	It("returns a timeout error when the operation does not finish in time", func() {
+		states = []string{"in progress"}
+
+		err := WaitForLastOperation(ui, serviceRepo, "my-db", 0, 0)
+
+		Expect(err).To(BeAssignableToTypeOf(&LastOperationTimeoutError{}))
+		Expect(err.Error()).To(ContainSubstring("did not finish within"))
+	})
+
+	It("returns the error when the instance cannot be found", func() {
+		serviceRepo.FindInstanceByNameStub = func(name string) (models.ServiceInstance, error) {
+			return models.ServiceInstance{}, errors.NewModelNotFoundError("Service instance", name)
+		}
+
+		err := WaitForLastOperation(ui, serviceRepo, "my-db", time.Minute, 0)
+
+		Expect(err).To(BeAssignableToTypeOf(&errors.ModelNotFoundError{}))
+	})
+})
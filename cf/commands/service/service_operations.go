@@ -0,0 +1,90 @@
+package service
+
+import (
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+)
+
+type ServiceOperations struct {
+	ui                 terminal.UI
+	config             core_config.Reader
+	serviceSummaryRepo api.ServiceSummaryRepository
+}
+
+func init() {
+	command_registry.Register(&ServiceOperations{})
+}
+
+func (cmd *ServiceOperations) MetaData() command_registry.CommandMetadata {
+	return command_registry.CommandMetadata{
+		Name:        "service-operations",
+		Description: T("List the service instance operations in progress in the target space"),
+		Usage:       T("CF_NAME service-operations"),
+	}
+}
+
+func (cmd *ServiceOperations) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 0 {
+		cmd.ui.Failed(T("Incorrect Usage. No argument required\n\n") + command_registry.Commands.CommandUsage("service-operations"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+	return
+}
+
+func (cmd *ServiceOperations) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.serviceSummaryRepo = deps.RepoLocator.GetServiceSummaryRepository()
+	return cmd
+}
+
+func (cmd *ServiceOperations) Execute(fc flags.FlagContext) {
+	cmd.ui.Say(T("Getting service operations in org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"OrgName":     terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":   terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser": terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	serviceInstances, err := cmd.serviceSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	table := terminal.NewTable(cmd.ui, []string{T("name"), T("service"), T("plan"), T("operation"), T("description"), T("updated")})
+	found := false
+	for _, instance := range serviceInstances {
+		if instance.LastOperation.State != "in progress" {
+			continue
+		}
+
+		found = true
+		table.Add(
+			instance.Name,
+			instance.ServiceOffering.Label,
+			instance.ServicePlan.Name,
+			ServiceInstanceStateToStatus(instance.LastOperation.Type, instance.LastOperation.State, false),
+			instance.LastOperation.Description,
+			instance.LastOperation.UpdatedAt,
+		)
+	}
+
+	if !found {
+		cmd.ui.Say(T("No service operations in progress"))
+		return
+	}
+
+	table.Print()
+}
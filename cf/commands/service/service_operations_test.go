@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("service-operations command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		configRepo          core_config.Repository
+		requirementsFactory *testreq.FakeReqFactory
+		serviceSummaryRepo  *testapi.FakeServiceSummaryRepo
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetServiceSummaryRepository(serviceSummaryRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("service-operations").SetDependency(deps, pluginCall))
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("service-operations", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		serviceSummaryRepo = &testapi.FakeServiceSummaryRepo{}
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+	})
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails with usage when provided an argument", func() {
+			runCommand("my-db")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "No argument required"},
+			))
+		})
+	})
+
+	It("lists the operations in progress", func() {
+		creating := models.ServiceInstance{}
+		creating.Name = "my-db"
+		creating.ServiceOffering.Label = "cleardb"
+		creating.ServicePlan.Name = "spark"
+		creating.LastOperation = models.LastOperationFields{Type: "create", State: "in progress", Description: "50% done", UpdatedAt: "2015-07-15T21:08:56Z"}
+
+		done := models.ServiceInstance{}
+		done.Name = "my-cache"
+		done.LastOperation = models.LastOperationFields{Type: "create", State: "succeeded"}
+
+		serviceSummaryRepo.GetSummariesInCurrentSpaceInstances = []models.ServiceInstance{creating, done}
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting service operations in org", "my-org", "my-space", "my-user"},
+			[]string{"OK"},
+			[]string{"name", "service", "plan", "operation", "description", "updated"},
+			[]string{"my-db", "cleardb", "spark", "create in progress", "50% done", "2015-07-15T21:08:56Z"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"my-cache"}))
+	})
+
+	It("says so when no operations are in progress", func() {
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings([]string{"No service operations in progress"}))
+	})
+})
@@ -31,7 +31,7 @@ func init() {
 }
 
 func (cmd *UpdateService) MetaData() command_registry.CommandMetadata {
-	baseUsage := T("CF_NAME update-service SERVICE_INSTANCE [-p NEW_PLAN] [-c PARAMETERS_AS_JSON] [-t TAGS]")
+	baseUsage := T("CF_NAME update-service SERVICE_INSTANCE [-p NEW_PLAN] [-c PARAMETERS_AS_JSON] [-t TAGS] [--wait]")
 	paramsUsage := T(`   Optionally provide service-specific configuration parameters in a valid JSON object in-line.
    CF_NAME update-service -c '{"name":"value","name":"value"}'
 
@@ -57,6 +57,7 @@ func (cmd *UpdateService) MetaData() command_registry.CommandMetadata {
 	fs["p"] = &cliFlags.StringFlag{Name: "p", Usage: T("Change service plan for a service instance")}
 	fs["c"] = &cliFlags.StringFlag{Name: "c", Usage: T("Valid JSON object containing service-specific configuration parameters, provided either in-line or in a file. For a list of supported configuration parameters, see documentation for the particular service offering.")}
 	fs["t"] = &cliFlags.StringFlag{Name: "t", Usage: T("User provided tags")}
+	fs["wait"] = &cliFlags.BoolFlag{Name: "wait", Usage: T("Wait for the service instance to be updated by an asynchronous service broker")}
 
 	return command_registry.CommandMetadata{
 		Name:        "update-service",
@@ -128,6 +129,12 @@ func (cmd *UpdateService) Execute(c flags.FlagContext) {
 	if err != nil {
 		cmd.ui.Failed(err.Error())
 	}
+
+	if c.Bool("wait") {
+		waitForLastOperationToFinish(cmd.ui, cmd.serviceRepo, serviceInstanceName)
+		return
+	}
+
 	err = printSuccessMessageForServiceInstance(serviceInstanceName, cmd.serviceRepo, cmd.ui)
 	if err != nil {
 		cmd.ui.Failed(err.Error())
@@ -210,6 +210,34 @@ var _ = Describe("update-service command", func() {
 		})
 	})
 
+	Context("with --wait", func() {
+		It("succeeds once the update has finished", func() {
+			serviceInstance := models.ServiceInstance{}
+			serviceInstance.LastOperation = models.LastOperationFields{Type: "update", State: "succeeded"}
+			serviceRepo.FindInstanceByNameServiceInstance = serviceInstance
+
+			callUpdateService([]string{"-t", "tag1", "--wait", "my-service-instance"})
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Updating service instance", "my-service-instance"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails when the update fails", func() {
+			serviceInstance := models.ServiceInstance{}
+			serviceInstance.LastOperation = models.LastOperationFields{Type: "update", State: "failed", Description: "plan not available"}
+			serviceRepo.FindInstanceByNameServiceInstance = serviceInstance
+
+			callUpdateService([]string{"-t", "tag1", "--wait", "my-service-instance"})
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"update operation", "my-service-instance", "failed", "plan not available"},
+			))
+		})
+	})
+
 	Context("when passing in tags", func() {
 		It("successfully updates a service and passes the tags as json", func() {
 			callUpdateService([]string{"-t", "tag1, tag2,tag3,  tag4", "my-service-instance"})
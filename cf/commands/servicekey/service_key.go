@@ -9,6 +9,7 @@ import (
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/requirements"
 	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
 	"github.com/simonleung8/flags"
 	"github.com/simonleung8/flags/flag"
 
@@ -30,14 +31,17 @@ func init() {
 func (cmd *ServiceKey) MetaData() command_registry.CommandMetadata {
 	fs := make(map[string]flags.FlagSet)
 	fs["guid"] = &cliFlags.BoolFlag{Name: "guid", Usage: T("Retrieve and display the given service-key's guid.  All other output for the service is suppressed.")}
+	fs["format"] = &cliFlags.StringFlag{Name: "format", Usage: T("Print only the credentials, as json, as shell exports (env) or as a .env file (dotenv)")}
 
 	return command_registry.CommandMetadata{
 		Name:        "service-key",
 		Description: T("Show service key info"),
-		Usage: T(`CF_NAME service-key SERVICE_INSTANCE SERVICE_KEY
+		Usage: T(`CF_NAME service-key SERVICE_INSTANCE SERVICE_KEY [--guid | --format json|env|dotenv]
 
 EXAMPLE:
-   CF_NAME service-key mydb mykey`),
+   CF_NAME service-key mydb mykey
+   eval "$(CF_NAME service-key mydb mykey --format env)"
+   CF_NAME service-key mydb mykey --format dotenv > .env`),
 		Flags: fs,
 	}
 }
@@ -47,6 +51,16 @@ func (cmd *ServiceKey) Requirements(requirementsFactory requirements.Factory, fc
 		cmd.ui.Failed(T("Incorrect Usage. Requires SERVICE_INSTANCE SERVICE_KEY as arguments\n\n") + command_registry.Commands.CommandUsage("service-key"))
 	}
 
+	switch fc.String("format") {
+	case "", "json", ui_helpers.EnvVarFormatEnv, ui_helpers.EnvVarFormatDotenv:
+	default:
+		cmd.ui.Failed(T("Incorrect Usage. --format must be json, env or dotenv\n\n") + command_registry.Commands.CommandUsage("service-key"))
+	}
+
+	if fc.Bool("guid") && fc.String("format") != "" {
+		cmd.ui.Failed(T("Incorrect Usage. --guid and --format cannot be combined\n\n") + command_registry.Commands.CommandUsage("service-key"))
+	}
+
 	loginRequirement := requirementsFactory.NewLoginRequirement()
 	cmd.serviceInstanceRequirement = requirementsFactory.NewServiceInstanceRequirement(fc.Args()[0])
 	targetSpaceRequirement := requirementsFactory.NewTargetedSpaceRequirement()
@@ -66,8 +80,9 @@ func (cmd *ServiceKey) SetDependency(deps command_registry.Dependency, pluginCal
 func (cmd *ServiceKey) Execute(c flags.FlagContext) {
 	serviceInstance := cmd.serviceInstanceRequirement.GetServiceInstance()
 	serviceKeyName := c.Args()[1]
+	format := c.String("format")
 
-	if !c.Bool("guid") {
+	if !c.Bool("guid") && format == "" {
 		cmd.ui.Say(T("Getting key {{.ServiceKeyName}} for service instance {{.ServiceInstanceName}} as {{.CurrentUser}}...",
 			map[string]interface{}{
 				"ServiceKeyName":      terminal.EntityNameColor(serviceKeyName),
@@ -80,19 +95,32 @@ func (cmd *ServiceKey) Execute(c flags.FlagContext) {
 	if err != nil {
 		switch err.(type) {
 		case *errors.NotAuthorizedError:
+			if format != "" {
+				cmd.failNotFound(serviceKeyName, serviceInstance.Name)
+			}
 			cmd.ui.Say(T("No service key {{.ServiceKeyName}} found for service instance {{.ServiceInstanceName}}",
 				map[string]interface{}{
 					"ServiceKeyName":      terminal.EntityNameColor(serviceKeyName),
 					"ServiceInstanceName": terminal.EntityNameColor(serviceInstance.Name)}))
 			return
 		default:
-			cmd.ui.Failed(err.Error())
+			if format != "" {
+				cmd.ui.FailedOnStderr(err.Error())
+			} else {
+				cmd.ui.Failed(err.Error())
+			}
 			return
 		}
 	}
 
 	if c.Bool("guid") {
 		cmd.ui.Say(serviceKey.Fields.Guid)
+	} else if format != "" {
+		if serviceKey.Fields.Name == "" {
+			cmd.failNotFound(serviceKeyName, serviceInstance.Name)
+		}
+
+		cmd.printCredentials(serviceKey.Credentials, format)
 	} else {
 		if serviceKey.Fields.Name == "" {
 			cmd.ui.Say(T("No service key {{.ServiceKeyName}} found for service instance {{.ServiceInstanceName}}",
@@ -112,3 +140,28 @@ func (cmd *ServiceKey) Execute(c flags.FlagContext) {
 		cmd.ui.Say(string(jsonBytes))
 	}
 }
+
+func (cmd *ServiceKey) failNotFound(serviceKeyName, serviceInstanceName string) {
+	cmd.ui.FailedOnStderr(T("No service key {{.ServiceKeyName}} found for service instance {{.ServiceInstanceName}}",
+		map[string]interface{}{
+			"ServiceKeyName":      serviceKeyName,
+			"ServiceInstanceName": serviceInstanceName}))
+}
+
+// printCredentials prints nothing but the credentials, so that the output can
+// be redirected to a file or sourced by a shell. Failures go to stderr for
+// the same reason.
+func (cmd *ServiceKey) printCredentials(credentials map[string]interface{}, format string) {
+	if format == "json" {
+		jsonBytes, err := json.MarshalIndent(credentials, "", " ")
+		if err != nil {
+			cmd.ui.FailedOnStderr(err.Error())
+		}
+		cmd.ui.Say("%s", jsonBytes)
+		return
+	}
+
+	for _, line := range ui_helpers.FormatEnvVars(ui_helpers.FlattenCredentials("", credentials), format) {
+		cmd.ui.Say("%s", line)
+	}
+}
@@ -0,0 +1,314 @@
+package space
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
+	"github.com/cloudfoundry/cli/cf/api/security_groups"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+	"gopkg.in/yaml.v2"
+)
+
+type ExportSpace struct {
+	ui                      terminal.UI
+	config                  core_config.Reader
+	appSummaryRepo          api.AppSummaryRepository
+	stackRepo               stacks.StackRepository
+	serviceSummaryRepo      api.ServiceSummaryRepository
+	serviceRepo             api.ServiceRepository
+	userProvidedServiceRepo api.UserProvidedServiceInstanceRepository
+	userRepo                api.UserRepository
+	securityGroupRepo       security_groups.SecurityGroupRepo
+	dropletRepo             application_bits.ApplicationDropletRepository
+}
+
+func init() {
+	command_registry.Register(&ExportSpace{})
+}
+
+func (cmd *ExportSpace) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["droplets"] = &cliFlags.StringFlag{Name: "droplets", Usage: T("Directory to download the droplets of the apps to, so that import-space can start them")}
+
+	baseUsage := T(`CF_NAME export-space [--droplets DIR]
+
+   The parameters that managed service instances were created with are not exported, because
+   the Cloud Controller does not return them. Add them to the parameters of the services in
+   the file before importing it. Droplet paths are written as absolute paths, so the file can
+   be saved and imported from any directory.`)
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME export-space > space.yml
+   CF_NAME export-space --droplets droplets > space.yml`)
+
+	return command_registry.CommandMetadata{
+		Name:        "export-space",
+		Description: T("Write the apps, services, roles and security groups of the targeted space as YAML"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage}, "\n\n"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *ExportSpace) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 0 {
+		cmd.ui.Failed(T("Incorrect Usage. No argument required\n\n") + command_registry.Commands.CommandUsage("export-space"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+	return
+}
+
+func (cmd *ExportSpace) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
+	cmd.serviceSummaryRepo = deps.RepoLocator.GetServiceSummaryRepository()
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.userProvidedServiceRepo = deps.RepoLocator.GetUserProvidedServiceInstanceRepository()
+	cmd.userRepo = deps.RepoLocator.GetUserRepository()
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	return cmd
+}
+
+// Execute writes nothing but the snapshot, so that the output can be
+// redirected to a file.
+func (cmd *ExportSpace) Execute(c flags.FlagContext) {
+	snapshot := spaceSnapshot{Space: cmd.config.SpaceFields().Name}
+
+	var err error
+	snapshot.SecurityGroups, err = cmd.exportSecurityGroups()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	snapshot.UserProvidedServices, err = cmd.exportUserProvidedServices()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	snapshot.Services, err = cmd.exportServices()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	snapshot.Apps, err = cmd.exportApps(c.String("droplets"))
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	snapshot.Roles, err = cmd.exportRoles()
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	out, err := yaml.Marshal(snapshot)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	cmd.ui.Say(strings.TrimSuffix(string(out), "\n"))
+}
+
+func (cmd *ExportSpace) exportSecurityGroups() ([]securityGroupSnapshot, error) {
+	groups, err := cmd.securityGroupRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []securityGroupSnapshot{}
+	for _, group := range groups {
+		for _, space := range group.Spaces {
+			if space.Guid == cmd.config.SpaceFields().Guid {
+				snapshots = append(snapshots, securityGroupSnapshot{Name: group.Name, Rules: group.Rules})
+				break
+			}
+		}
+	}
+	return snapshots, nil
+}
+
+func (cmd *ExportSpace) exportUserProvidedServices() ([]userProvidedServiceSnapshot, error) {
+	summary, err := cmd.userProvidedServiceRepo.GetSummaries()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []userProvidedServiceSnapshot{}
+	for _, resource := range summary.Resources {
+		if resource.SpaceGuid != cmd.config.SpaceFields().Guid {
+			continue
+		}
+
+		snapshots = append(snapshots, userProvidedServiceSnapshot{
+			Name:           resource.Name,
+			Credentials:    resource.Credentials,
+			SyslogDrainUrl: resource.SysLogDrainUrl,
+		})
+	}
+	return snapshots, nil
+}
+
+func (cmd *ExportSpace) exportServices() ([]serviceSnapshot, error) {
+	instances, err := cmd.serviceSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []serviceSnapshot{}
+	for _, instance := range instances {
+		if instance.IsUserProvided() {
+			continue
+		}
+
+		found, err := cmd.serviceRepo.FindInstanceByName(instance.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, serviceSnapshot{
+			Name:    instance.Name,
+			Service: instance.ServiceOffering.Label,
+			Plan:    instance.ServicePlan.Name,
+			Tags:    found.Tags,
+		})
+	}
+	return snapshots, nil
+}
+
+func (cmd *ExportSpace) exportApps(dropletDir string) ([]appSnapshot, error) {
+	apps, err := cmd.appSummaryRepo.GetSummariesInCurrentSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	stackNames := map[string]string{}
+	if len(apps) > 0 {
+		allStacks, err := cmd.stackRepo.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range allStacks {
+			stackNames[stack.Guid] = stack.Name
+		}
+	}
+
+	snapshots := []appSnapshot{}
+	for _, listed := range apps {
+		app, err := cmd.appSummaryRepo.GetSummary(listed.Guid)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := appSnapshot{
+			Name:               app.Name,
+			State:              app.State,
+			Instances:          app.InstanceCount,
+			Memory:             formatters.ByteSize(app.Memory * formatters.MEGABYTE),
+			DiskQuota:          formatters.ByteSize(app.DiskQuota * formatters.MEGABYTE),
+			Buildpack:          app.BuildpackUrl,
+			Command:            app.Command,
+			HealthCheckType:    app.HealthCheckType,
+			HealthCheckTimeout: app.HealthCheckTimeout,
+			DockerImage:        app.DockerImage,
+			Env:                app.EnvironmentVars,
+		}
+
+		if app.Stack != nil {
+			snapshot.Stack = stackNames[app.Stack.Guid]
+		}
+
+		for _, route := range app.Routes {
+			snapshot.Routes = append(snapshot.Routes, routeSnapshot{Host: route.Host, Domain: route.Domain.Name})
+		}
+
+		for _, service := range app.Services {
+			snapshot.Services = append(snapshot.Services, service.Name)
+		}
+
+		if dropletDir != "" && app.DockerImage == "" {
+			snapshot.Droplet, err = cmd.exportDroplet(app, dropletDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// exportDroplet downloads the droplet of an app into dir and returns its
+// absolute path, since the snapshot may be saved in any directory. Apps that
+// have never been staged have no droplet and are exported without one.
+func (cmd *ExportSpace) exportDroplet(app models.Application, dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, app.Name+".droplet")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = cmd.dropletRepo.DownloadDroplet(app.Guid, file)
+	file.Close()
+	if err != nil {
+		os.Remove(path)
+		if httpErr, ok := err.(errors.HttpError); ok && httpErr.StatusCode() == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (cmd *ExportSpace) exportRoles() (roles roleSnapshot, err error) {
+	for _, role := range snapshotRoles {
+		var users []models.UserFields
+		users, err = cmd.userRepo.ListUsersInSpaceForRole(cmd.config.SpaceFields().Guid, role)
+		if err != nil {
+			return
+		}
+
+		usernames := []string{}
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+
+		switch role {
+		case models.SPACE_MANAGER:
+			roles.Managers = usernames
+		case models.SPACE_DEVELOPER:
+			roles.Developers = usernames
+		case models.SPACE_AUDITOR:
+			roles.Auditors = usernames
+		}
+	}
+	return
+}
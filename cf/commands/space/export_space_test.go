@@ -0,0 +1,260 @@
+package space_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	testbits "github.com/cloudfoundry/cli/cf/api/application_bits/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	testsecuritygroup "github.com/cloudfoundry/cli/cf/api/security_groups/fakes"
+	teststacks "github.com/cloudfoundry/cli/cf/api/stacks/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("export-space command", func() {
+	var (
+		ui                      *testterm.FakeUI
+		configRepo              core_config.Repository
+		requirementsFactory     *testreq.FakeReqFactory
+		appSummaryRepo          *testapi.FakeAppSummaryRepo
+		stackRepo               *teststacks.FakeStackRepository
+		serviceSummaryRepo      *testapi.FakeServiceSummaryRepo
+		serviceRepo             *testapi.FakeServiceRepo
+		userProvidedServiceRepo *testapi.FakeUserProvidedServiceInstanceRepository
+		userRepo                *testapi.FakeUserRepository
+		securityGroupRepo       *testsecuritygroup.FakeSecurityGroupRepo
+		dropletRepo             *testbits.FakeApplicationDropletRepository
+		deps                    command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceSummaryRepository(serviceSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserProvidedServiceInstanceRepository(userProvidedServiceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserRepository(userRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(securityGroupRepo)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationDropletRepository(dropletRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("export-space").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{}
+		stackRepo = &teststacks.FakeStackRepository{}
+		serviceSummaryRepo = &testapi.FakeServiceSummaryRepo{}
+		serviceRepo = &testapi.FakeServiceRepo{}
+		userProvidedServiceRepo = &testapi.FakeUserProvidedServiceInstanceRepository{}
+		userRepo = &testapi.FakeUserRepository{}
+		securityGroupRepo = &testsecuritygroup.FakeSecurityGroupRepo{}
+		dropletRepo = &testbits.FakeApplicationDropletRepository{}
+
+		app := models.Application{}
+		app.Guid = "my-app-guid"
+		app.Name = "my-app"
+		app.State = "started"
+		app.InstanceCount = 2
+		app.Memory = 512
+		app.DiskQuota = 1024
+		app.Command = "run me"
+		app.HealthCheckType = "port"
+		app.EnvironmentVars = map[string]interface{}{"FOO": "bar"}
+		app.Stack = &models.Stack{Guid: "stack-guid"}
+		app.Routes = []models.RouteSummary{{Host: "my-app", Domain: models.DomainFields{Name: "example.com"}}}
+		app.Services = []models.ServicePlanSummary{{Name: "my-db"}}
+		appSummaryRepo.GetSummariesInCurrentSpaceApps = []models.Application{app}
+		appSummaryRepo.GetSummarySummary = app
+
+		stackRepo.FindAllReturns([]models.Stack{{Guid: "stack-guid", Name: "cflinuxfs2"}}, nil)
+
+		db := models.ServiceInstance{}
+		db.Name = "my-db"
+		db.ServicePlan = models.ServicePlanFields{Guid: "plan-guid", Name: "small"}
+		db.ServiceOffering = models.ServiceOfferingFields{Label: "mysql"}
+		ups := models.ServiceInstance{}
+		ups.Name = "my-ups"
+		serviceSummaryRepo.GetSummariesInCurrentSpaceInstances = []models.ServiceInstance{db, ups}
+
+		found := models.ServiceInstance{}
+		found.Tags = []string{"sql"}
+		serviceRepo.FindInstanceByNameServiceInstance = found
+
+		userProvidedServiceRepo.GetSummariesReturns(models.UserProvidedServiceSummary{
+			Resources: []models.UserProvidedServiceEntity{
+				{UserProvidedService: models.UserProvidedService{Name: "my-ups", SpaceGuid: "my-space-guid", Credentials: map[string]interface{}{"uri": "db://"}}},
+				{UserProvidedService: models.UserProvidedService{Name: "other-ups", SpaceGuid: "other-space-guid"}},
+			},
+		}, nil)
+
+		userRepo.ListUsersByRole = map[string][]models.UserFields{
+			models.SPACE_DEVELOPER: {{Username: "dev@example.com"}},
+		}
+
+		securityGroupRepo.FindAllReturns([]models.SecurityGroup{
+			{
+				SecurityGroupFields: models.SecurityGroupFields{Name: "my-group", Rules: []map[string]interface{}{{"protocol": "tcp"}}},
+				Spaces:              []models.Space{{SpaceFields: models.SpaceFields{Guid: "my-space-guid"}}},
+			},
+			{
+				SecurityGroupFields: models.SecurityGroupFields{Name: "other-group"},
+			},
+		}, nil)
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("export-space", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	exported := func() map[interface{}]interface{} {
+		snapshot := map[interface{}]interface{}{}
+		err := yaml.Unmarshal([]byte(strings.Join(ui.Outputs, "\n")), &snapshot)
+		Expect(err).NotTo(HaveOccurred())
+		return snapshot
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand()).To(BeFalse())
+		})
+
+		It("fails with usage when provided an argument", func() {
+			runCommand("my-space")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "No argument required"},
+			))
+		})
+	})
+
+	It("writes the apps of the space", func() {
+		runCommand()
+
+		apps := exported()["apps"].([]interface{})
+		Expect(apps).To(HaveLen(1))
+
+		app := apps[0].(map[interface{}]interface{})
+		Expect(app["name"]).To(Equal("my-app"))
+		Expect(app["state"]).To(Equal("started"))
+		Expect(app["instances"]).To(Equal(2))
+		Expect(app["memory"]).To(Equal("512M"))
+		Expect(app["disk_quota"]).To(Equal("1G"))
+		Expect(app["command"]).To(Equal("run me"))
+		Expect(app["health_check_type"]).To(Equal("port"))
+		Expect(app["stack"]).To(Equal("cflinuxfs2"))
+		Expect(app["env"]).To(Equal(map[interface{}]interface{}{"FOO": "bar"}))
+		Expect(app["routes"]).To(Equal([]interface{}{map[interface{}]interface{}{"host": "my-app", "domain": "example.com"}}))
+		Expect(app["services"]).To(Equal([]interface{}{"my-db"}))
+		Expect(app).NotTo(HaveKey("droplet"))
+	})
+
+	It("writes the services, user provided services, roles and security groups of the space", func() {
+		runCommand()
+
+		snapshot := exported()
+		Expect(snapshot["space"]).To(Equal("my-space"))
+		Expect(snapshot["services"]).To(Equal([]interface{}{
+			map[interface{}]interface{}{"name": "my-db", "service": "mysql", "plan": "small", "tags": []interface{}{"sql"}},
+		}))
+		Expect(snapshot["user_provided_services"]).To(Equal([]interface{}{
+			map[interface{}]interface{}{"name": "my-ups", "credentials": map[interface{}]interface{}{"uri": "db://"}},
+		}))
+		Expect(snapshot["roles"]).To(Equal(map[interface{}]interface{}{"developers": []interface{}{"dev@example.com"}}))
+		Expect(snapshot["security_groups"]).To(Equal([]interface{}{
+			map[interface{}]interface{}{"name": "my-group", "rules": []interface{}{map[interface{}]interface{}{"protocol": "tcp"}}},
+		}))
+	})
+
+	It("fails when a repository returns an error", func() {
+		securityGroupRepo.FindAllReturns(nil, errors.New("security groups are down"))
+
+		runCommand()
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"security groups are down"},
+		))
+	})
+
+	Context("with --droplets", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "export-space")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("downloads the droplets of the apps", func() {
+			dropletRepo.DownloadDropletStub = func(appGuid string, destination io.Writer) (string, error) {
+				destination.Write([]byte("droplet contents"))
+				return "sha", nil
+			}
+
+			runCommand("--droplets", dir)
+
+			path := filepath.Join(dir, "my-app.droplet")
+			app := exported()["apps"].([]interface{})[0].(map[interface{}]interface{})
+			Expect(app["droplet"]).To(Equal(path))
+
+			contents, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("droplet contents"))
+		})
+
+		It("writes absolute droplet paths when given a relative directory", func() {
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(dir)).To(Succeed())
+			defer os.Chdir(wd)
+
+			runCommand("--droplets", "droplets")
+
+			realDir, err := filepath.EvalSymlinks(dir)
+			Expect(err).NotTo(HaveOccurred())
+			app := exported()["apps"].([]interface{})[0].(map[interface{}]interface{})
+			Expect(app["droplet"]).To(Equal(filepath.Join(realDir, "droplets", "my-app.droplet")))
+		})
+
+		It("exports apps that were never staged without a droplet", func() {
+			dropletRepo.DownloadDropletReturns("", errors.NewHttpError(404, "10010", "Droplet not found"))
+
+			runCommand("--droplets", dir)
+
+			app := exported()["apps"].([]interface{})[0].(map[interface{}]interface{})
+			Expect(app).NotTo(HaveKey("droplet"))
+
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+	})
+})
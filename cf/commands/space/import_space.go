@@ -0,0 +1,489 @@
+package space
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/actors/service_builder"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/application_bits"
+	"github.com/cloudfoundry/cli/cf/api/applications"
+	"github.com/cloudfoundry/cli/cf/api/security_groups"
+	securitygroupspaces "github.com/cloudfoundry/cli/cf/api/security_groups/spaces"
+	"github.com/cloudfoundry/cli/cf/api/stacks"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"gopkg.in/yaml.v2"
+)
+
+type ImportSpace struct {
+	ui                       terminal.UI
+	config                   core_config.Reader
+	appRepo                  applications.ApplicationRepository
+	stackRepo                stacks.StackRepository
+	domainRepo               api.DomainRepository
+	routeRepo                api.RouteRepository
+	serviceRepo              api.ServiceRepository
+	serviceBindingRepo       api.ServiceBindingRepository
+	serviceBuilder           service_builder.ServiceBuilder
+	userProvidedServiceRepo  api.UserProvidedServiceInstanceRepository
+	userRepo                 api.UserRepository
+	securityGroupRepo        security_groups.SecurityGroupRepo
+	securityGroupSpaceBinder securitygroupspaces.SecurityGroupSpaceBinder
+	dropletRepo              application_bits.ApplicationDropletRepository
+}
+
+func init() {
+	command_registry.Register(&ImportSpace{})
+}
+
+func (cmd *ImportSpace) MetaData() command_registry.CommandMetadata {
+	baseUsage := T("CF_NAME import-space FILE")
+	exampleUsage := T(`EXAMPLE:
+   CF_NAME import-space space.yml`)
+	tipUsage := T(`TIP:
+   Importing is idempotent: existing apps are updated, and existing services, routes, bindings and roles are kept. Relative droplet paths are resolved against the directory of FILE; export-space writes absolute ones.`)
+
+	return command_registry.CommandMetadata{
+		Name:        "import-space",
+		Description: T("Recreate the apps, services, roles and security groups written by export-space in the targeted space"),
+		Usage:       strings.Join([]string{baseUsage, exampleUsage, tipUsage}, "\n\n"),
+	}
+}
+
+func (cmd *ImportSpace) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("import-space"))
+	}
+
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedSpaceRequirement(),
+	}
+	return
+}
+
+func (cmd *ImportSpace) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.appRepo = deps.RepoLocator.GetApplicationRepository()
+	cmd.stackRepo = deps.RepoLocator.GetStackRepository()
+	cmd.domainRepo = deps.RepoLocator.GetDomainRepository()
+	cmd.routeRepo = deps.RepoLocator.GetRouteRepository()
+	cmd.serviceRepo = deps.RepoLocator.GetServiceRepository()
+	cmd.serviceBindingRepo = deps.RepoLocator.GetServiceBindingRepository()
+	cmd.serviceBuilder = deps.ServiceBuilder
+	cmd.userProvidedServiceRepo = deps.RepoLocator.GetUserProvidedServiceInstanceRepository()
+	cmd.userRepo = deps.RepoLocator.GetUserRepository()
+	cmd.securityGroupRepo = deps.RepoLocator.GetSecurityGroupRepository()
+	cmd.securityGroupSpaceBinder = deps.RepoLocator.GetSecurityGroupSpaceBinder()
+	cmd.dropletRepo = deps.RepoLocator.GetApplicationDropletRepository()
+	return cmd
+}
+
+func (cmd *ImportSpace) Execute(c flags.FlagContext) {
+	path := c.Args()[0]
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	snapshot := spaceSnapshot{}
+	err = yaml.Unmarshal(contents, &snapshot)
+	if err != nil {
+		cmd.ui.Failed(T("Error reading {{.Path}}: {{.Err}}", map[string]interface{}{"Path": path, "Err": err.Error()}))
+	}
+
+	cmd.ui.Say(T("Importing space {{.SnapshotName}} into org {{.OrgName}} / space {{.SpaceName}} as {{.CurrentUser}}...",
+		map[string]interface{}{
+			"SnapshotName": terminal.EntityNameColor(snapshot.Space),
+			"OrgName":      terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"SpaceName":    terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+			"CurrentUser":  terminal.EntityNameColor(cmd.config.Username()),
+		}))
+
+	for _, group := range snapshot.SecurityGroups {
+		err = cmd.importSecurityGroup(group)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	for _, service := range snapshot.UserProvidedServices {
+		err = cmd.importUserProvidedService(service)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	for _, service := range snapshot.Services {
+		err = cmd.importService(service)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	appsWithoutFiles := []string{}
+	for _, app := range snapshot.Apps {
+		var started bool
+		started, err = cmd.importApp(app, filepath.Dir(path))
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+		if !started && app.State == "started" {
+			appsWithoutFiles = append(appsWithoutFiles, app.Name)
+		}
+	}
+
+	usernamesByRole := snapshot.Roles.byRole()
+	for _, role := range snapshotRoles {
+		for _, username := range usernamesByRole[role] {
+			err = cmd.importRole(username, role)
+			if err != nil {
+				cmd.ui.Failed(err.Error())
+			}
+		}
+	}
+
+	cmd.ui.Ok()
+
+	if len(appsWithoutFiles) > 0 {
+		cmd.ui.Say("")
+		cmd.ui.Say(T("TIP: Apps {{.AppNames}} have no droplet in the snapshot. Use '{{.Command}}' to push their files and start them.",
+			map[string]interface{}{
+				"AppNames": terminal.EntityNameColor(strings.Join(appsWithoutFiles, ", ")),
+				"Command":  terminal.CommandColor(cf.Name() + " push"),
+			}))
+	}
+}
+
+func (cmd *ImportSpace) importSecurityGroup(snapshot securityGroupSnapshot) error {
+	rules := []map[string]interface{}{}
+	for _, rule := range snapshot.Rules {
+		rules = append(rules, jsonCompatibleMap(rule))
+	}
+
+	group, err := cmd.securityGroupRepo.Read(snapshot.Name)
+	switch err.(type) {
+	case nil:
+	case *errors.ModelNotFoundError:
+		cmd.ui.Say(T("Creating security group {{.security_group}}...", map[string]interface{}{"security_group": terminal.EntityNameColor(snapshot.Name)}))
+
+		err = cmd.securityGroupRepo.Create(snapshot.Name, rules)
+		if err != nil {
+			return err
+		}
+
+		group, err = cmd.securityGroupRepo.Read(snapshot.Name)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	for _, space := range group.Spaces {
+		if space.Guid == cmd.config.SpaceFields().Guid {
+			return nil
+		}
+	}
+
+	cmd.ui.Say(T("Binding security group {{.security_group}} to space {{.space}}...",
+		map[string]interface{}{
+			"security_group": terminal.EntityNameColor(snapshot.Name),
+			"space":          terminal.EntityNameColor(cmd.config.SpaceFields().Name),
+		}))
+
+	return cmd.securityGroupSpaceBinder.BindSpace(group.Guid, cmd.config.SpaceFields().Guid)
+}
+
+func (cmd *ImportSpace) importUserProvidedService(snapshot userProvidedServiceSnapshot) error {
+	credentials := jsonCompatibleMap(snapshot.Credentials)
+	if credentials == nil {
+		credentials = map[string]interface{}{}
+	}
+
+	instance, err := cmd.serviceRepo.FindInstanceByName(snapshot.Name)
+	switch err.(type) {
+	case nil:
+		if !instance.IsUserProvided() {
+			return errors.New(T("Service instance {{.ServiceName}} already exists and is not user-provided",
+				map[string]interface{}{"ServiceName": snapshot.Name}))
+		}
+
+		cmd.ui.Say(T("Updating user provided service {{.ServiceName}}...", map[string]interface{}{"ServiceName": terminal.EntityNameColor(snapshot.Name)}))
+
+		instance.Params = credentials
+		instance.SysLogDrainUrl = snapshot.SyslogDrainUrl
+		return cmd.userProvidedServiceRepo.Update(instance.ServiceInstanceFields)
+	case *errors.ModelNotFoundError:
+		cmd.ui.Say(T("Creating user provided service {{.ServiceName}}...", map[string]interface{}{"ServiceName": terminal.EntityNameColor(snapshot.Name)}))
+
+		return cmd.userProvidedServiceRepo.Create(snapshot.Name, snapshot.SyslogDrainUrl, credentials)
+	default:
+		return err
+	}
+}
+
+func (cmd *ImportSpace) importService(snapshot serviceSnapshot) error {
+	_, err := cmd.serviceRepo.FindInstanceByName(snapshot.Name)
+	switch err.(type) {
+	case nil:
+		cmd.ui.Say(T("Service instance {{.ServiceName}} already exists", map[string]interface{}{"ServiceName": terminal.EntityNameColor(snapshot.Name)}))
+		return nil
+	case *errors.ModelNotFoundError:
+	default:
+		return err
+	}
+
+	cmd.ui.Say(T("Creating service instance {{.ServiceName}}...", map[string]interface{}{"ServiceName": terminal.EntityNameColor(snapshot.Name)}))
+
+	offerings, err := cmd.serviceBuilder.GetServicesByNameForSpaceWithPlans(cmd.config.SpaceFields().Guid, snapshot.Service)
+	if err != nil {
+		return err
+	}
+
+	for _, offering := range offerings {
+		for _, plan := range offering.Plans {
+			if plan.Name == snapshot.Plan {
+				return cmd.serviceRepo.CreateServiceInstance(snapshot.Name, plan.Guid, jsonCompatibleMap(snapshot.Parameters), snapshot.Tags)
+			}
+		}
+	}
+
+	return errors.New(T("Could not find plan {{.PlanName}} of service {{.ServiceLabel}}",
+		map[string]interface{}{"PlanName": snapshot.Plan, "ServiceLabel": snapshot.Service}))
+}
+
+// importApp creates or updates an app and binds its routes and services. The
+// app is started when the snapshot has a droplet or docker image for it; it
+// returns whether the app was started.
+func (cmd *ImportSpace) importApp(snapshot appSnapshot, dir string) (bool, error) {
+	params, err := cmd.appParams(snapshot)
+	if err != nil {
+		return false, err
+	}
+
+	app, err := cmd.appRepo.Read(snapshot.Name)
+	switch err.(type) {
+	case nil:
+		cmd.ui.Say(T("Updating app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(snapshot.Name)}))
+
+		_, err = cmd.appRepo.Update(app.Guid, params)
+	case *errors.ModelNotFoundError:
+		cmd.ui.Say(T("Creating app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(snapshot.Name)}))
+
+		spaceGuid := cmd.config.SpaceFields().Guid
+		params.Name = &snapshot.Name
+		params.SpaceGuid = &spaceGuid
+		app, err = cmd.appRepo.Create(params)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, route := range snapshot.Routes {
+		err = cmd.importRoute(app, route)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for _, serviceName := range snapshot.Services {
+		err = cmd.bindService(app, serviceName)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if snapshot.Droplet != "" {
+		err = cmd.uploadDroplet(app, snapshot.Droplet, dir)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if snapshot.State != "started" || (snapshot.Droplet == "" && snapshot.DockerImage == "") {
+		return false, nil
+	}
+
+	if app.State != "started" {
+		cmd.ui.Say(T("Starting app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+		state := "STARTED"
+		_, err = cmd.appRepo.Update(app.Guid, models.AppParams{State: &state})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (cmd *ImportSpace) appParams(snapshot appSnapshot) (params models.AppParams, err error) {
+	env := jsonCompatibleMap(snapshot.Env)
+	if env == nil {
+		env = map[string]interface{}{}
+	}
+
+	params = models.AppParams{
+		BuildpackUrl:    &snapshot.Buildpack,
+		Command:         &snapshot.Command,
+		EnvironmentVars: &env,
+	}
+
+	if snapshot.Instances != 0 {
+		params.InstanceCount = &snapshot.Instances
+	}
+
+	if snapshot.Memory != "" {
+		var memory int64
+		memory, err = formatters.ToMegabytes(snapshot.Memory)
+		if err != nil {
+			return
+		}
+		params.Memory = &memory
+	}
+
+	if snapshot.DiskQuota != "" {
+		var diskQuota int64
+		diskQuota, err = formatters.ToMegabytes(snapshot.DiskQuota)
+		if err != nil {
+			return
+		}
+		params.DiskQuota = &diskQuota
+	}
+
+	if snapshot.HealthCheckType != "" {
+		params.HealthCheckType = &snapshot.HealthCheckType
+	}
+
+	if snapshot.HealthCheckTimeout != 0 {
+		params.HealthCheckTimeout = &snapshot.HealthCheckTimeout
+	}
+
+	if snapshot.DockerImage != "" {
+		params.DockerImage = &snapshot.DockerImage
+	}
+
+	if snapshot.Stack != "" {
+		var stack models.Stack
+		stack, err = cmd.stackRepo.FindByName(snapshot.Stack)
+		switch err.(type) {
+		case nil:
+			params.StackGuid = &stack.Guid
+		case *errors.ModelNotFoundError:
+			err = nil
+			cmd.ui.Warn(T("Stack {{.Stack}} not found, app {{.AppName}} uses the default stack",
+				map[string]interface{}{"Stack": snapshot.Stack, "AppName": snapshot.Name}))
+		}
+	}
+
+	return
+}
+
+func (cmd *ImportSpace) importRoute(app models.Application, snapshot routeSnapshot) error {
+	domain, err := cmd.domainRepo.FindByNameInOrg(snapshot.Domain, cmd.config.OrganizationFields().Guid)
+	if err != nil {
+		return err
+	}
+
+	route, err := cmd.routeRepo.FindByHostAndDomain(snapshot.Host, domain)
+	switch err.(type) {
+	case nil:
+		if route.Space.Guid != cmd.config.SpaceFields().Guid {
+			cmd.ui.Warn(T("Skipping route {{.URL}} because it belongs to another space.", map[string]interface{}{"URL": route.URL()}))
+			return nil
+		}
+		if app.HasRoute(route) {
+			return nil
+		}
+	case *errors.ModelNotFoundError:
+		route = models.Route{Host: snapshot.Host, Domain: domain}
+		cmd.ui.Say(T("Creating route {{.URL}}...", map[string]interface{}{"URL": terminal.EntityNameColor(route.URL())}))
+
+		route, err = cmd.routeRepo.CreateInSpace(snapshot.Host, domain.Guid, cmd.config.SpaceFields().Guid)
+		if err != nil {
+			return err
+		}
+		route.Host = snapshot.Host
+		route.Domain = domain
+	default:
+		return err
+	}
+
+	cmd.ui.Say(T("Binding {{.URL}} to {{.AppName}}...",
+		map[string]interface{}{
+			"URL":     terminal.EntityNameColor(route.URL()),
+			"AppName": terminal.EntityNameColor(app.Name),
+		}))
+
+	return cmd.routeRepo.Bind(route.Guid, app.Guid)
+}
+
+func (cmd *ImportSpace) bindService(app models.Application, serviceName string) error {
+	instance, err := cmd.serviceRepo.FindInstanceByName(serviceName)
+	if err != nil {
+		return err
+	}
+
+	cmd.ui.Say(T("Binding service {{.ServiceName}} to app {{.AppName}}...",
+		map[string]interface{}{
+			"ServiceName": terminal.EntityNameColor(serviceName),
+			"AppName":     terminal.EntityNameColor(app.Name),
+		}))
+
+	err = cmd.serviceBindingRepo.Create(instance.Guid, app.Guid, nil)
+	if httpErr, ok := err.(errors.HttpError); ok && httpErr.ErrorCode() == errors.APP_ALREADY_BOUND {
+		return nil
+	}
+	return err
+}
+
+func (cmd *ImportSpace) uploadDroplet(app models.Application, path, dir string) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	cmd.ui.Say(T("Uploading droplet of app {{.AppName}}...", map[string]interface{}{"AppName": terminal.EntityNameColor(app.Name)}))
+
+	return cmd.dropletRepo.UploadDroplet(app.Guid, file)
+}
+
+func (cmd *ImportSpace) importRole(username, role string) error {
+	user, err := cmd.userRepo.FindByUsername(username)
+	switch err.(type) {
+	case nil:
+	case *errors.ModelNotFoundError:
+		cmd.ui.Warn(T("User {{.Username}} not found, skipping role {{.Role}}",
+			map[string]interface{}{"Username": username, "Role": role}))
+		return nil
+	default:
+		return err
+	}
+
+	cmd.ui.Say(T("Assigning role {{.Role}} to user {{.Username}}...",
+		map[string]interface{}{
+			"Role":     terminal.EntityNameColor(role),
+			"Username": terminal.EntityNameColor(username),
+		}))
+
+	return cmd.userRepo.SetSpaceRole(user.Guid, cmd.config.SpaceFields().Guid, cmd.config.OrganizationFields().Guid, role)
+}
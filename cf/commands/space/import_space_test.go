@@ -0,0 +1,455 @@
+package space_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	servicebuilderfakes "github.com/cloudfoundry/cli/cf/actors/service_builder/fakes"
+	testbits "github.com/cloudfoundry/cli/cf/api/application_bits/fakes"
+	testApplication "github.com/cloudfoundry/cli/cf/api/applications/fakes"
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	testsecuritygroup "github.com/cloudfoundry/cli/cf/api/security_groups/fakes"
+	testsecuritygroupspaces "github.com/cloudfoundry/cli/cf/api/security_groups/spaces/fakes"
+	teststacks "github.com/cloudfoundry/cli/cf/api/stacks/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import-space command", func() {
+	var (
+		ui                       *testterm.FakeUI
+		configRepo               core_config.Repository
+		requirementsFactory      *testreq.FakeReqFactory
+		appRepo                  *testApplication.FakeApplicationRepository
+		stackRepo                *teststacks.FakeStackRepository
+		domainRepo               *testapi.FakeDomainRepository
+		routeRepo                *testapi.FakeRouteRepository
+		serviceRepo              *testapi.FakeServiceRepo
+		serviceBindingRepo       *testapi.FakeServiceBindingRepo
+		serviceBuilder           *servicebuilderfakes.FakeServiceBuilder
+		userProvidedServiceRepo  *testapi.FakeUserProvidedServiceInstanceRepository
+		userRepo                 *testapi.FakeUserRepository
+		securityGroupRepo        *testsecuritygroup.FakeSecurityGroupRepo
+		securityGroupSpaceBinder *testsecuritygroupspaces.FakeSecurityGroupSpaceBinder
+		dropletRepo              *testbits.FakeApplicationDropletRepository
+		deps                     command_registry.Dependency
+		dir                      string
+		snapshotPath             string
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.ServiceBuilder = serviceBuilder
+		deps.RepoLocator = deps.RepoLocator.SetApplicationRepository(appRepo)
+		deps.RepoLocator = deps.RepoLocator.SetStackRepository(stackRepo)
+		deps.RepoLocator = deps.RepoLocator.SetDomainRepository(domainRepo)
+		deps.RepoLocator = deps.RepoLocator.SetRouteRepository(routeRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceRepository(serviceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceBindingRepository(serviceBindingRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserProvidedServiceInstanceRepository(userProvidedServiceRepo)
+		deps.RepoLocator = deps.RepoLocator.SetUserRepository(userRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupRepository(securityGroupRepo)
+		deps.RepoLocator = deps.RepoLocator.SetSecurityGroupSpaceBinder(securityGroupSpaceBinder)
+		deps.RepoLocator = deps.RepoLocator.SetApplicationDropletRepository(dropletRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("import-space").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedSpaceSuccess: true}
+		appRepo = &testApplication.FakeApplicationRepository{}
+		stackRepo = &teststacks.FakeStackRepository{}
+		domainRepo = &testapi.FakeDomainRepository{}
+		routeRepo = &testapi.FakeRouteRepository{}
+		serviceRepo = &testapi.FakeServiceRepo{}
+		serviceBindingRepo = &testapi.FakeServiceBindingRepo{}
+		serviceBuilder = &servicebuilderfakes.FakeServiceBuilder{}
+		userProvidedServiceRepo = &testapi.FakeUserProvidedServiceInstanceRepository{}
+		userRepo = &testapi.FakeUserRepository{}
+		securityGroupRepo = &testsecuritygroup.FakeSecurityGroupRepo{}
+		securityGroupSpaceBinder = &testsecuritygroupspaces.FakeSecurityGroupSpaceBinder{}
+		dropletRepo = &testbits.FakeApplicationDropletRepository{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "import-space")
+		Expect(err).NotTo(HaveOccurred())
+		snapshotPath = filepath.Join(dir, "space.yml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeSnapshot := func(contents string) {
+		err := ioutil.WriteFile(snapshotPath, []byte(contents), 0644)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("import-space", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand(snapshotPath)).To(BeFalse())
+		})
+
+		It("fails when a space is not targeted", func() {
+			requirementsFactory.TargetedSpaceSuccess = false
+			Expect(runCommand(snapshotPath)).To(BeFalse())
+		})
+
+		It("fails with usage when not provided a file", func() {
+			runCommand()
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "Requires an argument"},
+			))
+		})
+	})
+
+	It("fails when the file is not valid YAML", func() {
+		writeSnapshot("apps: [")
+
+		runCommand(snapshotPath)
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Error reading", snapshotPath},
+		))
+	})
+
+	Describe("apps", func() {
+		BeforeEach(func() {
+			appRepo.ReadReturns.Error = errors.NewModelNotFoundError("App", "my-app")
+			stackRepo.FindByNameReturns(models.Stack{Guid: "stack-guid", Name: "cflinuxfs2"}, nil)
+			domainRepo.FindByNameInOrgDomain = []models.DomainFields{{Name: "example.com", Guid: "domain-guid"}}
+			routeRepo.FindByHostAndDomainReturns.Error = errors.NewModelNotFoundError("Route", "my-app")
+			routeRepo.CreateInSpaceCreatedRoute = models.Route{Guid: "route-guid"}
+
+			db := models.ServiceInstance{}
+			db.Guid = "db-guid"
+			serviceRepo.FindInstanceByNameServiceInstance = db
+
+			writeSnapshot(`space: dev
+apps:
+- name: my-app
+  state: started
+  instances: 2
+  memory: 1G
+  disk_quota: 512M
+  stack: cflinuxfs2
+  env:
+    FOO: bar
+    NESTED:
+      key: value
+  routes:
+  - host: my-app
+    domain: example.com
+  services:
+  - my-db
+`)
+		})
+
+		It("creates apps that do not exist and binds their routes and services", func() {
+			runCommand(snapshotPath)
+
+			params := appRepo.CreatedAppParams()
+			Expect(*params.Name).To(Equal("my-app"))
+			Expect(*params.SpaceGuid).To(Equal("my-space-guid"))
+			Expect(*params.InstanceCount).To(Equal(2))
+			Expect(*params.Memory).To(Equal(int64(1024)))
+			Expect(*params.DiskQuota).To(Equal(int64(512)))
+			Expect(*params.StackGuid).To(Equal("stack-guid"))
+			Expect(*params.EnvironmentVars).To(Equal(map[string]interface{}{
+				"FOO":    "bar",
+				"NESTED": map[string]interface{}{"key": "value"},
+			}))
+
+			Expect(routeRepo.CreateInSpaceHost).To(Equal("my-app"))
+			Expect(routeRepo.CreateInSpaceDomainGuid).To(Equal("domain-guid"))
+			Expect(routeRepo.CreateInSpaceSpaceGuid).To(Equal("my-space-guid"))
+			Expect(routeRepo.BoundRouteGuid).To(Equal("route-guid"))
+			Expect(routeRepo.BoundAppGuid).To(Equal("my-app-guid"))
+
+			Expect(serviceBindingRepo.CreateServiceInstanceGuid).To(Equal("db-guid"))
+			Expect(serviceBindingRepo.CreateApplicationGuid).To(Equal("my-app-guid"))
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Importing space", "dev", "my-org", "my-space", "my-user"},
+				[]string{"Creating app", "my-app"},
+				[]string{"Creating route", "my-app.example.com"},
+				[]string{"Binding service", "my-db"},
+				[]string{"OK"},
+				[]string{"TIP", "my-app", "have no droplet"},
+			))
+		})
+
+		It("updates apps that already exist", func() {
+			app := models.Application{}
+			app.Guid = "existing-app-guid"
+			app.Name = "my-app"
+			appRepo.ReadReturns.Error = nil
+			appRepo.ReadReturns.App = app
+
+			runCommand(snapshotPath)
+
+			Expect(appRepo.CreateAppParams).To(BeEmpty())
+			Expect(appRepo.UpdateAppGuid).To(Equal("existing-app-guid"))
+			Expect(*appRepo.UpdateParams.Memory).To(Equal(int64(1024)))
+		})
+
+		It("does not bind routes the app is already bound to", func() {
+			app := models.Application{}
+			app.Guid = "existing-app-guid"
+			app.Routes = []models.RouteSummary{{Guid: "route-guid"}}
+			appRepo.ReadReturns.Error = nil
+			appRepo.ReadReturns.App = app
+			routeRepo.FindByHostAndDomainReturns.Error = nil
+			routeRepo.FindByHostAndDomainReturns.Route = models.Route{Guid: "route-guid", Space: models.SpaceFields{Guid: "my-space-guid"}}
+
+			runCommand(snapshotPath)
+
+			Expect(routeRepo.BoundRouteGuid).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+		})
+
+		It("ignores services that are already bound", func() {
+			serviceBindingRepo.CreateErrorCode = errors.APP_ALREADY_BOUND
+
+			runCommand(snapshotPath)
+
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+		})
+
+		It("uploads droplets relative to the snapshot and starts the app", func() {
+			err := ioutil.WriteFile(filepath.Join(dir, "my-app.droplet"), []byte("droplet"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			writeSnapshot(`space: dev
+apps:
+- name: my-app
+  state: started
+  droplet: my-app.droplet
+`)
+
+			runCommand(snapshotPath)
+
+			Expect(dropletRepo.UploadDropletCallCount()).To(Equal(1))
+			appGuid, droplet := dropletRepo.UploadDropletArgsForCall(0)
+			Expect(appGuid).To(Equal("my-app-guid"))
+			Expect(droplet.Name()).To(Equal(filepath.Join(dir, "my-app.droplet")))
+
+			Expect(*appRepo.UpdateParams.State).To(Equal("STARTED"))
+			Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"TIP"}))
+		})
+
+		It("uploads droplets exported to absolute paths from another directory", func() {
+			exportDir := filepath.Join(dir, "export")
+			importDir := filepath.Join(dir, "import")
+			Expect(os.MkdirAll(filepath.Join(exportDir, "droplets"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(importDir, 0755)).To(Succeed())
+
+			dropletPath := filepath.Join(exportDir, "droplets", "my-app.droplet")
+			Expect(ioutil.WriteFile(dropletPath, []byte("droplet"), 0644)).To(Succeed())
+			writeSnapshot(`space: dev
+apps:
+- name: my-app
+  state: started
+  droplet: ` + dropletPath + `
+`)
+
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(importDir)).To(Succeed())
+			defer os.Chdir(wd)
+
+			runCommand(snapshotPath)
+
+			Expect(dropletRepo.UploadDropletCallCount()).To(Equal(1))
+			_, droplet := dropletRepo.UploadDropletArgsForCall(0)
+			Expect(droplet.Name()).To(Equal(dropletPath))
+		})
+	})
+
+	Describe("services", func() {
+		BeforeEach(func() {
+			serviceRepo.FindInstanceByNameNotFound = true
+			serviceBuilder.GetServicesByNameForSpaceWithPlansReturns(models.ServiceOfferings{
+				{Plans: []models.ServicePlanFields{{Name: "large", Guid: "large-guid"}, {Name: "small", Guid: "small-guid"}}},
+			}, nil)
+
+			writeSnapshot(`space: dev
+services:
+- name: my-db
+  service: mysql
+  plan: small
+  tags: [sql]
+  parameters:
+    size: 10
+`)
+		})
+
+		It("creates service instances that do not exist", func() {
+			runCommand(snapshotPath)
+
+			spaceGuid, label := serviceBuilder.GetServicesByNameForSpaceWithPlansArgsForCall(0)
+			Expect(spaceGuid).To(Equal("my-space-guid"))
+			Expect(label).To(Equal("mysql"))
+
+			Expect(serviceRepo.CreateServiceInstanceArgs.Name).To(Equal("my-db"))
+			Expect(serviceRepo.CreateServiceInstanceArgs.PlanGuid).To(Equal("small-guid"))
+			Expect(serviceRepo.CreateServiceInstanceArgs.Params).To(Equal(map[string]interface{}{"size": 10}))
+			Expect(serviceRepo.CreateServiceInstanceArgs.Tags).To(Equal([]string{"sql"}))
+		})
+
+		It("keeps service instances that exist", func() {
+			serviceRepo.FindInstanceByNameNotFound = false
+
+			runCommand(snapshotPath)
+
+			Expect(serviceRepo.CreateServiceInstanceArgs.Name).To(BeEmpty())
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"my-db", "already exists"},
+				[]string{"OK"},
+			))
+		})
+
+		It("fails when the plan does not exist", func() {
+			serviceBuilder.GetServicesByNameForSpaceWithPlansReturns(models.ServiceOfferings{}, nil)
+
+			runCommand(snapshotPath)
+
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"FAILED"},
+				[]string{"Could not find plan small of service mysql"},
+			))
+		})
+	})
+
+	Describe("user provided services", func() {
+		BeforeEach(func() {
+			writeSnapshot(`space: dev
+user_provided_services:
+- name: my-ups
+  credentials:
+    uri: db://
+  syslog_drain_url: syslog://drain
+`)
+		})
+
+		It("creates user provided services that do not exist", func() {
+			serviceRepo.FindInstanceByNameNotFound = true
+
+			runCommand(snapshotPath)
+
+			Expect(userProvidedServiceRepo.CreateCallCount()).To(Equal(1))
+			name, drainUrl, credentials := userProvidedServiceRepo.CreateArgsForCall(0)
+			Expect(name).To(Equal("my-ups"))
+			Expect(drainUrl).To(Equal("syslog://drain"))
+			Expect(credentials).To(Equal(map[string]interface{}{"uri": "db://"}))
+		})
+
+		It("updates user provided services that exist", func() {
+			ups := models.ServiceInstance{}
+			ups.Guid = "ups-guid"
+			serviceRepo.FindInstanceByNameServiceInstance = ups
+
+			runCommand(snapshotPath)
+
+			Expect(userProvidedServiceRepo.CreateCallCount()).To(Equal(0))
+			Expect(userProvidedServiceRepo.UpdateCallCount()).To(Equal(1))
+			fields := userProvidedServiceRepo.UpdateArgsForCall(0)
+			Expect(fields.Guid).To(Equal("ups-guid"))
+			Expect(fields.Params).To(Equal(map[string]interface{}{"uri": "db://"}))
+			Expect(fields.SysLogDrainUrl).To(Equal("syslog://drain"))
+		})
+	})
+
+	Describe("security groups", func() {
+		BeforeEach(func() {
+			writeSnapshot(`space: dev
+security_groups:
+- name: my-group
+  rules:
+  - protocol: tcp
+    destination: 10.0.0.0/8
+`)
+		})
+
+		It("creates security groups that do not exist and binds them to the space", func() {
+			securityGroupRepo.ReadStub = func(name string) (models.SecurityGroup, error) {
+				if securityGroupRepo.CreateCallCount() == 0 {
+					return models.SecurityGroup{}, errors.NewModelNotFoundError("security group", name)
+				}
+				return models.SecurityGroup{SecurityGroupFields: models.SecurityGroupFields{Guid: "group-guid"}}, nil
+			}
+
+			runCommand(snapshotPath)
+
+			name, rules := securityGroupRepo.CreateArgsForCall(0)
+			Expect(name).To(Equal("my-group"))
+			Expect(rules).To(Equal([]map[string]interface{}{{"protocol": "tcp", "destination": "10.0.0.0/8"}}))
+
+			groupGuid, spaceGuid := securityGroupSpaceBinder.BindSpaceArgsForCall(0)
+			Expect(groupGuid).To(Equal("group-guid"))
+			Expect(spaceGuid).To(Equal("my-space-guid"))
+		})
+
+		It("does not bind security groups that are already bound to the space", func() {
+			securityGroupRepo.ReadReturns(models.SecurityGroup{
+				SecurityGroupFields: models.SecurityGroupFields{Guid: "group-guid"},
+				Spaces:              []models.Space{{SpaceFields: models.SpaceFields{Guid: "my-space-guid"}}},
+			}, nil)
+
+			runCommand(snapshotPath)
+
+			Expect(securityGroupRepo.CreateCallCount()).To(Equal(0))
+			Expect(securityGroupSpaceBinder.BindSpaceCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("roles", func() {
+		BeforeEach(func() {
+			writeSnapshot(`space: dev
+roles:
+  developers:
+  - dev@example.com
+`)
+		})
+
+		It("assigns space roles to users", func() {
+			userRepo.FindByUsernameUserFields = models.UserFields{Guid: "dev-guid", Username: "dev@example.com"}
+
+			runCommand(snapshotPath)
+
+			Expect(userRepo.FindByUsernameUsername).To(Equal("dev@example.com"))
+			Expect(userRepo.SetSpaceRoleUserGuid).To(Equal("dev-guid"))
+			Expect(userRepo.SetSpaceRoleSpaceGuid).To(Equal("my-space-guid"))
+			Expect(userRepo.SetSpaceRoleOrgGuid).To(Equal("my-org-guid"))
+			Expect(userRepo.SetSpaceRoleRole).To(Equal(models.SPACE_DEVELOPER))
+		})
+
+		It("warns about users that do not exist", func() {
+			userRepo.FindByUsernameNotFound = true
+
+			runCommand(snapshotPath)
+
+			Expect(userRepo.SetSpaceRoleUserGuid).To(BeEmpty())
+			Expect(ui.WarnOutputs).To(ContainSubstrings([]string{"dev@example.com", "not found"}))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"OK"}))
+		})
+	})
+})
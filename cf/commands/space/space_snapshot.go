@@ -0,0 +1,116 @@
+package space
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/models"
+)
+
+// spaceSnapshot is the document written by export-space and read by
+// import-space.
+type spaceSnapshot struct {
+	Space                string                        `yaml:"space"`
+	SecurityGroups       []securityGroupSnapshot       `yaml:"security_groups,omitempty"`
+	UserProvidedServices []userProvidedServiceSnapshot `yaml:"user_provided_services,omitempty"`
+	Services             []serviceSnapshot             `yaml:"services,omitempty"`
+	Apps                 []appSnapshot                 `yaml:"apps,omitempty"`
+	Roles                roleSnapshot                  `yaml:"roles,omitempty"`
+}
+
+type securityGroupSnapshot struct {
+	Name  string                   `yaml:"name"`
+	Rules []map[string]interface{} `yaml:"rules"`
+}
+
+type userProvidedServiceSnapshot struct {
+	Name           string                 `yaml:"name"`
+	Credentials    map[string]interface{} `yaml:"credentials,omitempty"`
+	SyslogDrainUrl string                 `yaml:"syslog_drain_url,omitempty"`
+}
+
+// serviceSnapshot describes a managed service instance. The cloud controller
+// does not return the parameters an instance was created with, so they are
+// only set by hand.
+type serviceSnapshot struct {
+	Name       string                 `yaml:"name"`
+	Service    string                 `yaml:"service"`
+	Plan       string                 `yaml:"plan"`
+	Tags       []string               `yaml:"tags,omitempty"`
+	Parameters map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+type appSnapshot struct {
+	Name               string                 `yaml:"name"`
+	State              string                 `yaml:"state,omitempty"`
+	Instances          int                    `yaml:"instances,omitempty"`
+	Memory             string                 `yaml:"memory,omitempty"`
+	DiskQuota          string                 `yaml:"disk_quota,omitempty"`
+	Buildpack          string                 `yaml:"buildpack,omitempty"`
+	Command            string                 `yaml:"command,omitempty"`
+	Stack              string                 `yaml:"stack,omitempty"`
+	HealthCheckType    string                 `yaml:"health_check_type,omitempty"`
+	HealthCheckTimeout int                    `yaml:"health_check_timeout,omitempty"`
+	DockerImage        string                 `yaml:"docker_image,omitempty"`
+	Env                map[string]interface{} `yaml:"env,omitempty"`
+	Routes             []routeSnapshot        `yaml:"routes,omitempty"`
+	Services           []string               `yaml:"services,omitempty"`
+	Droplet            string                 `yaml:"droplet,omitempty"`
+}
+
+type routeSnapshot struct {
+	Host   string `yaml:"host,omitempty"`
+	Domain string `yaml:"domain"`
+}
+
+type roleSnapshot struct {
+	Managers   []string `yaml:"managers,omitempty"`
+	Developers []string `yaml:"developers,omitempty"`
+	Auditors   []string `yaml:"auditors,omitempty"`
+}
+
+// byRole returns the usernames of the snapshot keyed by space role.
+func (roles roleSnapshot) byRole() map[string][]string {
+	return map[string][]string{
+		models.SPACE_MANAGER:   roles.Managers,
+		models.SPACE_DEVELOPER: roles.Developers,
+		models.SPACE_AUDITOR:   roles.Auditors,
+	}
+}
+
+var snapshotRoles = []string{models.SPACE_MANAGER, models.SPACE_DEVELOPER, models.SPACE_AUDITOR}
+
+// jsonCompatible converts the map[interface{}]interface{} values the YAML
+// decoder produces for nested mappings into maps that can be sent to the
+// cloud controller as JSON.
+func jsonCompatible(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, nested := range value {
+			converted[fmt.Sprintf("%v", key)] = jsonCompatible(nested)
+		}
+		return converted
+	case map[string]interface{}:
+		return jsonCompatibleMap(value)
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, nested := range value {
+			converted[i] = jsonCompatible(nested)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+func jsonCompatibleMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	converted := map[string]interface{}{}
+	for key, value := range values {
+		converted[key] = jsonCompatible(value)
+	}
+	return converted
+}
@@ -0,0 +1,137 @@
+package space
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf"
+	"github.com/cloudfoundry/cli/cf/api"
+	"github.com/cloudfoundry/cli/cf/api/space_quotas"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/ui_helpers"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type SpaceUsage struct {
+	ui                 terminal.UI
+	config             core_config.Reader
+	spaceReq           requirements.SpaceRequirement
+	quotaRepo          space_quotas.SpaceQuotaRepository
+	appSummaryRepo     api.AppSummaryRepository
+	serviceSummaryRepo api.ServiceSummaryRepository
+}
+
+func init() {
+	command_registry.Register(&SpaceUsage{})
+}
+
+func (cmd *SpaceUsage) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["warn-at"] = &cliFlags.IntFlag{Name: "warn-at", Usage: T("Warn about limits used at or above this percentage (Default: 80)")}
+
+	return command_registry.CommandMetadata{
+		Name:        "space-usage",
+		Description: T("Show how much of its space quota a space uses"),
+		Usage: T(`CF_NAME space-usage SPACE [--warn-at PERCENT]
+
+   Memory is the memory of the running app instances and instance memory the memory of the
+   largest app instance. User-provided service instances do not count against the quota.`),
+		Flags: fs,
+	}
+}
+
+func (cmd *SpaceUsage) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	if len(fc.Args()) != 1 {
+		cmd.ui.Failed(T("Incorrect Usage. Requires an argument\n\n") + command_registry.Commands.CommandUsage("space-usage"))
+	}
+
+	if fc.IsSet("warn-at") && (fc.Int("warn-at") < 1 || fc.Int("warn-at") > 100) {
+		cmd.ui.Failed(T("Incorrect Usage. --warn-at must be between 1 and 100\n\n") + command_registry.Commands.CommandUsage("space-usage"))
+	}
+
+	cmd.spaceReq = requirementsFactory.NewSpaceRequirement(fc.Args()[0])
+	reqs = []requirements.Requirement{
+		requirementsFactory.NewLoginRequirement(),
+		requirementsFactory.NewTargetedOrgRequirement(),
+		cmd.spaceReq,
+	}
+	return
+}
+
+func (cmd *SpaceUsage) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.config = deps.Config
+	cmd.quotaRepo = deps.RepoLocator.GetSpaceQuotaRepository()
+	cmd.appSummaryRepo = deps.RepoLocator.GetAppSummaryRepository()
+	cmd.serviceSummaryRepo = deps.RepoLocator.GetServiceSummaryRepository()
+	return cmd
+}
+
+func (cmd *SpaceUsage) Execute(c flags.FlagContext) {
+	space := cmd.spaceReq.GetSpace()
+
+	warnAt := ui_helpers.DefaultQuotaWarnAt
+	if c.IsSet("warn-at") {
+		warnAt = c.Int("warn-at")
+	}
+
+	cmd.ui.Say(T("Getting quota usage of space {{.SpaceName}} in org {{.OrgName}} as {{.Username}}...",
+		map[string]interface{}{
+			"SpaceName": terminal.EntityNameColor(space.Name),
+			"OrgName":   terminal.EntityNameColor(cmd.config.OrganizationFields().Name),
+			"Username":  terminal.EntityNameColor(cmd.config.Username())}))
+
+	apps, err := cmd.appSummaryRepo.GetSummariesInSpace(space.Guid)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	serviceInstances, err := cmd.serviceSummaryRepo.GetSummariesInSpace(space.Guid)
+	if err != nil {
+		cmd.ui.Failed(err.Error())
+	}
+
+	var quota models.SpaceQuota
+	if space.SpaceQuotaGuid != "" {
+		quota, err = cmd.quotaRepo.FindByGuid(space.SpaceQuotaGuid)
+		if err != nil {
+			cmd.ui.Failed(err.Error())
+		}
+	}
+
+	usage := models.NewQuotaUsage(apps, serviceInstances)
+
+	cmd.ui.Ok()
+	cmd.ui.Say("")
+
+	if space.SpaceQuotaGuid == "" {
+		table := terminal.NewTable(cmd.ui, []string{T("resource"), T("used")})
+		table.Add(T("memory"), formatters.ByteSize(usage.Memory*formatters.MEGABYTE))
+		table.Add(T("instance memory"), formatters.ByteSize(usage.InstanceMemory*formatters.MEGABYTE))
+		table.Add(T("routes"), fmt.Sprintf("%d", usage.Routes))
+		table.Add(T("service instances"), fmt.Sprintf("%d", usage.ServiceInstances))
+		table.Print()
+
+		cmd.ui.Say("")
+		cmd.ui.Say(T("Space {{.SpaceName}} has no space quota, only the quota of its org applies. Use '{{.Command}}' to compare it with the org quota.",
+			map[string]interface{}{
+				"SpaceName": space.Name,
+				"Command":   terminal.CommandColor(cf.Name() + " org-usage " + cmd.config.OrganizationFields().Name)}))
+		return
+	}
+
+	cmd.ui.Say(T("space quota: {{.QuotaName}}", map[string]interface{}{"QuotaName": terminal.EntityNameColor(quota.Name)}))
+	ui_helpers.PrintQuotaUsage(cmd.ui, usage, models.QuotaFields{
+		Name:                quota.Name,
+		MemoryLimit:         quota.MemoryLimit,
+		InstanceMemoryLimit: quota.InstanceMemoryLimit,
+		RoutesLimit:         quota.RoutesLimit,
+		ServicesLimit:       quota.ServicesLimit,
+	}, warnAt, T("space {{.SpaceName}}", map[string]interface{}{"SpaceName": space.Name}))
+}
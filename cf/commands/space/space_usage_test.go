@@ -0,0 +1,149 @@
+package space_test
+
+import (
+	testapi "github.com/cloudfoundry/cli/cf/api/fakes"
+	"github.com/cloudfoundry/cli/cf/api/space_quotas/fakes"
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/configuration/core_config"
+	"github.com/cloudfoundry/cli/cf/errors"
+	"github.com/cloudfoundry/cli/cf/models"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testconfig "github.com/cloudfoundry/cli/testhelpers/configuration"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+)
+
+var _ = Describe("space-usage command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		requirementsFactory *testreq.FakeReqFactory
+		quotaRepo           *fakes.FakeSpaceQuotaRepository
+		appSummaryRepo      *testapi.FakeAppSummaryRepo
+		serviceSummaryRepo  *testapi.FakeServiceSummaryRepo
+		configRepo          core_config.Repository
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.Config = configRepo
+		deps.RepoLocator = deps.RepoLocator.SetSpaceQuotaRepository(quotaRepo)
+		deps.RepoLocator = deps.RepoLocator.SetAppSummaryRepository(appSummaryRepo)
+		deps.RepoLocator = deps.RepoLocator.SetServiceSummaryRepository(serviceSummaryRepo)
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("space-usage").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		configRepo = testconfig.NewRepositoryWithDefaults()
+		quotaRepo = &fakes.FakeSpaceQuotaRepository{}
+		ui = &testterm.FakeUI{}
+
+		space := models.Space{}
+		space.Name = "my-space"
+		space.Guid = "my-space-guid"
+		space.SpaceQuotaGuid = "my-space-quota-guid"
+		requirementsFactory = &testreq.FakeReqFactory{LoginSuccess: true, TargetedOrgSuccess: true, Space: space}
+
+		app := models.Application{}
+		app.Memory = 256
+		app.RunningInstances = 3
+		app.Routes = []models.RouteSummary{{Guid: "route-1-guid"}, {Guid: "route-2-guid"}}
+		appSummaryRepo = &testapi.FakeAppSummaryRepo{
+			GetSummariesInSpaceApps: map[string][]models.Application{"my-space-guid": {app}},
+		}
+
+		serviceInstance := models.ServiceInstance{}
+		serviceInstance.ServicePlan.Guid = "plan-guid"
+		serviceSummaryRepo = &testapi.FakeServiceSummaryRepo{
+			GetSummariesInSpaceInstances: map[string][]models.ServiceInstance{"my-space-guid": {serviceInstance}},
+		}
+
+		quotaRepo.FindByGuidReturns(models.SpaceQuota{
+			Name:                "small",
+			MemoryLimit:         1024,
+			InstanceMemoryLimit: -1,
+			RoutesLimit:         10,
+			ServicesLimit:       1,
+		}, nil)
+
+		deps = command_registry.NewDependency()
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("space-usage", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	Describe("requirements", func() {
+		It("fails when not logged in", func() {
+			requirementsFactory.LoginSuccess = false
+			Expect(runCommand("my-space")).To(BeFalse())
+		})
+
+		It("fails when an org is not targeted", func() {
+			requirementsFactory.TargetedOrgSuccess = false
+			Expect(runCommand("my-space")).To(BeFalse())
+		})
+
+		It("fails with usage when --warn-at is out of range", func() {
+			runCommand("--warn-at", "120", "my-space")
+			Expect(ui.Outputs).To(ContainSubstrings(
+				[]string{"Incorrect Usage", "--warn-at must be between 1 and 100"},
+			))
+		})
+	})
+
+	It("compares the usage with the space quota and warns about limits above the threshold", func() {
+		runCommand("my-space")
+
+		Expect(quotaRepo.FindByGuidArgsForCall(0)).To(Equal("my-space-quota-guid"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Getting quota usage of space", "my-space", "my-org", "my-user"},
+			[]string{"OK"},
+			[]string{"space quota:", "small"},
+			[]string{"resource", "used", "limit", "percent used"},
+			[]string{"memory", "768M", "1G", "75%"},
+			[]string{"instance memory", "256M", "unlimited"},
+			[]string{"routes", "2", "10", "20%"},
+			[]string{"service instances", "1", "1", "100%"},
+			[]string{"service instances usage of space my-space is at 100% of its quota"},
+		))
+		Expect(ui.Outputs).NotTo(ContainSubstrings([]string{"memory usage of space"}))
+	})
+
+	It("uses the given warning threshold", func() {
+		runCommand("--warn-at", "50", "my-space")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"memory usage of space my-space is at 75% of its quota"},
+		))
+	})
+
+	It("only shows the usage when the space has no space quota", func() {
+		space := requirementsFactory.Space
+		space.SpaceQuotaGuid = ""
+		requirementsFactory.Space = space
+
+		runCommand("my-space")
+
+		Expect(quotaRepo.FindByGuidCallCount()).To(Equal(0))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"memory", "768M"},
+			[]string{"Space my-space has no space quota", "org-usage my-org"},
+		))
+	})
+
+	It("fails when the app summaries cannot be fetched", func() {
+		appSummaryRepo.GetSummariesInSpaceErr = errors.New("summary failed")
+
+		runCommand("my-space")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"summary failed"},
+		))
+	})
+})
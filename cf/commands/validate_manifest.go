@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/simonleung8/flags"
+	"github.com/simonleung8/flags/flag"
+)
+
+type ValidateManifest struct {
+	ui           terminal.UI
+	manifestRepo manifest.ManifestRepository
+}
+
+func init() {
+	command_registry.Register(&ValidateManifest{})
+}
+
+func (cmd *ValidateManifest) MetaData() command_registry.CommandMetadata {
+	fs := make(map[string]flags.FlagSet)
+	fs["f"] = &cliFlags.StringFlag{Name: "f", Usage: T("Path to manifest")}
+
+	return command_registry.CommandMetadata{
+		Name:        "validate-manifest",
+		Description: T("Check a manifest for unknown keys and invalid values"),
+		Usage:       T("CF_NAME validate-manifest [-f MANIFEST_PATH]"),
+		Flags:       fs,
+	}
+}
+
+func (cmd *ValidateManifest) Requirements(requirementsFactory requirements.Factory, fc flags.FlagContext) ([]requirements.Requirement, error) {
+	if len(fc.Args()) != 0 {
+		cmd.ui.Failed(T("Incorrect Usage. No argument required\n\n") + command_registry.Commands.CommandUsage("validate-manifest"))
+	}
+
+	return nil, nil
+}
+
+func (cmd *ValidateManifest) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	cmd.ui = deps.Ui
+	cmd.manifestRepo = deps.ManifestRepo
+	return cmd
+}
+
+func (cmd *ValidateManifest) Execute(c flags.FlagContext) {
+	path := c.String("f")
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			cmd.ui.Failed(T("Could not determine the current working directory!"), err)
+		}
+	}
+
+	m, errs := cmd.manifestRepo.ValidateManifest(path)
+
+	if m.Path != "" {
+		cmd.ui.Say(T("Validating manifest file {{.Path}}...", map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+	}
+
+	if len(errs) > 0 {
+		message := T("Manifest is invalid:")
+		for _, err := range errs {
+			message += "\n" + err.Error()
+		}
+		cmd.ui.Failed(message)
+	}
+
+	cmd.ui.Ok()
+	cmd.ui.Say(T("Manifest file {{.Path}} is valid", map[string]interface{}{"Path": terminal.EntityNameColor(m.Path)}))
+}
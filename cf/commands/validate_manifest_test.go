@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"os"
+
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/manifest"
+	testcmd "github.com/cloudfoundry/cli/testhelpers/commands"
+	testmanifest "github.com/cloudfoundry/cli/testhelpers/manifest"
+	testreq "github.com/cloudfoundry/cli/testhelpers/requirements"
+	testterm "github.com/cloudfoundry/cli/testhelpers/terminal"
+
+	. "github.com/cloudfoundry/cli/testhelpers/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validate-manifest command", func() {
+	var (
+		ui                  *testterm.FakeUI
+		manifestRepo        *testmanifest.FakeManifestRepository
+		requirementsFactory *testreq.FakeReqFactory
+		deps                command_registry.Dependency
+	)
+
+	updateCommandDependency := func(pluginCall bool) {
+		deps.Ui = ui
+		deps.ManifestRepo = manifestRepo
+		command_registry.Commands.SetCommand(command_registry.Commands.FindCommand("validate-manifest").SetDependency(deps, pluginCall))
+	}
+
+	BeforeEach(func() {
+		ui = &testterm.FakeUI{}
+		manifestRepo = &testmanifest.FakeManifestRepository{}
+		requirementsFactory = &testreq.FakeReqFactory{}
+	})
+
+	runCommand := func(args ...string) bool {
+		return testcmd.RunCliCommand("validate-manifest", args, requirementsFactory, updateCommandDependency, false)
+	}
+
+	It("fails with usage when given an argument", func() {
+		runCommand("my-app")
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Incorrect Usage", "No argument required"},
+		))
+	})
+
+	It("validates the manifest in the current directory by default", func() {
+		runCommand()
+
+		cwd, _ := os.Getwd()
+		Expect(manifestRepo.ValidateManifestArgs.Path).To(Equal(cwd))
+	})
+
+	It("reports that a valid manifest is valid", func() {
+		manifestRepo.ValidateManifestReturns.Manifest = &manifest.Manifest{Path: "path/to/manifest.yml"}
+
+		runCommand("-f", "path/to/manifest.yml")
+
+		Expect(manifestRepo.ValidateManifestArgs.Path).To(Equal("path/to/manifest.yml"))
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"Validating manifest file", "path/to/manifest.yml"},
+			[]string{"OK"},
+			[]string{"Manifest file", "path/to/manifest.yml", "is valid"},
+		))
+	})
+
+	It("lists every problem found in an invalid manifest", func() {
+		manifestRepo.ValidateManifestReturns.Manifest = &manifest.Manifest{Path: "manifest.yml"}
+		manifestRepo.ValidateManifestReturns.Errors = []error{
+			manifest.NewValidationError("manifest.yml", 2, "Unknown manifest key 'memroy', did you mean 'memory'?"),
+			manifest.NewValidationError("manifest.yml", 7, "Expected instances to be a number, but it was 'lots'."),
+		}
+
+		runCommand("-f", "manifest.yml")
+
+		Expect(ui.Outputs).To(ContainSubstrings(
+			[]string{"FAILED"},
+			[]string{"Manifest is invalid"},
+			[]string{"manifest.yml:2: Unknown manifest key 'memroy', did you mean 'memory'?"},
+			[]string{"manifest.yml:7: Expected instances to be a number, but it was 'lots'."},
+		))
+		Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"is valid"}))
+	})
+})
@@ -134,6 +134,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("restart"),
 					presentNonCodegangstaCommand("restage"),
 					presentNonCodegangstaCommand("restart-app-instance"),
+					presentNonCodegangstaCommand("wait"),
 				}, {
 					presentNonCodegangstaCommand("events"),
 					presentNonCodegangstaCommand("files"),
@@ -142,13 +143,19 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("env"),
 					presentNonCodegangstaCommand("set-env"),
 					presentNonCodegangstaCommand("unset-env"),
+					presentNonCodegangstaCommand("env-diff"),
 				}, {
 					presentNonCodegangstaCommand("stacks"),
 					presentNonCodegangstaCommand("stack"),
 				}, {
 					presentNonCodegangstaCommand("copy-source"),
+					presentNonCodegangstaCommand("download-droplet"),
+					presentNonCodegangstaCommand("download-app-bits"),
+					presentNonCodegangstaCommand("promote"),
 				}, {
 					presentNonCodegangstaCommand("create-app-manifest"),
+					presentNonCodegangstaCommand("validate-manifest"),
+					presentNonCodegangstaCommand("manifest-diff"),
 				}, {
 					presentNonCodegangstaCommand("get-health-check"),
 					presentNonCodegangstaCommand("set-health-check"),
@@ -165,6 +172,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("marketplace"),
 					presentNonCodegangstaCommand("services"),
 					presentNonCodegangstaCommand("service"),
+					presentNonCodegangstaCommand("service-operations"),
 				}, {
 					presentNonCodegangstaCommand("create-service"),
 					presentNonCodegangstaCommand("update-service"),
@@ -178,6 +186,7 @@ func newAppPresenter() (presenter appPresenter) {
 				}, {
 					presentNonCodegangstaCommand("bind-service"),
 					presentNonCodegangstaCommand("unbind-service"),
+					presentNonCodegangstaCommand("rebind-service"),
 				}, {
 					presentNonCodegangstaCommand("create-user-provided-service"),
 					presentNonCodegangstaCommand("update-user-provided-service"),
@@ -189,6 +198,7 @@ func newAppPresenter() (presenter appPresenter) {
 				{
 					presentNonCodegangstaCommand("orgs"),
 					presentNonCodegangstaCommand("org"),
+					presentNonCodegangstaCommand("org-usage"),
 				}, {
 					presentNonCodegangstaCommand("create-org"),
 					presentNonCodegangstaCommand("delete-org"),
@@ -201,10 +211,14 @@ func newAppPresenter() (presenter appPresenter) {
 				{
 					presentNonCodegangstaCommand("spaces"),
 					presentNonCodegangstaCommand("space"),
+					presentNonCodegangstaCommand("space-usage"),
 				}, {
 					presentNonCodegangstaCommand("create-space"),
 					presentNonCodegangstaCommand("delete-space"),
 					presentNonCodegangstaCommand("rename-space"),
+				}, {
+					presentNonCodegangstaCommand("export-space"),
+					presentNonCodegangstaCommand("import-space"),
 				}, {
 					presentNonCodegangstaCommand("allow-space-ssh"),
 					presentNonCodegangstaCommand("disallow-space-ssh"),
@@ -244,6 +258,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("update-buildpack"),
 					presentNonCodegangstaCommand("rename-buildpack"),
 					presentNonCodegangstaCommand("delete-buildpack"),
+					presentNonCodegangstaCommand("sync-buildpacks"),
 				},
 			},
 		}, {
@@ -323,6 +338,9 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("security-groups"),
 					presentNonCodegangstaCommand("create-security-group"),
 					presentNonCodegangstaCommand("update-security-group"),
+					presentNonCodegangstaCommand("security-group-diff"),
+					presentNonCodegangstaCommand("security-group-check"),
+					presentNonCodegangstaCommand("space-security-report"),
 					presentNonCodegangstaCommand("delete-security-group"),
 					presentNonCodegangstaCommand("bind-security-group"),
 					presentNonCodegangstaCommand("unbind-security-group"),
@@ -344,6 +362,10 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("staging-environment-variable-group"),
 					presentNonCodegangstaCommand("set-staging-environment-variable-group"),
 					presentNonCodegangstaCommand("set-running-environment-variable-group"),
+					presentNonCodegangstaCommand("set-running-env-var"),
+					presentNonCodegangstaCommand("unset-running-env-var"),
+					presentNonCodegangstaCommand("set-staging-env-var"),
+					presentNonCodegangstaCommand("unset-staging-env-var"),
 				},
 			},
 		},
@@ -355,6 +377,7 @@ func newAppPresenter() (presenter appPresenter) {
 					presentNonCodegangstaCommand("feature-flag"),
 					presentNonCodegangstaCommand("enable-feature-flag"),
 					presentNonCodegangstaCommand("disable-feature-flag"),
+					presentNonCodegangstaCommand("apply-feature-flags"),
 				},
 			},
 		}, {
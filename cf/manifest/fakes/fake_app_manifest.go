@@ -15,6 +15,12 @@ type FakeAppManifest struct {
 		arg1 string
 		arg2 string
 	}
+	DiskQuotaStub        func(string, int64)
+	diskQuotaMutex       sync.RWMutex
+	diskQuotaArgsForCall []struct {
+		arg1 string
+		arg2 int64
+	}
 	MemoryStub        func(string, int64)
 	memoryMutex       sync.RWMutex
 	memoryArgsForCall []struct {
@@ -46,12 +52,30 @@ type FakeAppManifest struct {
 		arg1 string
 		arg2 int
 	}
+	HealthCheckTypeStub        func(string, string)
+	healthCheckTypeMutex       sync.RWMutex
+	healthCheckTypeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
 	InstancesStub        func(string, int)
 	instancesMutex       sync.RWMutex
 	instancesArgsForCall []struct {
 		arg1 string
 		arg2 int
 	}
+	StackStub        func(string, string)
+	stackMutex       sync.RWMutex
+	stackArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	DockerImageStub        func(string, string)
+	dockerImageMutex       sync.RWMutex
+	dockerImageArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
 	DomainStub        func(string, string, string)
 	domainMutex       sync.RWMutex
 	domainArgsForCall []struct {
@@ -102,6 +126,30 @@ func (fake *FakeAppManifest) BuildpackUrlArgsForCall(i int) (string, string) {
 	return fake.buildpackUrlArgsForCall[i].arg1, fake.buildpackUrlArgsForCall[i].arg2
 }
 
+func (fake *FakeAppManifest) DiskQuota(arg1 string, arg2 int64) {
+	fake.diskQuotaMutex.Lock()
+	fake.diskQuotaArgsForCall = append(fake.diskQuotaArgsForCall, struct {
+		arg1 string
+		arg2 int64
+	}{arg1, arg2})
+	fake.diskQuotaMutex.Unlock()
+	if fake.DiskQuotaStub != nil {
+		fake.DiskQuotaStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) DiskQuotaCallCount() int {
+	fake.diskQuotaMutex.RLock()
+	defer fake.diskQuotaMutex.RUnlock()
+	return len(fake.diskQuotaArgsForCall)
+}
+
+func (fake *FakeAppManifest) DiskQuotaArgsForCall(i int) (string, int64) {
+	fake.diskQuotaMutex.RLock()
+	defer fake.diskQuotaMutex.RUnlock()
+	return fake.diskQuotaArgsForCall[i].arg1, fake.diskQuotaArgsForCall[i].arg2
+}
+
 func (fake *FakeAppManifest) Memory(arg1 string, arg2 int64) {
 	fake.memoryMutex.Lock()
 	fake.memoryArgsForCall = append(fake.memoryArgsForCall, struct {
@@ -223,6 +271,30 @@ func (fake *FakeAppManifest) HealthCheckTimeoutArgsForCall(i int) (string, int)
 	return fake.healthCheckTimeoutArgsForCall[i].arg1, fake.healthCheckTimeoutArgsForCall[i].arg2
 }
 
+func (fake *FakeAppManifest) HealthCheckType(arg1 string, arg2 string) {
+	fake.healthCheckTypeMutex.Lock()
+	fake.healthCheckTypeArgsForCall = append(fake.healthCheckTypeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.healthCheckTypeMutex.Unlock()
+	if fake.HealthCheckTypeStub != nil {
+		fake.HealthCheckTypeStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) HealthCheckTypeCallCount() int {
+	fake.healthCheckTypeMutex.RLock()
+	defer fake.healthCheckTypeMutex.RUnlock()
+	return len(fake.healthCheckTypeArgsForCall)
+}
+
+func (fake *FakeAppManifest) HealthCheckTypeArgsForCall(i int) (string, string) {
+	fake.healthCheckTypeMutex.RLock()
+	defer fake.healthCheckTypeMutex.RUnlock()
+	return fake.healthCheckTypeArgsForCall[i].arg1, fake.healthCheckTypeArgsForCall[i].arg2
+}
+
 func (fake *FakeAppManifest) Instances(arg1 string, arg2 int) {
 	fake.instancesMutex.Lock()
 	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
@@ -247,6 +319,54 @@ func (fake *FakeAppManifest) InstancesArgsForCall(i int) (string, int) {
 	return fake.instancesArgsForCall[i].arg1, fake.instancesArgsForCall[i].arg2
 }
 
+func (fake *FakeAppManifest) Stack(arg1 string, arg2 string) {
+	fake.stackMutex.Lock()
+	fake.stackArgsForCall = append(fake.stackArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.stackMutex.Unlock()
+	if fake.StackStub != nil {
+		fake.StackStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) StackCallCount() int {
+	fake.stackMutex.RLock()
+	defer fake.stackMutex.RUnlock()
+	return len(fake.stackArgsForCall)
+}
+
+func (fake *FakeAppManifest) StackArgsForCall(i int) (string, string) {
+	fake.stackMutex.RLock()
+	defer fake.stackMutex.RUnlock()
+	return fake.stackArgsForCall[i].arg1, fake.stackArgsForCall[i].arg2
+}
+
+func (fake *FakeAppManifest) DockerImage(arg1 string, arg2 string) {
+	fake.dockerImageMutex.Lock()
+	fake.dockerImageArgsForCall = append(fake.dockerImageArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.dockerImageMutex.Unlock()
+	if fake.DockerImageStub != nil {
+		fake.DockerImageStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeAppManifest) DockerImageCallCount() int {
+	fake.dockerImageMutex.RLock()
+	defer fake.dockerImageMutex.RUnlock()
+	return len(fake.dockerImageArgsForCall)
+}
+
+func (fake *FakeAppManifest) DockerImageArgsForCall(i int) (string, string) {
+	fake.dockerImageMutex.RLock()
+	defer fake.dockerImageMutex.RUnlock()
+	return fake.dockerImageArgsForCall[i].arg1, fake.dockerImageArgsForCall[i].arg2
+}
+
 func (fake *FakeAppManifest) Domain(arg1 string, arg2 string, arg3 string) {
 	fake.domainMutex.Lock()
 	fake.domainArgsForCall = append(fake.domainArgsForCall, struct {
@@ -3,18 +3,23 @@ package manifest
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/cloudfoundry/cli/cf/models"
 )
 
 type AppManifest interface {
 	BuildpackUrl(string, string)
+	DiskQuota(string, int64)
 	Memory(string, int64)
 	Service(string, string)
 	StartCommand(string, string)
 	EnvironmentVars(string, string, string)
 	HealthCheckTimeout(string, int)
+	HealthCheckType(string, string)
 	Instances(string, int)
+	Stack(string, string)
+	DockerImage(string, string)
 	Domain(string, string, string)
 	GetContents() []models.Application
 	FileSavePath(string)
@@ -26,6 +31,11 @@ type appManifest struct {
 	contents []models.Application
 }
 
+type manifestProperty struct {
+	key   string
+	value string
+}
+
 func NewGenerator() AppManifest {
 	return &appManifest{}
 }
@@ -39,6 +49,11 @@ func (m *appManifest) Memory(appName string, memory int64) {
 	m.contents[i].Memory = memory
 }
 
+func (m *appManifest) DiskQuota(appName string, diskQuota int64) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].DiskQuota = diskQuota
+}
+
 func (m *appManifest) StartCommand(appName string, cmd string) {
 	i := m.findOrCreateApplication(appName)
 	m.contents[i].Command = cmd
@@ -54,6 +69,23 @@ func (m *appManifest) HealthCheckTimeout(appName string, timeout int) {
 	m.contents[i].HealthCheckTimeout = timeout
 }
 
+func (m *appManifest) HealthCheckType(appName string, healthCheckType string) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].HealthCheckType = healthCheckType
+}
+
+func (m *appManifest) Stack(appName string, stackName string) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].Stack = &models.Stack{
+		Name: stackName,
+	}
+}
+
+func (m *appManifest) DockerImage(appName string, image string) {
+	i := m.findOrCreateApplication(appName)
+	m.contents[i].DockerImage = image
+}
+
 func (m *appManifest) Instances(appName string, instances int) {
 	i := m.findOrCreateApplication(appName)
 	m.contents[i].InstanceCount = instances
@@ -93,54 +125,46 @@ func (m *appManifest) Save() error {
 	}
 	defer f.Close()
 
-	_, err = fmt.Fprintln(f, "---\napplications:")
+	sharedProperties := m.sharedProperties()
 
-	for _, app := range m.contents {
-		if _, err := fmt.Fprintf(f, "- name: %s\n", app.Name); err != nil {
-			return err
-		}
+	if _, err := fmt.Fprintln(f, "---"); err != nil {
+		return err
+	}
 
-		if _, err := fmt.Fprintf(f, "  memory: %dM\n", app.Memory); err != nil {
+	for _, property := range sharedProperties {
+		if _, err := fmt.Fprintf(f, "%s: %s\n", property.key, property.value); err != nil {
 			return err
 		}
+	}
 
-		if _, err := fmt.Fprintf(f, "  instances: %d\n", app.InstanceCount); err != nil {
+	if _, err := fmt.Fprintln(f, "applications:"); err != nil {
+		return err
+	}
+
+	for _, app := range m.contents {
+		if _, err := fmt.Fprintf(f, "- name: %s\n", app.Name); err != nil {
 			return err
 		}
 
-		if app.BuildpackUrl != "" {
-			if _, err := fmt.Fprintf(f, "  buildpack: %s\n", app.BuildpackUrl); err != nil {
-				return err
+		for _, property := range appProperties(app) {
+			if containsProperty(sharedProperties, property) {
+				continue
 			}
-		}
 
-		if app.HealthCheckTimeout > 0 {
-			if _, err := fmt.Fprintf(f, "  timeout: %d\n", app.HealthCheckTimeout); err != nil {
+			if _, err := fmt.Fprintf(f, "  %s: %s\n", property.key, property.value); err != nil {
 				return err
 			}
 		}
 
-		if app.Command != "" {
-			if _, err := fmt.Fprintf(f, "  command: %s\n", app.Command); err != nil {
+		if len(app.Routes) == 1 {
+			if _, err := fmt.Fprintf(f, "  host: %s\n", app.Routes[0].Host); err != nil {
 				return err
 			}
-		}
-
-		if len(app.Routes) > 0 {
-			if len(app.Routes) == 1 {
-				if _, err := fmt.Fprintf(f, "  host: %s\n", app.Routes[0].Host); err != nil {
-					return err
-				}
-				if _, err := fmt.Fprintf(f, "  domain: %s\n", app.Routes[0].Domain.Name); err != nil {
-					return err
-				}
-			} else {
-				if err := writeRoutesToFile(f, app.Routes); err != nil {
-					return err
-				}
+			if _, err := fmt.Fprintf(f, "  domain: %s\n", app.Routes[0].Domain.Name); err != nil {
+				return err
 			}
-		} else {
-			if _, err := fmt.Fprintf(f, "  no-route: true\n"); err != nil {
+		} else if len(app.Routes) > 1 {
+			if err := writeRoutesToFile(f, app.Routes); err != nil {
 				return err
 			}
 		}
@@ -161,6 +185,81 @@ func (m *appManifest) Save() error {
 	return nil
 }
 
+// sharedProperties returns the properties that have the same value for every
+// app, so they can be written once in the global section of the manifest.
+func (m *appManifest) sharedProperties() []manifestProperty {
+	if len(m.contents) < 2 {
+		return nil
+	}
+
+	shared := []manifestProperty{}
+	for _, property := range appProperties(m.contents[0]) {
+		isShared := true
+		for _, app := range m.contents[1:] {
+			if !containsProperty(appProperties(app), property) {
+				isShared = false
+				break
+			}
+		}
+
+		if isShared {
+			shared = append(shared, property)
+		}
+	}
+
+	return shared
+}
+
+func appProperties(app models.Application) []manifestProperty {
+	properties := []manifestProperty{
+		{key: "memory", value: fmt.Sprintf("%dM", app.Memory)},
+		{key: "instances", value: fmt.Sprintf("%d", app.InstanceCount)},
+	}
+
+	if app.DiskQuota > 0 {
+		properties = append(properties, manifestProperty{key: "disk_quota", value: fmt.Sprintf("%dM", app.DiskQuota)})
+	}
+
+	if app.BuildpackUrl != "" {
+		properties = append(properties, manifestProperty{key: "buildpack", value: app.BuildpackUrl})
+	}
+
+	if app.Stack != nil && app.Stack.Name != "" {
+		properties = append(properties, manifestProperty{key: "stack", value: app.Stack.Name})
+	}
+
+	if app.HealthCheckType != "" {
+		properties = append(properties, manifestProperty{key: "health-check-type", value: app.HealthCheckType})
+	}
+
+	if app.HealthCheckTimeout > 0 {
+		properties = append(properties, manifestProperty{key: "timeout", value: fmt.Sprintf("%d", app.HealthCheckTimeout)})
+	}
+
+	if app.Command != "" {
+		properties = append(properties, manifestProperty{key: "command", value: app.Command})
+	}
+
+	if app.DockerImage != "" {
+		properties = append(properties, manifestProperty{key: "docker-image", value: app.DockerImage})
+	}
+
+	if len(app.Routes) == 0 {
+		properties = append(properties, manifestProperty{key: "no-route", value: "true"})
+	}
+
+	return properties
+}
+
+func containsProperty(properties []manifestProperty, property manifestProperty) bool {
+	for _, p := range properties {
+		if p == property {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *appManifest) findOrCreateApplication(name string) int {
 	for i, app := range m.contents {
 		if app.Name == name {
@@ -256,8 +355,14 @@ func writeEnvironmentVarToFile(f *os.File, envVars map[string]interface{}) error
 	if err != nil {
 		return err
 	}
-	for k, v := range envVars {
-		_, err = fmt.Fprintf(f, "    %s: %s\n", k, v)
+	keys := []string{}
+	for k, _ := range envVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		_, err = fmt.Fprintf(f, "    %s: %s\n", k, envVars[k])
 		if err != nil {
 			return err
 		}
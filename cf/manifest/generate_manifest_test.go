@@ -112,6 +112,87 @@ var _ = Describe("generate_manifest", func() {
 			[]string{"  buildpack: ruby-buildpack"},
 		))
 	})
+	It("includes disk quota, stack, health check type and docker image", func() {
+		m.Memory("app1", 128)
+		m.DiskQuota("app1", 1024)
+		m.Stack("app1", "cflinuxfs2")
+		m.HealthCheckType("app1", "none")
+		m.DockerImage("app1", "user/my-image")
+		err := m.Save()
+		Ω(err).NotTo(HaveOccurred())
+
+		Ω(getYamlContent("./output.yml")).To(ContainSubstrings(
+			[]string{"- name: app1"},
+			[]string{"  disk_quota: 1024M"},
+			[]string{"  stack: cflinuxfs2"},
+			[]string{"  health-check-type: none"},
+			[]string{"  docker-image: user/my-image"},
+			[]string{"  no-route: true"},
+		))
+	})
+
+	It("writes environment variables in alphabetical order", func() {
+		m.EnvironmentVars("app1", "b", "2")
+		m.EnvironmentVars("app1", "c", "3")
+		m.EnvironmentVars("app1", "a", "1")
+		m.Save()
+
+		cmdOutput := &outputs{
+			contents: getYamlContent("./output.yml"),
+			cursor:   0,
+		}
+
+		Ω(cmdOutput.ContainsSubstring("    a: 1")).To(BeTrue())
+		Ω(cmdOutput.ContainsSubstring("    b: 2")).To(BeTrue())
+		Ω(cmdOutput.ContainsSubstring("    c: 3")).To(BeTrue())
+	})
+
+	Context("When there are multiple apps", func() {
+		It("hoists properties shared by all apps into the global section", func() {
+			m.Memory("app1", 256)
+			m.Instances("app1", 2)
+			m.Stack("app1", "cflinuxfs2")
+			m.Domain("app1", "foo", "example.com")
+			m.Memory("app2", 256)
+			m.Instances("app2", 1)
+			m.Stack("app2", "cflinuxfs2")
+			err := m.Save()
+			Ω(err).NotTo(HaveOccurred())
+
+			contents := getYamlContent("./output.yml")
+			Ω(contents[0]).To(Equal("---"))
+			Ω(contents[1:4]).To(ConsistOf("memory: 256M", "stack: cflinuxfs2", "applications:"))
+
+			Ω(contents).ToNot(ContainSubstrings([]string{"  memory: 256M"}))
+			Ω(contents).ToNot(ContainSubstrings([]string{"  stack: cflinuxfs2"}))
+
+			cmdOutput := &outputs{
+				contents: contents,
+				cursor:   0,
+			}
+
+			Ω(cmdOutput.ContainsSubstring("- name: app1")).To(BeTrue())
+			Ω(cmdOutput.ContainsSubstring("  instances: 2")).To(BeTrue())
+			Ω(cmdOutput.ContainsSubstring("  host: foo")).To(BeTrue())
+			Ω(cmdOutput.ContainsSubstring("- name: app2")).To(BeTrue())
+			Ω(cmdOutput.ContainsSubstring("  instances: 1")).To(BeTrue())
+			Ω(cmdOutput.ContainsSubstring("  no-route: true")).To(BeTrue())
+		})
+
+		It("does not hoist anything when the apps have nothing in common", func() {
+			m.Memory("app1", 128)
+			m.Instances("app1", 1)
+			m.Domain("app1", "foo", "example.com")
+			m.Memory("app2", 64)
+			m.Instances("app2", 2)
+			m.Save()
+
+			contents := getYamlContent("./output.yml")
+			Ω(contents[0]).To(Equal("---"))
+			Ω(contents[1]).To(Equal("applications:"))
+		})
+	})
+
 	Context("When there are multiple hosts and domains", func() {
 
 		It("generates a manifest containing two hosts two domains", func() {
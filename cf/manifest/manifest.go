@@ -9,6 +9,7 @@ import (
 
 	. "github.com/cloudfoundry/cli/cf/i18n"
 
+	"github.com/cloudfoundry/cli/cf/app_files"
 	"github.com/cloudfoundry/cli/cf/errors"
 	"github.com/cloudfoundry/cli/cf/formatters"
 	"github.com/cloudfoundry/cli/cf/models"
@@ -161,12 +162,13 @@ func mapToAppParams(basePath string, yamlMap generic.Map) (appParams models.AppP
 	appParams.ServicesToBind = sliceOrEmptyVal(yamlMap, "services", &errs)
 	appParams.EnvironmentVars = envVarOrEmptyMap(yamlMap, &errs)
 	appParams.HealthCheckType = stringVal(yamlMap, "health-check-type", &errs)
+	appParams.DockerImage = stringVal(yamlMap, "docker-image", &errs)
 
 	if appParams.Path != nil {
 		path := *appParams.Path
 		if filepath.IsAbs(path) {
 			path = filepath.Clean(path)
-		} else {
+		} else if !app_files.IsGitSource(path) {
 			path = filepath.Join(basePath, path)
 		}
 		appParams.Path = &path
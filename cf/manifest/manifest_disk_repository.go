@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 type ManifestRepository interface {
 	ReadManifest(string) (*Manifest, error)
+	ValidateManifest(string) (*Manifest, []error)
 }
 
 type ManifestDiskRepository struct{}
@@ -32,7 +34,7 @@ func (repo ManifestDiskRepository) ReadManifest(inputPath string) (*Manifest, er
 
 	m.Path = manifestPath
 
-	mapp, err := repo.readAllYAMLFiles(manifestPath)
+	mapp, err := repo.readAllYAMLFiles(manifestPath, nil)
 	if err != nil {
 		return m, err
 	}
@@ -42,18 +44,58 @@ func (repo ManifestDiskRepository) ReadManifest(inputPath string) (*Manifest, er
 	return m, nil
 }
 
-func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap generic.Map, err error) {
+func (repo ManifestDiskRepository) ValidateManifest(inputPath string) (*Manifest, []error) {
+	m := NewEmptyManifest()
+	manifestPath, err := repo.manifestPath(inputPath)
+
+	if err != nil {
+		return m, []error{errors.NewWithError(T("Error finding manifest"), err)}
+	}
+
+	m.Path = manifestPath
+
+	var errs []error
+	mapp, err := repo.readAllYAMLFiles(manifestPath, func(path string, contents []byte, yamlMap generic.Map) {
+		errs = append(errs, validateManifestFile(path, contents, yamlMap)...)
+	})
+	if err != nil {
+		return m, append(errs, err)
+	}
+
+	m.Data = mapp
+
+	if len(errs) == 0 {
+		if _, err := m.Applications(); err != nil {
+			errs = append(errs, NewValidationError(manifestPath, 0, err.Error()))
+		}
+	}
+
+	return m, errs
+}
+
+type manifestFileVisitor func(path string, contents []byte, yamlMap generic.Map)
+
+func (repo ManifestDiskRepository) readAllYAMLFiles(path string, visit manifestFileVisitor) (mergedMap generic.Map, err error) {
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return
 	}
 	defer file.Close()
 
-	mapp, err := parseManifest(file)
+	contents, err := ioutil.ReadAll(file)
 	if err != nil {
 		return
 	}
 
+	mapp, err := parseManifest(bytes.NewReader(contents))
+	if err != nil {
+		return
+	}
+
+	if visit != nil {
+		visit(path, contents, mapp)
+	}
+
 	if !mapp.Has("inherit") {
 		mergedMap = mapp
 		return
@@ -69,7 +111,7 @@ func (repo ManifestDiskRepository) readAllYAMLFiles(path string) (mergedMap gene
 		inheritedPath = filepath.Join(filepath.Dir(path), inheritedPath)
 	}
 
-	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath)
+	inheritedMap, err := repo.readAllYAMLFiles(inheritedPath, visit)
 	if err != nil {
 		return
 	}
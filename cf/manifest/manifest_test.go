@@ -187,6 +187,20 @@ var _ = Describe("Manifests", func() {
 		}
 	})
 
+	It("does not expand git repository app paths", func() {
+		m := NewManifest("/some/path/manifest.yml", generic.NewMap(map[interface{}]interface{}{
+			"applications": []interface{}{
+				map[interface{}]interface{}{
+					"path": "git+https://example.com/app.git#v1.2.3",
+				},
+			},
+		}))
+
+		apps, err := m.Applications()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*apps[0].Path).To(Equal("git+https://example.com/app.git#v1.2.3"))
+	})
+
 	It("returns errors when there are null values", func() {
 		m := NewManifest("/some/path", generic.NewMap(map[interface{}]interface{}{
 			"applications": []interface{}{
@@ -252,6 +266,7 @@ var _ = Describe("Manifests", func() {
 					"stack":             "my-stack",
 					"memory":            "256M",
 					"health-check-type": "none",
+					"docker-image":      "my-docker-image",
 					"instances":         1,
 					"timeout":           11,
 					"no-route":          true,
@@ -272,6 +287,7 @@ var _ = Describe("Manifests", func() {
 		Expect(*apps[0].Name).To(Equal("my-app-name"))
 		Expect(*apps[0].StackName).To(Equal("my-stack"))
 		Expect(*apps[0].HealthCheckType).To(Equal("none"))
+		Expect(*apps[0].DockerImage).To(Equal("my-docker-image"))
 		Expect(*apps[0].Memory).To(Equal(int64(256)))
 		Expect(*apps[0].InstanceCount).To(Equal(1))
 		Expect(*apps[0].HealthCheckTimeout).To(Equal(11))
@@ -0,0 +1,351 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/i18n"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+	"github.com/cloudfoundry/cli/generic"
+)
+
+type ValidationError struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func NewValidationError(path string, line int, message string) *ValidationError {
+	return &ValidationError{Path: path, Line: line, Message: message}
+}
+
+func (err *ValidationError) Error() string {
+	if err.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", err.Path, err.Line, err.Message)
+	}
+	return fmt.Sprintf("%s: %s", err.Path, err.Message)
+}
+
+type propertyType int
+
+const (
+	stringProperty propertyType = iota
+	nullableStringProperty
+	bytesProperty
+	intProperty
+	boolProperty
+	stringListProperty
+	envProperty
+)
+
+var appPropertyTypes = map[string]propertyType{
+	"buildpack":         nullableStringProperty,
+	"command":           nullableStringProperty,
+	"disk_quota":        bytesProperty,
+	"docker-image":      stringProperty,
+	"domain":            stringProperty,
+	"domains":           stringListProperty,
+	"env":               envProperty,
+	"health-check-type": stringProperty,
+	"host":              stringProperty,
+	"hosts":             stringListProperty,
+	"instances":         intProperty,
+	"memory":            bytesProperty,
+	"name":              stringProperty,
+	"no-hostname":       boolProperty,
+	"no-route":          boolProperty,
+	"path":              stringProperty,
+	"random-route":      boolProperty,
+	"services":          stringListProperty,
+	"stack":             stringProperty,
+	"timeout":           intProperty,
+}
+
+var globalOnlyProperties = []string{"applications", "inherit"}
+
+// validateManifestFile checks the keys and values of a single manifest file
+// against the known manifest schema. Line numbers are looked up in contents.
+func validateManifestFile(path string, contents []byte, yamlMap generic.Map) (errs []error) {
+	lines := newLineLocator(contents)
+
+	generic.Each(yamlMap, func(key, value interface{}) {
+		keyName := fmt.Sprintf("%v", key)
+		line := lines.globalKeyLine(keyName)
+
+		switch keyName {
+		case "inherit":
+			if _, ok := value.(string); !ok {
+				errs = append(errs, NewValidationError(path, line, T("invalid inherit path in manifest")))
+			}
+		case "applications":
+			appMaps, ok := value.([]interface{})
+			if !ok {
+				errs = append(errs, NewValidationError(path, line, T("Expected applications to be a list")))
+				return
+			}
+
+			for index, appData := range appMaps {
+				if !generic.IsMappable(appData) {
+					errs = append(errs, NewValidationError(path, lines.appLine(index),
+						T("Expected application to be a list of key/value pairs")))
+					continue
+				}
+
+				generic.Each(generic.NewMap(appData), func(key, value interface{}) {
+					appKeyName := fmt.Sprintf("%v", key)
+					errs = append(errs, validateProperty(path, lines.appKeyLine(index, appKeyName), appKeyName, value, false)...)
+				})
+			}
+		default:
+			errs = append(errs, validateProperty(path, line, keyName, value, true)...)
+		}
+	})
+
+	sort.Sort(byLine(errs))
+	return
+}
+
+func validateProperty(path string, line int, key string, value interface{}, global bool) (errs []error) {
+	propType, ok := appPropertyTypes[key]
+	if !ok {
+		if suggestion := suggestKey(key, global); suggestion != "" {
+			errs = append(errs, NewValidationError(path, line, T("Unknown manifest key '{{.PropertyName}}', did you mean '{{.Suggestion}}'?",
+				map[string]interface{}{"PropertyName": key, "Suggestion": suggestion})))
+		} else {
+			errs = append(errs, NewValidationError(path, line, T("Unknown manifest key '{{.PropertyName}}'",
+				map[string]interface{}{"PropertyName": key})))
+		}
+		return
+	}
+
+	var message string
+	switch propType {
+	case stringProperty:
+		if _, ok := value.(string); !ok {
+			message = T("{{.PropertyName}} must be a string value", map[string]interface{}{"PropertyName": key})
+		}
+	case nullableStringProperty:
+		if _, ok := value.(string); !ok && value != nil {
+			message = T("{{.PropertyName}} must be a string or null value", map[string]interface{}{"PropertyName": key})
+		}
+	case bytesProperty:
+		if _, err := formatters.ToMegabytes(coerceToString(value)); value == nil || err != nil {
+			message = T("Invalid value for '{{.PropertyName}}': {{.StringVal}} (expected a number with a unit, e.g. 256M or 1G)",
+				map[string]interface{}{"PropertyName": key, "StringVal": value})
+		}
+	case intProperty:
+		valid := false
+		switch val := value.(type) {
+		case int, int64:
+			valid = true
+		case string:
+			_, err := strconv.Atoi(val)
+			valid = err == nil
+		}
+		if !valid {
+			message = T("Expected {{.PropertyName}} to be a {{.ExpectedType}}, but it was '{{.PropertyValue}}'.",
+				map[string]interface{}{"PropertyName": key, "ExpectedType": T("number"), "PropertyValue": value})
+		}
+	case boolProperty:
+		switch val := value.(type) {
+		case bool:
+		case string:
+			if val != "true" && val != "false" {
+				message = T("Expected {{.PropertyName}} to be a boolean.", map[string]interface{}{"PropertyName": key})
+			}
+		default:
+			message = T("Expected {{.PropertyName}} to be a boolean.", map[string]interface{}{"PropertyName": key})
+		}
+	case stringListProperty:
+		if !isStringList(value) {
+			message = T("Expected {{.PropertyName}} to be a list of strings.", map[string]interface{}{"PropertyName": key})
+		}
+	case envProperty:
+		if !generic.IsMappable(value) {
+			message = T("Expected {{.Name}} to be a set of key => value, but it was a {{.Type}}.",
+				map[string]interface{}{"Name": key, "Type": value})
+		} else {
+			for _, err := range validateEnvVars(generic.NewMap(value)) {
+				errs = append(errs, NewValidationError(path, line, err.Error()))
+			}
+		}
+	}
+
+	if message == "" && key == "health-check-type" && value != "port" && value != "none" {
+		message = T("Invalid health-check-type param: {{.healthCheckType}}", map[string]interface{}{"healthCheckType": value})
+	}
+
+	if message != "" {
+		errs = append(errs, NewValidationError(path, line, message))
+	}
+	return
+}
+
+func isStringList(value interface{}) bool {
+	list, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, item := range list {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func suggestKey(key string, global bool) string {
+	candidates := []string{}
+	for name, _ := range appPropertyTypes {
+		candidates = append(candidates, name)
+	}
+	if global {
+		candidates = append(candidates, globalOnlyProperties...)
+	}
+	sort.Strings(candidates)
+
+	suggestion := ""
+	bestDistance := 3
+	for _, candidate := range candidates {
+		distance := levenshteinDistance(strings.ToLower(key), candidate)
+		if distance < bestDistance {
+			suggestion = candidate
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+func levenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+type byLine []error
+
+func (errs byLine) Len() int      { return len(errs) }
+func (errs byLine) Swap(i, j int) { errs[i], errs[j] = errs[j], errs[i] }
+func (errs byLine) Less(i, j int) bool {
+	if errs[i].(*ValidationError).Line == errs[j].(*ValidationError).Line {
+		return errs[i].Error() < errs[j].Error()
+	}
+	return errs[i].(*ValidationError).Line < errs[j].(*ValidationError).Line
+}
+
+var (
+	yamlKeyRegex      = regexp.MustCompile(`^(\s*)([^\s#'"-][^:#]*?|'[^']*'|"[^"]*")\s*:(\s|$)`)
+	yamlListItemRegex = regexp.MustCompile(`^(\s*)-(\s+)(.*)$`)
+)
+
+// lineLocator finds the line on which a key was defined in a manifest file.
+// It only understands the block style that manifests are normally written in;
+// keys it cannot find are reported without a line number.
+type lineLocator struct {
+	globalKeys map[string]int
+	appLines   []int
+	appKeys    []map[string]int
+}
+
+func newLineLocator(contents []byte) lineLocator {
+	locator := lineLocator{globalKeys: map[string]int{}}
+
+	inApplications := false
+	listIndent, keyIndent := -1, -1
+
+	for index, line := range strings.Split(string(contents), "\n") {
+		lineNumber := index + 1
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		if match := yamlKeyRegex.FindStringSubmatch(line); match != nil && match[1] == "" {
+			key := strings.Trim(match[2], `'"`)
+			locator.globalKeys[key] = lineNumber
+			inApplications = key == "applications"
+			listIndent, keyIndent = -1, -1
+			continue
+		}
+
+		if !inApplications {
+			continue
+		}
+
+		if match := yamlListItemRegex.FindStringSubmatch(line); match != nil && (listIndent == -1 || len(match[1]) == listIndent) {
+			listIndent = len(match[1])
+			keyIndent = len(match[1]) + 1 + len(match[2])
+			locator.appLines = append(locator.appLines, lineNumber)
+			locator.appKeys = append(locator.appKeys, map[string]int{})
+			locator.addAppKey(strings.Repeat(" ", keyIndent)+match[3], keyIndent, lineNumber)
+			continue
+		}
+
+		if len(locator.appKeys) > 0 {
+			locator.addAppKey(line, keyIndent, lineNumber)
+		}
+	}
+
+	return locator
+}
+
+func (locator lineLocator) addAppKey(line string, keyIndent int, lineNumber int) {
+	match := yamlKeyRegex.FindStringSubmatch(line)
+	if match == nil || len(match[1]) != keyIndent {
+		return
+	}
+
+	key := strings.Trim(match[2], `'"`)
+	locator.appKeys[len(locator.appKeys)-1][key] = lineNumber
+}
+
+func (locator lineLocator) globalKeyLine(key string) int {
+	return locator.globalKeys[key]
+}
+
+func (locator lineLocator) appLine(index int) int {
+	if index < len(locator.appLines) {
+		return locator.appLines[index]
+	}
+	return locator.globalKeys["applications"]
+}
+
+func (locator lineLocator) appKeyLine(index int, key string) int {
+	if index < len(locator.appKeys) {
+		if line, ok := locator.appKeys[index][key]; ok {
+			return line
+		}
+	}
+	return locator.appLine(index)
+}
@@ -0,0 +1,60 @@
+package manifest_test
+
+import (
+	"path/filepath"
+
+	. "github.com/cloudfoundry/cli/cf/manifest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateManifest", func() {
+	var repo ManifestRepository
+
+	BeforeEach(func() {
+		repo = NewManifestDiskRepository()
+	})
+
+	It("returns no errors for a valid manifest", func() {
+		m, errs := repo.ValidateManifest("../../fixtures/manifests/different-manifest.yml")
+
+		Expect(errs).To(BeEmpty())
+		Expect(m.Path).To(Equal(filepath.Clean("../../fixtures/manifests/different-manifest.yml")))
+	})
+
+	It("validates the manifests it inherits from", func() {
+		_, errs := repo.ValidateManifest("../../fixtures/manifests/inherited-manifest.yml")
+		Expect(errs).To(BeEmpty())
+
+		path := filepath.Clean("../../fixtures/manifests/invalid-base-manifest.yml")
+		_, errs = repo.ValidateManifest("../../fixtures/manifests/invalid-inherited-manifest.yml")
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Error()).To(Equal(path + ":2: Expected timeout to be a number, but it was 'soon'."))
+	})
+
+	It("reports every problem with the file and line it occurred on", func() {
+		path := filepath.Clean("../../fixtures/manifests/invalid-manifest.yml")
+		_, errs := repo.ValidateManifest(path)
+
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+
+		Expect(messages).To(Equal([]string{
+			path + ":2: Unknown manifest key 'memroy', did you mean 'memory'?",
+			path + ":5: Expected instances to be a number, but it was 'lots'.",
+			path + ":6: Invalid value for 'disk_quota': 1 gigabyte (expected a number with a unit, e.g. 256M or 1G)",
+			path + ":8: Expected no-route to be a boolean.",
+			path + ":10: Unknown manifest key 'helth-check-type', did you mean 'health-check-type'?",
+			path + ":11: Expected services to be a list of strings.",
+		}))
+	})
+
+	It("returns an error when the manifest cannot be found", func() {
+		m, errs := repo.ValidateManifest("some/path/that/doesnt/exist/manifest.yml")
+
+		Expect(errs).To(HaveLen(1))
+		Expect(m.Path).To(BeEmpty())
+	})
+})
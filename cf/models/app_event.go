@@ -8,4 +8,49 @@ type EventFields struct {
 	Timestamp   time.Time
 	Description string
 	ActorName   string
+	Actor       string
+	ActeeType   string
+	ActeeName   string
+}
+
+// EventsFilter selects the events to list. Exactly one of AppGuid, SpaceGuid
+// and OrgGuid scopes the search; zero times and empty values match anything.
+type EventsFilter struct {
+	AppGuid   string
+	SpaceGuid string
+	OrgGuid   string
+	Types     []string
+	Actor     string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Matches reports whether event passes the type, actor and time filters.
+func (filter EventsFilter) Matches(event EventFields) bool {
+	if len(filter.Types) > 0 {
+		matched := false
+		for _, eventType := range filter.Types {
+			if event.Name == eventType {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if filter.Actor != "" && filter.Actor != event.ActorName && filter.Actor != event.Actor {
+		return false
+	}
+
+	if !filter.Since.IsZero() && event.Timestamp.Before(filter.Since) {
+		return false
+	}
+
+	if !filter.Until.IsZero() && event.Timestamp.After(filter.Until) {
+		return false
+	}
+
+	return true
 }
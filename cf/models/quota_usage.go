@@ -0,0 +1,45 @@
+package models
+
+// QuotaUsage is what the apps and service instances of a space, or of all
+// the spaces of an org, use of the limits of a quota.
+type QuotaUsage struct {
+	Memory           int64 // in Megabytes, of the running app instances
+	InstanceMemory   int64 // in Megabytes, of the largest app instance
+	Routes           int
+	ServiceInstances int // user-provided service instances do not count
+}
+
+// NewQuotaUsage sums the usage of the app summaries and service instances
+// of a space. Routes mapped to several apps are counted once.
+func NewQuotaUsage(apps []Application, serviceInstances []ServiceInstance) (usage QuotaUsage) {
+	routes := map[string]bool{}
+	for _, app := range apps {
+		if app.RunningInstances > 0 {
+			usage.Memory += app.Memory * int64(app.RunningInstances)
+		}
+		if app.Memory > usage.InstanceMemory {
+			usage.InstanceMemory = app.Memory
+		}
+		for _, route := range app.Routes {
+			routes[route.Guid] = true
+		}
+	}
+	usage.Routes = len(routes)
+
+	for _, instance := range serviceInstances {
+		if !instance.IsUserProvided() {
+			usage.ServiceInstances++
+		}
+	}
+	return
+}
+
+func (usage QuotaUsage) Add(other QuotaUsage) QuotaUsage {
+	usage.Memory += other.Memory
+	if other.InstanceMemory > usage.InstanceMemory {
+		usage.InstanceMemory = other.InstanceMemory
+	}
+	usage.Routes += other.Routes
+	usage.ServiceInstances += other.ServiceInstances
+	return usage
+}
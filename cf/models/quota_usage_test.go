@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	. "github.com/cloudfoundry/cli/cf/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QuotaUsage", func() {
+	var (
+		apps             []Application
+		serviceInstances []ServiceInstance
+	)
+
+	BeforeEach(func() {
+		route1 := RouteSummary{Guid: "route-1-guid"}
+		route2 := RouteSummary{Guid: "route-2-guid"}
+
+		apps = []Application{
+			{ApplicationFields: ApplicationFields{Memory: 256, InstanceCount: 2, RunningInstances: 2}, Routes: []RouteSummary{route1, route2}},
+			{ApplicationFields: ApplicationFields{Memory: 1024, InstanceCount: 3, RunningInstances: 1}, Routes: []RouteSummary{route1}},
+			{ApplicationFields: ApplicationFields{Memory: 512, InstanceCount: 1, RunningInstances: 0}},
+		}
+
+		managed := ServiceInstance{}
+		managed.ServicePlan.Guid = "plan-guid"
+		serviceInstances = []ServiceInstance{managed, managed, ServiceInstance{}}
+	})
+
+	It("sums the memory of running instances, routes and managed service instances", func() {
+		usage := NewQuotaUsage(apps, serviceInstances)
+
+		Expect(usage).To(Equal(QuotaUsage{
+			Memory:           1536,
+			InstanceMemory:   1024,
+			Routes:           2,
+			ServiceInstances: 2,
+		}))
+	})
+
+	It("adds up the usage of several spaces", func() {
+		usage := QuotaUsage{Memory: 100, InstanceMemory: 2048, Routes: 1, ServiceInstances: 1}.Add(NewQuotaUsage(apps, serviceInstances))
+
+		Expect(usage).To(Equal(QuotaUsage{
+			Memory:           1636,
+			InstanceMemory:   2048,
+			Routes:           3,
+			ServiceInstances: 3,
+		}))
+	})
+})
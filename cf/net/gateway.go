@@ -205,7 +205,14 @@ func (gateway Gateway) newRequest(request *http.Request, accessToken string, bod
 }
 
 func (gateway Gateway) NewRequestForFile(method, fullUrl, accessToken string, body *os.File) (req *Request, apiErr error) {
+	return gateway.NewRequestForFilePart(method, fullUrl, accessToken, body, 0, 0)
+}
+
+// NewRequestForFilePart builds a request for a file that is one part of a
+// larger upload. uploadedBefore bytes of uploadTotal have already been sent.
+func (gateway Gateway) NewRequestForFilePart(method, fullUrl, accessToken string, body *os.File, uploadedBefore, uploadTotal int64) (req *Request, apiErr error) {
 	progressReader := NewProgressReader(body, gateway.ui, 5*time.Second)
+	progressReader.SetOverallProgress(uploadedBefore, uploadTotal)
 	progressReader.Seek(0, 0)
 	fileStats, err := body.Stat()
 
@@ -245,6 +252,35 @@ func (gateway Gateway) PerformRequest(request *Request) (rawResponse *http.Respo
 	return gateway.doRequestHandlingAuth(request)
 }
 
+// PerformRequestForDownload writes the body of the response to destination,
+// reporting the progress of the download on stderr. It fails when the body is shorter
+// than the Content-Length of the response.
+func (gateway Gateway) PerformRequestForDownload(request *Request, destination io.Writer) (headers http.Header, apiErr error) {
+	rawResponse, apiErr := gateway.doRequestHandlingAuth(request)
+	if apiErr != nil {
+		return
+	}
+	defer rawResponse.Body.Close()
+
+	headers = rawResponse.Header
+
+	progressReader := NewDownloadProgressReader(rawResponse.Body, os.Stderr, 5*time.Second)
+	progressReader.SetTotalSize(rawResponse.ContentLength)
+	defer progressReader.Close()
+
+	written, err := io.Copy(destination, progressReader)
+	if err != nil {
+		apiErr = errors.NewWithError(T("Error downloading file: {{.Err}}", map[string]interface{}{"Err": err}), err)
+		return
+	}
+
+	if rawResponse.ContentLength >= 0 && written != rawResponse.ContentLength {
+		apiErr = errors.New(T("Download incomplete: received {{.Received}} of {{.Expected}} bytes",
+			map[string]interface{}{"Received": written, "Expected": rawResponse.ContentLength}))
+	}
+	return
+}
+
 func (gateway Gateway) performRequestForResponseBytes(request *Request) (bytes []byte, headers http.Header, rawResponse *http.Response, apiErr error) {
 	rawResponse, apiErr = gateway.doRequestHandlingAuth(request)
 	if apiErr != nil {
@@ -62,12 +62,25 @@ func dumpRequest(req *http.Request) {
 }
 
 func dumpResponse(res *http.Response) {
-	dumpedResponse, err := httputil.DumpResponse(res, true)
+	shouldDisplayBody := !isBinaryContent(res.Header.Get("Content-Type"))
+	dumpedResponse, err := httputil.DumpResponse(res, shouldDisplayBody)
 	if err != nil {
 		trace.Logger.Printf(T("Error dumping response\n{{.Err}}\n", map[string]interface{}{"Err": err}))
 	} else {
 		trace.Logger.Printf("\n%s [%s]\n%s\n", terminal.HeaderColor(T("RESPONSE:")), time.Now().Format(time.RFC3339), trace.Sanitize(string(dumpedResponse)))
+		if !shouldDisplayBody {
+			trace.Logger.Println(T("[BINARY CONTENT HIDDEN]"))
+		}
+	}
+}
+
+func isBinaryContent(contentType string) bool {
+	for _, binaryType := range []string{"application/octet-stream", "application/zip", "application/x-gzip", "application/gzip", "application/x-tar"} {
+		if strings.Contains(contentType, binaryType) {
+			return true
+		}
 	}
+	return false
 }
 
 func WrapNetworkErrors(host string, err error) error {
@@ -1,6 +1,7 @@
 package net
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"time"
@@ -13,9 +14,15 @@ type ProgressReader struct {
 	ioReadSeeker   io.ReadSeeker
 	bytesRead      int64
 	total          int64
+	uploadedBefore int64
+	overallTotal   int64
 	quit           chan bool
+	stopped        chan bool
+	done           bool
 	ui             terminal.UI
+	output         io.Writer
 	outputInterval time.Duration
+	downloading    bool
 }
 
 func NewProgressReader(readSeeker io.ReadSeeker, ui terminal.UI, outputInterval time.Duration) *ProgressReader {
@@ -26,6 +33,18 @@ func NewProgressReader(readSeeker io.ReadSeeker, ui terminal.UI, outputInterval
 	}
 }
 
+// NewDownloadProgressReader reports the progress of reading a download to
+// output rather than the UI, so that it does not end up in the output of
+// commands that print what they downloaded. The returned reader cannot seek.
+func NewDownloadProgressReader(reader io.Reader, output io.Writer, outputInterval time.Duration) *ProgressReader {
+	return &ProgressReader{
+		ioReadSeeker:   unseekableReader{reader},
+		output:         output,
+		outputInterval: outputInterval,
+		downloading:    true,
+	}
+}
+
 func (progressReader *ProgressReader) Read(p []byte) (int, error) {
 	if progressReader.ioReadSeeker == nil {
 		return 0, os.ErrInvalid
@@ -37,13 +56,14 @@ func (progressReader *ProgressReader) Read(p []byte) (int, error) {
 		if n > 0 {
 			if progressReader.quit == nil {
 				progressReader.quit = make(chan bool)
+				progressReader.stopped = make(chan bool)
 				go progressReader.printProgress(progressReader.quit)
 			}
 
 			progressReader.bytesRead += int64(n)
 
 			if progressReader.total == progressReader.bytesRead {
-				progressReader.quit <- true
+				progressReader.stop(true)
 				return n, err
 			}
 		}
@@ -52,27 +72,86 @@ func (progressReader *ProgressReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
+// Close stops printing the progress. It has to be called when reading stops
+// before all of the content was read, e.g. because a download failed.
+func (progressReader *ProgressReader) Close() error {
+	progressReader.stop(false)
+	return nil
+}
+
+func (progressReader *ProgressReader) stop(finished bool) {
+	if progressReader.quit == nil || progressReader.done {
+		return
+	}
+
+	progressReader.done = true
+	progressReader.quit <- finished
+	<-progressReader.stopped
+}
+
 func (progressReader *ProgressReader) Seek(offset int64, whence int) (int64, error) {
 	return progressReader.ioReadSeeker.Seek(offset, whence)
 }
 
 func (progressReader *ProgressReader) printProgress(quit chan bool) {
 	timer := time.NewTicker(progressReader.outputInterval)
+	defer timer.Stop()
+	defer close(progressReader.stopped)
 
 	for {
 		select {
-		case <-quit:
+		case finished := <-quit:
 			//The spaces are there to ensure we overwrite the entire line
 			//before using the terminal printer to output Done Uploading
-			progressReader.ui.PrintCapturingNoOutput("\r                             ")
-			progressReader.ui.Say("\rDone uploading")
+			progressReader.printf("\r                             ")
+			if !finished {
+				return
+			}
+			if progressReader.downloading {
+				progressReader.printf("\rDone downloading\n")
+			} else if progressReader.overallTotal == 0 || progressReader.uploadedBefore+progressReader.bytesRead >= progressReader.overallTotal {
+				progressReader.ui.Say("\rDone uploading")
+			}
 			return
 		case <-timer.C:
-			progressReader.ui.PrintCapturingNoOutput("\r%s uploaded...", formatters.ByteSize(progressReader.bytesRead))
+			if progressReader.downloading {
+				progressReader.printf("\r%s of %s downloaded...",
+					formatters.ByteSize(progressReader.bytesRead),
+					formatters.ByteSize(progressReader.total))
+			} else if progressReader.overallTotal > 0 {
+				progressReader.ui.PrintCapturingNoOutput("\r%s of %s uploaded...",
+					formatters.ByteSize(progressReader.uploadedBefore+progressReader.bytesRead),
+					formatters.ByteSize(progressReader.overallTotal))
+			} else {
+				progressReader.ui.PrintCapturingNoOutput("\r%s uploaded...", formatters.ByteSize(progressReader.bytesRead))
+			}
 		}
 	}
 }
 
+func (progressReader *ProgressReader) printf(message string, args ...interface{}) {
+	if progressReader.output != nil {
+		fmt.Fprintf(progressReader.output, message, args...)
+		return
+	}
+	progressReader.ui.PrintCapturingNoOutput(message, args...)
+}
+
 func (progressReader *ProgressReader) SetTotalSize(size int64) {
 	progressReader.total = size
 }
+
+// SetOverallProgress is used when the content is one part of a larger upload,
+// so that progress is reported for the upload as a whole.
+func (progressReader *ProgressReader) SetOverallProgress(uploadedBefore, overallTotal int64) {
+	progressReader.uploadedBefore = uploadedBefore
+	progressReader.overallTotal = overallTotal
+}
+
+type unseekableReader struct {
+	io.Reader
+}
+
+func (reader unseekableReader) Seek(offset int64, whence int) (int64, error) {
+	return 0, os.ErrInvalid
+}
@@ -1,6 +1,7 @@
 package net_test
 
 import (
+	"bytes"
 	"os"
 	"time"
 
@@ -64,4 +65,81 @@ var _ = Describe("ProgressReader", func() {
 
 		Expect(int64(bytesRead)).To(Equal(fileStat.Size()))
 	})
+
+	Context("when the content is one part of a larger upload", func() {
+		readAll := func() {
+			for {
+				time.Sleep(50 * time.Microsecond)
+				_, err := progressReader.Read(b)
+				if err != nil {
+					break
+				}
+			}
+		}
+
+		It("prints the progress of the whole upload", func() {
+			progressReader.SetOverallProgress(fileStat.Size(), 2*fileStat.Size())
+			readAll()
+
+			Expect(ui.UncapturedOutput).To(ContainSubstrings([]string{"\r", "of", "uploaded..."}))
+			Expect(ui.Outputs).To(ContainSubstrings([]string{"\rDone "}))
+		})
+
+		It("does not print that it is done before the last part is read", func() {
+			progressReader.SetOverallProgress(0, 2*fileStat.Size())
+			readAll()
+
+			Expect(ui.UncapturedOutput).To(ContainSubstrings([]string{"\r    "}))
+			Expect(ui.Outputs).ToNot(ContainSubstrings([]string{"Done"}))
+		})
+	})
+
+	Context("when the content is a download", func() {
+		var output *bytes.Buffer
+
+		BeforeEach(func() {
+			output = &bytes.Buffer{}
+		})
+
+		It("prints the progress of the download to its output instead of the UI", func() {
+			progressReader = NewDownloadProgressReader(testFile, output, 1*time.Millisecond)
+			progressReader.SetTotalSize(fileStat.Size())
+
+			for {
+				time.Sleep(50 * time.Microsecond)
+				_, err := progressReader.Read(b)
+				if err != nil {
+					break
+				}
+			}
+
+			Expect(output.String()).To(ContainSubstring("\rDone downloading\n"))
+			Expect(ui.UncapturedOutput).To(BeEmpty())
+			Expect(ui.Outputs).To(BeEmpty())
+		})
+
+		It("stops printing the progress when it is closed before the download is complete", func() {
+			progressReader = NewDownloadProgressReader(testFile, output, 1*time.Millisecond)
+			progressReader.SetTotalSize(fileStat.Size() * 2)
+
+			_, err := progressReader.Read(b)
+			Expect(err).NotTo(HaveOccurred())
+			time.Sleep(5 * time.Millisecond)
+
+			Expect(progressReader.Close()).To(Succeed())
+			printed := output.String()
+			time.Sleep(5 * time.Millisecond)
+
+			Expect(output.String()).To(Equal(printed))
+			Expect(printed).To(ContainSubstring("downloaded..."))
+			Expect(printed).NotTo(ContainSubstring("Done"))
+		})
+
+		It("cannot seek", func() {
+			progressReader = NewDownloadProgressReader(testFile, output, 1*time.Millisecond)
+
+			_, err := progressReader.Seek(0, 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
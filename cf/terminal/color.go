@@ -136,6 +136,14 @@ func LogSysHeaderColor(message string) string {
 	return ColorizeBold(message, cyan)
 }
 
+func DiffAddedColor(message string) string {
+	return Colorize(message, green)
+}
+
+func DiffRemovedColor(message string) string {
+	return Colorize(message, red)
+}
+
 func isTerminal() bool {
 	return terminal.IsTerminal(1)
 }
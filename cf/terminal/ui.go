@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -32,6 +33,7 @@ type UI interface {
 	ConfirmDeleteWithAssociations(modelType, modelName string) bool
 	Ok()
 	Failed(message string, args ...interface{})
+	FailedOnStderr(message string, args ...interface{})
 	PanicQuietly()
 	ShowConfiguration(core_config.Reader)
 	LoadingIndication()
@@ -138,6 +140,10 @@ func (c *terminalUI) Ok() {
 
 const QuietPanic = "This shouldn't print anything"
 
+// ExitCode is panicked with after a failure has been reported, to make the
+// CLI exit with a status other than 1.
+type ExitCode int
+
 func (c *terminalUI) Failed(message string, args ...interface{}) {
 	message = fmt.Sprintf(message, args...)
 
@@ -158,6 +164,23 @@ func (c *terminalUI) Failed(message string, args ...interface{}) {
 	}
 }
 
+// FailedOnStderr reports a failure like Failed, but writes it to stderr so
+// that it does not end up in output that is redirected or evaluated.
+func (c *terminalUI) FailedOnStderr(message string, args ...interface{}) {
+	message = fmt.Sprintf(message, args...)
+
+	failed := "FAILED"
+	if T != nil {
+		failed = T("FAILED")
+	}
+	fmt.Fprintln(os.Stderr, FailureColor(failed))
+	fmt.Fprintln(os.Stderr, message)
+
+	trace.Logger.Print(failed)
+	trace.Logger.Print(message)
+	c.PanicQuietly()
+}
+
 func (c *terminalUI) PanicQuietly() {
 	panic(QuietPanic)
 }
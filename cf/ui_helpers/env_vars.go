@@ -0,0 +1,268 @@
+package ui_helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cli/cf/errors"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+const (
+	EnvVarFormatEnv    = "env"
+	EnvVarFormatDotenv = "dotenv"
+)
+
+var invalidEnvVarNameChars = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+var shellVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var lineBreaks = strings.NewReplacer("\n", " ", "\r", " ")
+
+// EnvVarName joins the parts with underscores and turns the result into an
+// upper-case name that can be used as a shell variable, e.g. "my-db", "uri"
+// becomes "MY_DB_URI".
+func EnvVarName(parts ...string) string {
+	nonEmpty := []string{}
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+
+	name := invalidEnvVarNameChars.ReplaceAllString(strings.ToUpper(strings.Join(nonEmpty, "_")), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// FlattenCredentials turns nested credentials into env variables, naming each
+// leaf value after the path of keys leading to it, e.g. {"db": {"port": 5432}}
+// with the prefix "my-db" becomes MY_DB_DB_PORT=5432. Array elements are named
+// after their index.
+func FlattenCredentials(prefix string, credentials map[string]interface{}) map[string]string {
+	vars := map[string]string{}
+	flattenValue(vars, EnvVarName(prefix), credentials)
+	return vars
+}
+
+func flattenValue(vars map[string]string, name string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			flattenValue(vars, EnvVarName(name, key), nested)
+		}
+	case []interface{}:
+		for i, nested := range value {
+			flattenValue(vars, EnvVarName(name, strconv.Itoa(i)), nested)
+		}
+	default:
+		vars[name] = EnvVarValue(value)
+	}
+}
+
+// EnvVarValue returns the string form of an env variable value: strings are
+// used as they are, numbers are written without exponents, null becomes empty
+// and objects and arrays become JSON.
+func EnvVarValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		jsonBytes, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(jsonBytes)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// FormatEnvVars returns one line per variable, sorted by name. The env format
+// gives `export NAME='value'` lines that can be sourced by a shell, the dotenv
+// format gives `NAME="value"` lines as read by dotenv libraries. A shell
+// cannot export names such as my.key, so the env format gives a # comment
+// line for them instead.
+func FormatEnvVars(vars map[string]string, format string) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		switch format {
+		case EnvVarFormatDotenv:
+			lines = append(lines, fmt.Sprintf("%s=%s", name, dotenvQuote(vars[name])))
+		default:
+			if !shellVarName.MatchString(name) {
+				lines = append(lines, "# "+T("{{.Name}} is not a valid shell variable name, skipped", map[string]interface{}{"Name": lineBreaks.Replace(name)}))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("export %s=%s", name, shellQuote(vars[name])))
+		}
+	}
+	return lines
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+func dotenvQuote(value string) string {
+	return `"` + dotenvEscaper.Replace(value) + `"`
+}
+
+var dotenvUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\r`, "\r")
+
+var validEnvVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ParseEnvVars reads env variables from a JSON object or from dotenv lines,
+// the format FormatEnvVars writes. Dotenv lines may start with `export`,
+// values may be single quoted (taken literally, with \' between quoted parts
+// for a quote, as a shell reads them), double quoted (with \n, \" and \\
+// escapes) or bare, and blank lines and # comments are skipped.
+func ParseEnvVars(contents []byte) (map[string]interface{}, error) {
+	trimmed := strings.TrimSpace(string(contents))
+	if strings.HasPrefix(trimmed, "{") {
+		vars := map[string]interface{}{}
+		err := json.Unmarshal([]byte(trimmed), &vars)
+		if err != nil {
+			return nil, err
+		}
+		return vars, nil
+	}
+
+	vars := map[string]interface{}{}
+	for i, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		parts := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || !validEnvVarName.MatchString(name) {
+			return nil, errors.New(T("line {{.Line}}: expected NAME=value", map[string]interface{}{"Line": i + 1}))
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.New(T("line {{.Line}}: {{.Error}}", map[string]interface{}{"Line": i + 1, "Error": err.Error()}))
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+func parseDotenvValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "'"):
+		return parseSingleQuotedValue(value)
+	case strings.HasPrefix(value, `"`):
+		escaped := false
+		for i := 1; i < len(value); i++ {
+			switch {
+			case escaped:
+				escaped = false
+			case value[i] == '\\':
+				escaped = true
+			case value[i] == '"':
+				return dotenvUnescaper.Replace(value[1:i]), nil
+			}
+		}
+		return "", errors.New(T("unterminated quote"))
+	default:
+		if i := strings.Index(value, " #"); i != -1 {
+			value = strings.TrimSpace(value[:i])
+		}
+		return value, nil
+	}
+}
+
+// parseSingleQuotedValue joins adjacent single quoted parts and escaped
+// quotes, since shellQuote writes a quote by closing the quoted part, adding
+// an escaped quote and opening the next part.
+func parseSingleQuotedValue(value string) (string, error) {
+	parsed := ""
+	for {
+		switch {
+		case strings.HasPrefix(value, `\'`):
+			parsed += "'"
+			value = value[2:]
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end == -1 {
+				return "", errors.New(T("unterminated quote"))
+			}
+			parsed += value[1 : end+1]
+			value = value[end+2:]
+		default:
+			return parsed, nil
+		}
+	}
+}
+
+// ReadEnvVarsFile parses the JSON or dotenv file at path with ParseEnvVars.
+func ReadEnvVarsFile(path string) (map[string]interface{}, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewWithError(T("Error reading env file {{.Path}}", map[string]interface{}{"Path": path}), err)
+	}
+
+	vars, err := ParseEnvVars(contents)
+	if err != nil {
+		return nil, errors.NewWithError(T("Invalid env file {{.Path}}", map[string]interface{}{"Path": path}), err)
+	}
+	return vars, nil
+}
+
+// EnvVarsDiff lists the variables that would change if the live env
+// variables were replaced by the desired ones as colored "- NAME: value" and
+// "+ NAME: value" lines, sorted by name. No lines means no changes.
+func EnvVarsDiff(live map[string]interface{}, desired map[string]interface{}) []string {
+	names := []string{}
+	for name := range live {
+		names = append(names, name)
+	}
+	for name := range desired {
+		if _, ok := live[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	lines := []string{}
+	for _, name := range names {
+		liveValue, isLive := live[name]
+		desiredValue, isDesired := desired[name]
+
+		if isLive && isDesired && reflect.DeepEqual(liveValue, desiredValue) {
+			continue
+		}
+		if isLive {
+			lines = append(lines, terminal.DiffRemovedColor(fmt.Sprintf("- %s: %s", name, EnvVarValue(liveValue))))
+		}
+		if isDesired {
+			lines = append(lines, terminal.DiffAddedColor(fmt.Sprintf("+ %s: %s", name, EnvVarValue(desiredValue))))
+		}
+	}
+	return lines
+}
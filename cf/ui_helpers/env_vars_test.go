@@ -0,0 +1,151 @@
+package ui_helpers_test
+
+import (
+	"strings"
+
+	. "github.com/cloudfoundry/cli/cf/ui_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("env vars", func() {
+	Describe("EnvVarName", func() {
+		It("upper-cases and joins the parts with underscores", func() {
+			Expect(EnvVarName("my-db", "uri")).To(Equal("MY_DB_URI"))
+		})
+
+		It("skips empty parts", func() {
+			Expect(EnvVarName("", "uri")).To(Equal("URI"))
+		})
+
+		It("does not start with a digit", func() {
+			Expect(EnvVarName("1st.db")).To(Equal("_1ST_DB"))
+		})
+	})
+
+	Describe("EnvVarValue", func() {
+		It("writes large integral numbers without an exponent", func() {
+			Expect(EnvVarValue(float64(10000000))).To(Equal("10000000"))
+			Expect(EnvVarValue(float64(1e21))).To(Equal("1000000000000000000000"))
+		})
+
+		It("keeps the fraction of other numbers", func() {
+			Expect(EnvVarValue(float64(0.25))).To(Equal("0.25"))
+		})
+	})
+
+	Describe("FlattenCredentials", func() {
+		It("names each leaf value after its path", func() {
+			credentials := map[string]interface{}{
+				"uri":  "postgres://host/db",
+				"port": float64(5432),
+				"tls":  true,
+				"none": nil,
+				"nodes": []interface{}{
+					map[string]interface{}{"host": "node-0"},
+					"node-1",
+				},
+			}
+
+			Expect(FlattenCredentials("my-db", credentials)).To(Equal(map[string]string{
+				"MY_DB_URI":          "postgres://host/db",
+				"MY_DB_PORT":         "5432",
+				"MY_DB_TLS":          "true",
+				"MY_DB_NONE":         "",
+				"MY_DB_NODES_0_HOST": "node-0",
+				"MY_DB_NODES_1":      "node-1",
+			}))
+		})
+
+		It("does not prefix the names without a prefix", func() {
+			Expect(FlattenCredentials("", map[string]interface{}{"user-name": "admin"})).To(Equal(map[string]string{
+				"USER_NAME": "admin",
+			}))
+		})
+	})
+
+	Describe("FormatEnvVars", func() {
+		vars := map[string]string{
+			"PASSWORD": `it's "secret"`,
+			"CERT":     "line1\nline2",
+		}
+
+		It("formats sorted shell exports", func() {
+			Expect(FormatEnvVars(vars, EnvVarFormatEnv)).To(Equal([]string{
+				"export CERT='line1\nline2'",
+				`export PASSWORD='it'\''s "secret"'`,
+			}))
+		})
+
+		It("comments out names a shell cannot export", func() {
+			Expect(FormatEnvVars(map[string]string{"my.key": "1", "a-b": "2", "OK": "3"}, EnvVarFormatEnv)).To(Equal([]string{
+				"export OK='3'",
+				"# a-b is not a valid shell variable name, skipped",
+				"# my.key is not a valid shell variable name, skipped",
+			}))
+		})
+
+		It("formats sorted dotenv lines", func() {
+			Expect(FormatEnvVars(vars, EnvVarFormatDotenv)).To(Equal([]string{
+				`CERT="line1\nline2"`,
+				`PASSWORD="it's \"secret\""`,
+			}))
+		})
+	})
+
+	Describe("ParseEnvVars", func() {
+		It("reads a JSON object", func() {
+			vars, err := ParseEnvVars([]byte(`{"PORT": 8080, "NAME": "my-app", "DEBUG": true}`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]interface{}{"PORT": float64(8080), "NAME": "my-app", "DEBUG": true}))
+		})
+
+		It("reads dotenv lines", func() {
+			vars, err := ParseEnvVars([]byte(`# database
+DATABASE_URL=postgres://db.example.com/my-db # the primary
+export GREETING='$literal \n'
+MESSAGE="line one\nsays \"hi\""
+
+EMPTY=
+`))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]interface{}{
+				"DATABASE_URL": "postgres://db.example.com/my-db",
+				"GREETING":     `$literal \n`,
+				"MESSAGE":      "line one\nsays \"hi\"",
+				"EMPTY":        "",
+			}))
+		})
+
+		It("reads what FormatEnvVars writes as dotenv", func() {
+			vars := map[string]string{"A": `quote " and backslash \`, "B": "two\nlines"}
+
+			parsed, err := ParseEnvVars([]byte(strings.Join(FormatEnvVars(vars, EnvVarFormatDotenv), "\n")))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(map[string]interface{}{"A": vars["A"], "B": vars["B"]}))
+		})
+
+		It("reads what FormatEnvVars writes as env", func() {
+			vars := map[string]string{"A": `it's a 'quoted' value`, "B": "'", "C": `$literal \n`}
+
+			parsed, err := ParseEnvVars([]byte(strings.Join(FormatEnvVars(vars, EnvVarFormatEnv), "\n")))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(map[string]interface{}{"A": vars["A"], "B": vars["B"], "C": vars["C"]}))
+		})
+
+		It("reports the line of invalid entries", func() {
+			_, err := ParseEnvVars([]byte("A=1\nnot a variable\n"))
+			Expect(err).To(MatchError("line 2: expected NAME=value"))
+
+			_, err = ParseEnvVars([]byte(`A="unterminated`))
+			Expect(err).To(MatchError("line 1: unterminated quote"))
+
+			_, err = ParseEnvVars([]byte(`A='it'\''s`))
+			Expect(err).To(MatchError("line 1: unterminated quote"))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package ui_helpers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloudfoundry/cli/cf/formatters"
+	. "github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+// DefaultQuotaWarnAt is the percentage of a quota limit above which
+// org-usage and space-usage warn.
+const DefaultQuotaWarnAt = 80
+
+// PrintQuotaUsage prints what usage uses of each limit of quota and warns
+// about the limits that are used at or above warnAt percent. owner names the
+// org or space in the warnings.
+func PrintQuotaUsage(ui terminal.UI, usage models.QuotaUsage, quota models.QuotaFields, warnAt int, owner string) {
+	rows := []struct {
+		name        string
+		used, limit int64
+		format      func(int64) string
+	}{
+		{T("memory"), usage.Memory, quota.MemoryLimit, megabytes},
+		{T("instance memory"), usage.InstanceMemory, quota.InstanceMemoryLimit, megabytes},
+		{T("routes"), int64(usage.Routes), int64(quota.RoutesLimit), count},
+		{T("service instances"), int64(usage.ServiceInstances), int64(quota.ServicesLimit), count},
+	}
+
+	warnings := []string{}
+	table := terminal.NewTable(ui, []string{T("resource"), T("used"), T("limit"), T("percent used")})
+	for _, row := range rows {
+		if row.limit == -1 {
+			table.Add(row.name, row.format(row.used), T("unlimited"), "")
+			continue
+		}
+
+		percent := QuotaPercent(row.used, row.limit)
+		percentage := fmt.Sprintf("%d%%", percent)
+		if percent >= warnAt {
+			percentage = terminal.WarningColor(percentage)
+			warnings = append(warnings, T("{{.Resource}} usage of {{.Owner}} is at {{.Percent}}% of its quota", map[string]interface{}{
+				"Resource": row.name,
+				"Owner":    owner,
+				"Percent":  percent,
+			}))
+		}
+		table.Add(row.name, row.format(row.used), row.format(row.limit), percentage)
+	}
+	table.Print()
+
+	if len(warnings) > 0 {
+		ui.Say("")
+	}
+	for _, warning := range warnings {
+		ui.Warn("%s", warning)
+	}
+}
+
+// QuotaPercent is the share of limit that used makes up, rounded down. A
+// limit of 0 counts as fully used as soon as anything uses it.
+func QuotaPercent(used, limit int64) int {
+	if limit <= 0 {
+		if used > 0 {
+			return 100
+		}
+		return 0
+	}
+	return int(used * 100 / limit)
+}
+
+func megabytes(value int64) string {
+	return formatters.ByteSize(value * formatters.MEGABYTE)
+}
+
+func count(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
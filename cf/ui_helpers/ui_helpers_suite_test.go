@@ -1,6 +1,10 @@
 package ui_helpers_test
 
 import (
+	"github.com/cloudfoundry/cli/cf/i18n"
+	"github.com/cloudfoundry/cli/cf/i18n/detection"
+	"github.com/cloudfoundry/cli/testhelpers/configuration"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -8,6 +12,9 @@ import (
 )
 
 func TestUiHelpers(t *testing.T) {
+	config := configuration.NewRepositoryWithDefaults()
+	i18n.T = i18n.Init(config, &detection.JibberJabberDetector{})
+
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "UiHelpers Suite")
 }
@@ -19,6 +19,7 @@ type downloader struct {
 	saveDir    string
 	filename   string
 	downloaded bool
+	transport  http.RoundTripper
 }
 
 func NewDownloader(saveDir string) Downloader {
@@ -28,9 +29,20 @@ func NewDownloader(saveDir string) Downloader {
 	}
 }
 
+// NewDownloaderWithTransport returns a Downloader that makes its requests
+// through transport, e.g. to trust additional certificates.
+func NewDownloaderWithTransport(saveDir string, transport http.RoundTripper) Downloader {
+	return &downloader{
+		saveDir:    saveDir,
+		downloaded: false,
+		transport:  transport,
+	}
+}
+
 //this func returns byte written, filename and error
 func (d *downloader) DownloadFile(url string) (int64, string, error) {
 	c := http.Client{
+		Transport: d.transport,
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			r.URL.Opaque = r.URL.Path
 
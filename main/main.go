@@ -111,6 +111,10 @@ func handlePanics(printer terminal.Printer) {
 	stackTrace := generateBacktrace()
 
 	err := recover()
+	if code, ok := err.(terminal.ExitCode); ok {
+		os.Exit(int(code))
+	}
+
 	panic_printer.DisplayCrashDialog(err, commandArgs, stackTrace)
 
 	if err != nil {
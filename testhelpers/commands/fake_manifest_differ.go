@@ -0,0 +1,189 @@
+// This file was generated by counterfeiter
+package commands
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/cli/cf/command_registry"
+	"github.com/cloudfoundry/cli/cf/commands/application"
+	"github.com/cloudfoundry/cli/cf/models"
+	"github.com/cloudfoundry/cli/cf/requirements"
+	"github.com/simonleung8/flags"
+)
+
+type FakeManifestDiffer struct {
+	MetaDataStub        func() command_registry.CommandMetadata
+	metaDataMutex       sync.RWMutex
+	metaDataArgsForCall []struct{}
+	metaDataReturns     struct {
+		result1 command_registry.CommandMetadata
+	}
+	SetDependencyStub        func(deps command_registry.Dependency, pluginCall bool) command_registry.Command
+	setDependencyMutex       sync.RWMutex
+	setDependencyArgsForCall []struct {
+		deps       command_registry.Dependency
+		pluginCall bool
+	}
+	setDependencyReturns struct {
+		result1 command_registry.Command
+	}
+	RequirementsStub        func(requirementsFactory requirements.Factory, context flags.FlagContext) (reqs []requirements.Requirement, err error)
+	requirementsMutex       sync.RWMutex
+	requirementsArgsForCall []struct {
+		requirementsFactory requirements.Factory
+		context             flags.FlagContext
+	}
+	requirementsReturns struct {
+		result1 []requirements.Requirement
+		result2 error
+	}
+	ExecuteStub        func(context flags.FlagContext)
+	executeMutex       sync.RWMutex
+	executeArgsForCall []struct {
+		context flags.FlagContext
+	}
+	ShowManifestDiffStub        func(apps []models.AppParams)
+	showManifestDiffMutex       sync.RWMutex
+	showManifestDiffArgsForCall []struct {
+		apps []models.AppParams
+	}
+}
+
+func (fake *FakeManifestDiffer) MetaData() command_registry.CommandMetadata {
+	fake.metaDataMutex.Lock()
+	fake.metaDataArgsForCall = append(fake.metaDataArgsForCall, struct{}{})
+	fake.metaDataMutex.Unlock()
+	if fake.MetaDataStub != nil {
+		return fake.MetaDataStub()
+	} else {
+		return fake.metaDataReturns.result1
+	}
+}
+
+func (fake *FakeManifestDiffer) MetaDataCallCount() int {
+	fake.metaDataMutex.RLock()
+	defer fake.metaDataMutex.RUnlock()
+	return len(fake.metaDataArgsForCall)
+}
+
+func (fake *FakeManifestDiffer) MetaDataReturns(result1 command_registry.CommandMetadata) {
+	fake.MetaDataStub = nil
+	fake.metaDataReturns = struct {
+		result1 command_registry.CommandMetadata
+	}{result1}
+}
+
+func (fake *FakeManifestDiffer) SetDependency(deps command_registry.Dependency, pluginCall bool) command_registry.Command {
+	fake.setDependencyMutex.Lock()
+	fake.setDependencyArgsForCall = append(fake.setDependencyArgsForCall, struct {
+		deps       command_registry.Dependency
+		pluginCall bool
+	}{deps, pluginCall})
+	fake.setDependencyMutex.Unlock()
+	if fake.SetDependencyStub != nil {
+		return fake.SetDependencyStub(deps, pluginCall)
+	} else {
+		return fake.setDependencyReturns.result1
+	}
+}
+
+func (fake *FakeManifestDiffer) SetDependencyCallCount() int {
+	fake.setDependencyMutex.RLock()
+	defer fake.setDependencyMutex.RUnlock()
+	return len(fake.setDependencyArgsForCall)
+}
+
+func (fake *FakeManifestDiffer) SetDependencyArgsForCall(i int) (command_registry.Dependency, bool) {
+	fake.setDependencyMutex.RLock()
+	defer fake.setDependencyMutex.RUnlock()
+	return fake.setDependencyArgsForCall[i].deps, fake.setDependencyArgsForCall[i].pluginCall
+}
+
+func (fake *FakeManifestDiffer) SetDependencyReturns(result1 command_registry.Command) {
+	fake.SetDependencyStub = nil
+	fake.setDependencyReturns = struct {
+		result1 command_registry.Command
+	}{result1}
+}
+
+func (fake *FakeManifestDiffer) Requirements(requirementsFactory requirements.Factory, context flags.FlagContext) (reqs []requirements.Requirement, err error) {
+	fake.requirementsMutex.Lock()
+	fake.requirementsArgsForCall = append(fake.requirementsArgsForCall, struct {
+		requirementsFactory requirements.Factory
+		context             flags.FlagContext
+	}{requirementsFactory, context})
+	fake.requirementsMutex.Unlock()
+	if fake.RequirementsStub != nil {
+		return fake.RequirementsStub(requirementsFactory, context)
+	} else {
+		return fake.requirementsReturns.result1, fake.requirementsReturns.result2
+	}
+}
+
+func (fake *FakeManifestDiffer) RequirementsCallCount() int {
+	fake.requirementsMutex.RLock()
+	defer fake.requirementsMutex.RUnlock()
+	return len(fake.requirementsArgsForCall)
+}
+
+func (fake *FakeManifestDiffer) RequirementsArgsForCall(i int) (requirements.Factory, flags.FlagContext) {
+	fake.requirementsMutex.RLock()
+	defer fake.requirementsMutex.RUnlock()
+	return fake.requirementsArgsForCall[i].requirementsFactory, fake.requirementsArgsForCall[i].context
+}
+
+func (fake *FakeManifestDiffer) RequirementsReturns(result1 []requirements.Requirement, result2 error) {
+	fake.RequirementsStub = nil
+	fake.requirementsReturns = struct {
+		result1 []requirements.Requirement
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManifestDiffer) Execute(context flags.FlagContext) {
+	fake.executeMutex.Lock()
+	fake.executeArgsForCall = append(fake.executeArgsForCall, struct {
+		context flags.FlagContext
+	}{context})
+	fake.executeMutex.Unlock()
+	if fake.ExecuteStub != nil {
+		fake.ExecuteStub(context)
+	}
+}
+
+func (fake *FakeManifestDiffer) ExecuteCallCount() int {
+	fake.executeMutex.RLock()
+	defer fake.executeMutex.RUnlock()
+	return len(fake.executeArgsForCall)
+}
+
+func (fake *FakeManifestDiffer) ExecuteArgsForCall(i int) flags.FlagContext {
+	fake.executeMutex.RLock()
+	defer fake.executeMutex.RUnlock()
+	return fake.executeArgsForCall[i].context
+}
+
+func (fake *FakeManifestDiffer) ShowManifestDiff(apps []models.AppParams) {
+	fake.showManifestDiffMutex.Lock()
+	fake.showManifestDiffArgsForCall = append(fake.showManifestDiffArgsForCall, struct {
+		apps []models.AppParams
+	}{apps})
+	fake.showManifestDiffMutex.Unlock()
+	if fake.ShowManifestDiffStub != nil {
+		fake.ShowManifestDiffStub(apps)
+	}
+}
+
+func (fake *FakeManifestDiffer) ShowManifestDiffCallCount() int {
+	fake.showManifestDiffMutex.RLock()
+	defer fake.showManifestDiffMutex.RUnlock()
+	return len(fake.showManifestDiffArgsForCall)
+}
+
+func (fake *FakeManifestDiffer) ShowManifestDiffArgsForCall(i int) []models.AppParams {
+	fake.showManifestDiffMutex.RLock()
+	defer fake.showManifestDiffMutex.RUnlock()
+	return fake.showManifestDiffArgsForCall[i].apps
+}
+
+var _ application.ManifestDiffer = new(FakeManifestDiffer)
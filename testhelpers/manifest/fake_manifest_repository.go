@@ -12,6 +12,14 @@ type FakeManifestRepository struct {
 		Manifest *manifest.Manifest
 		Error    error
 	}
+
+	ValidateManifestArgs struct {
+		Path string
+	}
+	ValidateManifestReturns struct {
+		Manifest *manifest.Manifest
+		Errors   []error
+	}
 }
 
 func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.Manifest, err error) {
@@ -25,3 +33,15 @@ func (repo *FakeManifestRepository) ReadManifest(inputPath string) (m *manifest.
 	err = repo.ReadManifestReturns.Error
 	return
 }
+
+func (repo *FakeManifestRepository) ValidateManifest(inputPath string) (m *manifest.Manifest, errs []error) {
+	repo.ValidateManifestArgs.Path = inputPath
+	if repo.ValidateManifestReturns.Manifest != nil {
+		m = repo.ValidateManifestReturns.Manifest
+	} else {
+		m = manifest.NewEmptyManifest()
+	}
+
+	errs = repo.ValidateManifestReturns.Errors
+	return
+}
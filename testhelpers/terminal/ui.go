@@ -17,6 +17,7 @@ type FakeUI struct {
 	Outputs                    []string
 	UncapturedOutput           []string
 	WarnOutputs                []string
+	ErrOutputs                 []string
 	Prompts                    []string
 	PasswordPrompts            []string
 	Inputs                     []string
@@ -122,6 +123,12 @@ func (ui *FakeUI) Failed(message string, args ...interface{}) {
 	return
 }
 
+func (ui *FakeUI) FailedOnStderr(message string, args ...interface{}) {
+	ui.ErrOutputs = append(ui.ErrOutputs, "FAILED")
+	ui.ErrOutputs = append(ui.ErrOutputs, strings.Split(fmt.Sprintf(message, args...), "\n")...)
+	panic(QuietPanic)
+}
+
 func (ui *FakeUI) PanicQuietly() {
 	ui.PanickedQuietly = true
 }